@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/content-services/yummy/pkg/yum"
+)
+
+// runDiff runs the diff command, returning the exit code: 0 if the repositories match, 1 if they differ
+// and 2 on errors
+func runDiff(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: yummy diff FROM TO")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	from, err := openRepository(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	to, err := openRepository(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	diff, err := yum.Diff(ctx, from, to)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	printDiff(os.Stdout, *diff)
+	if diff.Empty() {
+		return 0
+	}
+	return 1
+}
+
+// printDiff prints one line per difference, prefixed with + when added, - when removed and ~ when changed
+func printDiff(w io.Writer, diff yum.RepositoryDiff) {
+	for _, pkg := range diff.AddedPackages {
+		fmt.Fprintf(w, "+ package %v\n", pkg.NEVRA())
+	}
+	for _, pkg := range diff.RemovedPackages {
+		fmt.Fprintf(w, "- package %v\n", pkg.NEVRA())
+	}
+	for _, change := range diff.ChangedPackages {
+		fmt.Fprintf(w, "~ package %v (%v -> %v)\n", change.To.NEVRA(), change.From.Checksum.Value, change.To.Checksum.Value)
+	}
+	for _, advisory := range diff.AddedAdvisories {
+		fmt.Fprintf(w, "+ advisory %v %v\n", advisory.ID, advisory.Title)
+	}
+	for _, advisory := range diff.RemovedAdvisories {
+		fmt.Fprintf(w, "- advisory %v %v\n", advisory.ID, advisory.Title)
+	}
+	for _, change := range diff.ChangedAdvisories {
+		fmt.Fprintf(w, "~ advisory %v (updated %v -> %v)\n", change.To.ID, change.From.Updated.Date, change.To.Updated.Date)
+	}
+	for _, stream := range diff.AddedModuleStreams {
+		fmt.Fprintf(w, "+ module %v:%v:%v:%v:%v\n", stream.Name, stream.Stream, stream.Version, stream.Context, stream.Arch)
+	}
+	for _, stream := range diff.RemovedModuleStreams {
+		fmt.Fprintf(w, "- module %v:%v:%v:%v:%v\n", stream.Name, stream.Stream, stream.Version, stream.Context, stream.Arch)
+	}
+}
@@ -0,0 +1,99 @@
+// Command yummy inspects yum repositories from the command line
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/content-services/yummy/pkg/yum"
+)
+
+const usage = `Usage: yummy <command> [arguments]
+
+Commands:
+  diff FROM TO    print the packages, advisories and module streams added, removed or changed
+                  between two repositories, given as URLs or bundles written by Repository.Export
+  verify URL      check the metadata checksums and, given --gpgkey, the signature of a repository,
+                  printing a JSON report and exiting 1 when problems are found
+  serve NAME=URL  serve the packages, groups, modules and advisories of repositories over a REST API
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	var code int
+	switch os.Args[1] {
+	case "diff":
+		code = runDiff(ctx, os.Args[2:])
+	case "verify":
+		code = runVerify(ctx, os.Args[2:])
+	case "serve":
+		code = runServe(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %v\n\n%v", os.Args[1], usage)
+		code = 2
+	}
+	os.Exit(code)
+}
+
+// openRepository opens the repository at a URL or, if location is a file, the bundle in it
+func openRepository(location string) (*yum.Repository, error) {
+	if info, err := os.Stat(location); err == nil && !info.IsDir() {
+		f, err := os.Open(location)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		repo, _, err := yum.OpenBundle(f)
+		if err != nil {
+			return nil, fmt.Errorf("error opening bundle %v: %w", location, err)
+		}
+		return &repo, nil
+	}
+
+	client := &http.Client{Timeout: time.Minute}
+	repo, err := yum.NewRepository(yum.YummySettings{Client: client, URL: &location})
+	if err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// parseInterspersed parses flags given before or after positional arguments, as in "verify URL --gpgkey key"
+func parseInterspersed(flags *flag.FlagSet, args []string) error {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	return flags.Parse(positional)
+}
+
+// stringList is a flag that may be repeated
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/content-services/yummy/pkg/yum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	var gpgKeys stringList
+	flags.Var(&gpgKeys, "gpgkey", "")
+
+	require.NoError(t, parseInterspersed(flags, []string{"--gpgkey", "a.pub", "https://example.com/repo/", "--gpgkey", "b.pub"}))
+	assert.Equal(t, stringList{"a.pub", "b.pub"}, gpgKeys)
+	assert.Equal(t, []string{"https://example.com/repo/"}, flags.Args())
+}
+
+func TestPrintDiff(t *testing.T) {
+	var out bytes.Buffer
+	printDiff(&out, yum.RepositoryDiff{
+		AddedPackages:      []yum.Package{{Name: "foo", Arch: "noarch", Version: yum.Version{Version: "1.0", Release: "1"}}},
+		RemovedAdvisories:  []yum.Advisory{{ID: "RHSA-1", Title: "Important: foo"}},
+		AddedModuleStreams: []yum.Stream{{Name: "nodejs", Stream: "20", Version: "1", Context: "abc", Arch: "x86_64"}},
+	})
+	assert.Equal(t, "+ package foo-0:1.0-1.noarch\n- advisory RHSA-1 Important: foo\n+ module nodejs:20:1:abc:x86_64\n", out.String())
+}
+
+func TestWriteVerifyReport(t *testing.T) {
+	var out bytes.Buffer
+	report := yum.VerifyReport{Problems: []yum.VerifyProblem{{Type: "primary", URL: "https://example.com/repo/primary.xml.gz", StatusCode: 404, Err: errors.New("not found")}}}
+	require.NoError(t, writeVerifyReport(&out, "https://example.com/repo/", report))
+
+	assert.Contains(t, out.String(), `"statusCode"`)
+	var decoded verifyReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.False(t, decoded.OK)
+	assert.Equal(t, []verifyProblem{{Type: "primary", URL: "https://example.com/repo/primary.xml.gz", StatusCode: 404, Error: "not found"}}, decoded.Problems)
+
+	out.Reset()
+	require.NoError(t, writeVerifyReport(&out, "https://example.com/repo/", yum.VerifyReport{}))
+	assert.JSONEq(t, `{"url": "https://example.com/repo/", "ok": true, "problems": []}`, out.String())
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/content-services/yummy/pkg/server"
+	"github.com/content-services/yummy/pkg/yum"
+)
+
+// runServe runs the serve command, serving the REST API until the server fails
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := flags.String("listen", ":8080", "address to listen on")
+	cacheTTL := flags.Duration("cache-ttl", server.DefaultCacheTTL, "how long repository metadata is cached")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: yummy serve [--listen :8080] [--cache-ttl 5m] NAME=URL...")
+		flags.PrintDefaults()
+	}
+	if err := parseInterspersed(flags, args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	repos := map[string]yum.YumRepository{}
+	for _, arg := range flags.Args() {
+		name, location, ok := strings.Cut(arg, "=")
+		if !ok || name == "" || location == "" {
+			fmt.Fprintf(os.Stderr, "invalid repository %v, expected NAME=URL\n", arg)
+			return 2
+		}
+		repo, err := openRepository(location)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		repos[name] = repo
+	}
+
+	handler := server.New(repos, server.Options{CacheTTL: *cacheTTL})
+	if err := http.ListenAndServe(*listen, handler); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	return 0
+}
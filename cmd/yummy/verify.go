@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/content-services/yummy/pkg/yum"
+)
+
+// verifyReport is the JSON report printed by the verify command
+type verifyReport struct {
+	URL      string          `json:"url"`
+	OK       bool            `json:"ok"`
+	Problems []verifyProblem `json:"problems"`
+}
+
+type verifyProblem struct {
+	Type       string `json:"type"`
+	URL        string `json:"url,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error"`
+}
+
+// runVerify runs the verify command, returning the exit code: 0 if the repository verifies, 1 if problems
+// were found and 2 on errors
+func runVerify(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	var gpgKeyPaths stringList
+	flags.Var(&gpgKeyPaths, "gpgkey", "armored public key trusted to sign repomd.xml, may be repeated")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: yummy verify URL [--gpgkey key.pub]...")
+		flags.PrintDefaults()
+	}
+	if err := parseInterspersed(flags, args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	var gpgKeys []string
+	for _, path := range gpgKeyPaths {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		gpgKeys = append(gpgKeys, string(key))
+	}
+
+	repo, err := openRepository(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	repo.Configure(yum.YummySettings{GPGKeys: gpgKeys})
+
+	report := repo.Verify(ctx, nil)
+	if err = writeVerifyReport(os.Stdout, flags.Arg(0), report); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if report.OK() {
+		return 0
+	}
+	return 1
+}
+
+func writeVerifyReport(w io.Writer, url string, report yum.VerifyReport) error {
+	output := verifyReport{URL: url, OK: report.OK(), Problems: []verifyProblem{}}
+	for _, problem := range report.Problems {
+		output.Problems = append(output.Problems, verifyProblem{
+			Type:       problem.Type,
+			URL:        problem.URL,
+			StatusCode: problem.StatusCode,
+			Error:      problem.Err.Error(),
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
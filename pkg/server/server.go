@@ -0,0 +1,145 @@
+// Package server exposes the metadata of yum repositories over a small REST API, so that yummy can be
+// deployed as an introspection service rather than linked as a library
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/content-services/yummy/pkg/yum"
+)
+
+// DefaultCacheTTL is how long the metadata of a repository is served from cache before being fetched again
+const DefaultCacheTTL = 5 * time.Minute
+
+// Options configure a Server
+type Options struct {
+	// CacheTTL is how long fetched metadata is served before the repository is fetched again,
+	// DefaultCacheTTL if zero
+	CacheTTL time.Duration
+	// Logger logs the errors of failed fetches, which clients only get a generic message for, as they hold the
+	// URLs of the repositories. Defaults to slog.Default() when nil.
+	Logger *slog.Logger
+}
+
+// Server is an http.Handler serving the packages, package groups, module streams and advisories of
+// repositories as JSON, under the name each repository is configured with:
+//
+//	GET /                        names of the repositories
+//	GET /{repo}/packages         packages
+//	GET /{repo}/groups           package groups
+//	GET /{repo}/modules          module streams, grouped by module
+//	GET /{repo}/advisories       advisories, filtered by the optional type and severity query parameters
+//
+// Metadata is cached by the repositories and dropped once it is older than the cache TTL.
+type Server struct {
+	repos  map[string]*cachedRepository
+	names  []string
+	ttl    time.Duration
+	mux    *http.ServeMux
+	logger *slog.Logger
+}
+
+// cachedRepository serializes the use of a repository, which is not safe for concurrent use, and tracks
+// when its cache was last cleared
+type cachedRepository struct {
+	mutex   sync.Mutex
+	repo    yum.YumRepository
+	fetched time.Time
+}
+
+// New returns a Server for repos, keyed by the name they are served under
+func New(repos map[string]yum.YumRepository, opts Options) *Server {
+	s := &Server{repos: map[string]*cachedRepository{}, ttl: opts.CacheTTL, mux: http.NewServeMux(), logger: opts.Logger}
+	if s.ttl == 0 {
+		s.ttl = DefaultCacheTTL
+	}
+	if s.logger == nil {
+		s.logger = slog.Default()
+	}
+	for name, repo := range repos {
+		s.repos[name] = &cachedRepository{repo: repo}
+		s.names = append(s.names, name)
+	}
+	sort.Strings(s.names)
+
+	s.mux.HandleFunc("GET /{$}", s.listRepositories)
+	s.mux.HandleFunc("GET /{repo}/packages", s.handle(func(ctx context.Context, repo yum.YumRepository, _ *http.Request) (interface{}, int, error) {
+		return repo.Packages(ctx)
+	}))
+	s.mux.HandleFunc("GET /{repo}/groups", s.handle(func(ctx context.Context, repo yum.YumRepository, _ *http.Request) (interface{}, int, error) {
+		return repo.PackageGroups(ctx)
+	}))
+	s.mux.HandleFunc("GET /{repo}/modules", s.handle(func(ctx context.Context, repo yum.YumRepository, _ *http.Request) (interface{}, int, error) {
+		return repo.ModuleStreams(ctx)
+	}))
+	s.mux.HandleFunc("GET /{repo}/advisories", s.handle(func(ctx context.Context, repo yum.YumRepository, req *http.Request) (interface{}, int, error) {
+		var opts []yum.AdvisoryOption
+		query := req.URL.Query()
+		if types := query["type"]; len(types) > 0 {
+			advisoryTypes := make([]yum.AdvisoryType, 0, len(types))
+			for _, advisoryType := range types {
+				advisoryTypes = append(advisoryTypes, yum.AdvisoryType(advisoryType))
+			}
+			opts = append(opts, yum.WithType(advisoryTypes...))
+		}
+		if severity := query.Get("severity"); severity != "" {
+			opts = append(opts, yum.WithMinSeverity(yum.ParseSeverity(severity)))
+		}
+		return repo.Advisories(ctx, opts...)
+	}))
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mux.ServeHTTP(w, req)
+}
+
+func (s *Server) listRepositories(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.names)
+}
+
+// fetchFunc fetches metadata from a repository, returning it with the response code and error
+type fetchFunc func(ctx context.Context, repo yum.YumRepository, req *http.Request) (interface{}, int, error)
+
+// handle returns a handler serving the metadata fetched by fetch from the repository named in the path
+func (s *Server) handle(fetch fetchFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cached, ok := s.repos[req.PathValue("repo")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown repository "+req.PathValue("repo"))
+			return
+		}
+
+		cached.mutex.Lock()
+		if time.Since(cached.fetched) > s.ttl {
+			cached.repo.Clear()
+			cached.fetched = time.Now()
+		}
+		result, code, err := fetch(req.Context(), cached.repo, req)
+		cached.mutex.Unlock()
+
+		if err != nil {
+			s.logger.WarnContext(req.Context(), "fetch failed", "repository", req.PathValue("repo"), "path", req.URL.Path, "error", err)
+			status, message := yum.UpstreamError(code)
+			writeError(w, status, message)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
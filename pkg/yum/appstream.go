@@ -0,0 +1,157 @@
+package yum
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Application is a component from the AppStream metadata of a repository
+type Application struct {
+	ID         string            `xml:"id" json:"id"`
+	Type       string            `xml:"type,attr" json:"type"`
+	PkgName    string            `xml:"pkgname" json:"pkgName"`
+	Name       LocalizedString   `xml:"name" json:"name"`
+	Summary    LocalizedString   `xml:"summary" json:"summary"`
+	Icons      []ApplicationIcon `xml:"icon" json:"icons,omitempty"`
+	Categories []string          `xml:"categories>category" json:"categories,omitempty"`
+	URLs       []ApplicationURL  `xml:"url" json:"urls,omitempty"`
+}
+
+// ApplicationIcon refers to an icon of an application. Depending on Type, Value is a stock icon name,
+// the file name of a cached icon from the appstream-icons metadata, or the URL of a remote icon.
+type ApplicationIcon struct {
+	Type   string `xml:"type,attr" json:"type"`
+	Width  int    `xml:"width,attr" json:"width"`
+	Height int    `xml:"height,attr" json:"height"`
+	Value  string `xml:",chardata" json:"value"`
+}
+
+type ApplicationURL struct {
+	Type  string `xml:"type,attr" json:"type"`
+	Value string `xml:",chardata" json:"value"`
+}
+
+// appStreamTypes lists the repomd types AppStream metadata is published as, in order of preference
+var appStreamTypes = []string{"appstream", "appdata"}
+
+// Applications populates r.Applications with the components from the AppStream metadata of the repository.
+// Returns response code and error. Repositories without AppStream metadata return no applications.
+func (r *Repository) Applications(ctx context.Context) ([]Application, int, error) {
+	var err error
+	var appStreamURL *string
+	var resp *http.Response
+	var applications []Application
+
+	r.cacheRequest(ctx, "appstream", r.applications != nil)
+	if r.applications != nil {
+		return r.applications, 200, nil
+	}
+
+	if _, _, err = r.Repomd(ctx); err != nil {
+		return nil, 0, fmt.Errorf("error parsing repomd.xml: %w", err)
+	}
+
+	if appStreamURL, err = r.getAppStreamURL(); err != nil {
+		return nil, 0, fmt.Errorf("error parsing appstream URL: %w", err)
+	}
+
+	if appStreamURL == nil {
+		return []Application{}, 200, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *appStreamURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	if resp, err = r.do(req, "appstream"); err != nil {
+		return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", *appStreamURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("Cannot fetch %v: %d", *appStreamURL, resp.StatusCode)
+	}
+
+	if applications, err = parseAppStreamXML(r.decompressedBody(resp, "appstream"), *r.settings.MaxXmlSize); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("error parsing appstream xml: %w", err)
+	}
+	r.parsed("appstream", len(applications))
+	r.applications = applications
+
+	return applications, resp.StatusCode, nil
+}
+
+func (r *Repository) getAppStreamURL() (*string, error) {
+	var appStreamLocation Location
+
+	for _, appStreamType := range appStreamTypes {
+		for _, data := range r.repomd.Data {
+			if data.Type == appStreamType {
+				appStreamLocation = data.Location
+				break
+			}
+		}
+		if appStreamLocation.Href != "" {
+			break
+		}
+	}
+
+	if appStreamLocation.Href == "" {
+		return nil, nil
+	}
+
+	appStreamURL, err := r.getLocationURL(appStreamLocation)
+	if err != nil {
+		return nil, err
+	}
+	return &appStreamURL, nil
+}
+
+// ParseAppStreamXML creates an Application array from a compressed or uncompressed AppStream collection,
+// reading at most DefaultMaxXmlSize bytes of it
+func ParseAppStreamXML(body io.ReadCloser) ([]Application, error) {
+	return parseAppStreamXML(body, DefaultMaxXmlSize)
+}
+
+// parseAppStreamXML parses a compressed or uncompressed AppStream collection, reading at most maxSize bytes
+func parseAppStreamXML(body io.ReadCloser, maxSize int64) ([]Application, error) {
+	applications := []Application{}
+
+	reader, err := ExtractIfCompressed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxSize))
+	var element, item string
+	for {
+		t, decodeError := decoder.Token()
+
+		if decodeError == io.EOF {
+			break
+		} else if decodeError != nil {
+			return nil, fmt.Errorf("error decoding token: %w", xmlParseError(decoder, element, item, decodeError))
+		} else if t == nil {
+			break
+		}
+
+		elType, ok := t.(xml.StartElement)
+		if ok && elType.Name.Local == "component" {
+			var application Application
+			if decodeElementError := decoder.DecodeElement(&application, &elType); decodeElementError != nil {
+				return nil, xmlParseError(decoder, elType.Name.Local, cmp.Or(application.ID, item), decodeElementError)
+			}
+			item = application.ID
+			applications = append(applications, application)
+		} else if ok {
+			element = elType.Name.Local
+		}
+	}
+
+	return applications, nil
+}
@@ -0,0 +1,70 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAppStreamXML(t *testing.T) {
+	f, err := os.Open("mocks/appstream.xml.gz")
+	require.NoError(t, err)
+	defer f.Close()
+
+	applications, err := ParseAppStreamXML(f)
+	require.NoError(t, err)
+	require.Len(t, applications, 2)
+
+	gedit := applications[0]
+	assert.Equal(t, "org.gnome.gedit.desktop", gedit.ID)
+	assert.Equal(t, "desktop", gedit.Type)
+	assert.Equal(t, "gedit", gedit.PkgName)
+	assert.Equal(t, "gedit", gedit.Name.Value)
+	assert.Equal(t, "Gedit", gedit.Name.ForLocale("de"))
+	assert.Equal(t, "Edit text files", gedit.Summary.Value)
+	assert.Equal(t, "Textdateien bearbeiten", gedit.Summary.ForLocale("de_CH"))
+	assert.Equal(t, []string{"Utility", "TextEditor"}, gedit.Categories)
+	require.Len(t, gedit.Icons, 3)
+	assert.Equal(t, ApplicationIcon{Type: "cached", Width: 64, Height: 64, Value: "gedit_accessories-text-editor.png"}, gedit.Icons[1])
+	assert.Equal(t, []ApplicationURL{{Type: "homepage", Value: "https://wiki.gnome.org/Apps/Gedit"}}, gedit.URLs)
+
+	assert.Equal(t, "addon", applications[1].Type)
+	assert.Empty(t, applications[1].Icons)
+}
+
+func TestParseAppStreamXMLMaxSize(t *testing.T) {
+	f, err := os.Open("mocks/appstream.xml.gz")
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = parseAppStreamXML(f, 100)
+	assert.ErrorContains(t, err, "unexpected EOF")
+}
+
+func TestFetchApplications(t *testing.T) {
+	appStream, err := os.ReadFile("mocks/appstream.xml.gz")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<repomd><data type="appstream-icons"><location href="repodata/icons.tar.gz"/></data>` +
+			`<data type="appstream"><location href="repodata/appstream.xml.gz"/></data></repomd>`))
+	})
+	mux.HandleFunc("/repodata/appstream.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(appStream)
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	applications, code, err := r.Applications(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Len(t, applications, 2)
+	assert.Equal(t, applications, r.applications)
+}
@@ -0,0 +1,114 @@
+package yum
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	// AzureStorageResource is the resource of the tokens AzureManagedIdentityTokenSource returns
+	AzureStorageResource = "https://storage.azure.com/"
+	// azureStorageVersion is the Blob service version of the requests of AzureBlobClient, which must be
+	// 2017-11-09 or later for requests authenticated with tokens
+	azureStorageVersion = "2021-08-06"
+)
+
+// azureIMDSEndpoint is the token endpoint of the Azure Instance Metadata Service
+var azureIMDSEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// AzureBlobClient is an HTTPDoer that serves repositories from Azure Blob Storage containers, for yum mirrors
+// hosted in storage accounts. Set it as YummySettings.Client with a URL of the form
+// azblob://account/container/prefix: requests for azblob URLs are sent to the Blob service of the account,
+// authenticated with SAS if set, else with Token if set, and other requests are sent as they are.
+type AzureBlobClient struct {
+	Client   HTTPDoer    // Sends the requests, http.DefaultClient if nil
+	SAS      string      // Shared access signature, such as "sv=2021-08-06&sr=c&sig=...", added to every request
+	Token    TokenSource // Authenticates requests with Microsoft Entra ID tokens, see AzureManagedIdentityTokenSource
+	Endpoint string      // Blob service endpoint of the account, https://<account>.blob.core.windows.net if empty
+}
+
+// Do sends req, translating azblob://account/container/blob URLs to the Blob service
+func (c *AzureBlobClient) Do(req *http.Request) (*http.Response, error) {
+	client := c.Client
+	if isNilClient(client) {
+		client = http.DefaultClient
+	}
+	if req.URL.Scheme != "azblob" {
+		return client.Do(req)
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%v.blob.core.windows.net", req.URL.Host)
+	}
+	blobURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Blob service endpoint: %w", err)
+	}
+	blobURL.Path = path.Join("/", blobURL.Path, req.URL.Path)
+	blobURL.RawQuery = strings.TrimPrefix(c.SAS, "?")
+
+	blobReq := req.Clone(req.Context())
+	blobReq.URL = blobURL
+	blobReq.Host = ""
+	blobReq.Header.Set("x-ms-version", azureStorageVersion)
+	if c.SAS == "" && c.Token != nil {
+		token, err := c.Token(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("error getting Azure Storage token: %w", err)
+		}
+		blobReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	return client.Do(blobReq)
+}
+
+// AzureManagedIdentityTokenSource returns a TokenSource of Azure Storage tokens of the managed identity of the
+// virtual machine, container or App Service the program runs on. clientID selects a user-assigned identity,
+// the system-assigned identity is used if empty. App Service and Functions are detected by their
+// IDENTITY_ENDPOINT and IDENTITY_HEADER variables, other hosts use the Instance Metadata Service.
+// Tokens are requested with client and cached until they expire.
+func AzureManagedIdentityTokenSource(client HTTPDoer, clientID string) TokenSource {
+	if isNilClient(client) {
+		client = http.DefaultClient
+	}
+	return cachingTokenSource(func(ctx context.Context) (accessToken, error) {
+		req, err := azureManagedIdentityRequest(ctx, clientID)
+		if err != nil {
+			return accessToken{}, err
+		}
+		return fetchOAuthToken(client, req)
+	})
+}
+
+// azureManagedIdentityRequest returns the token request of the managed identity endpoint of the host
+func azureManagedIdentityRequest(ctx context.Context, clientID string) (*http.Request, error) {
+	query := url.Values{"resource": {AzureStorageResource}}
+	if clientID != "" {
+		query.Set("client_id", clientID)
+	}
+
+	endpoint, identityHeader := os.Getenv("IDENTITY_ENDPOINT"), os.Getenv("IDENTITY_HEADER")
+	appService := endpoint != "" && identityHeader != ""
+	if appService {
+		query.Set("api-version", "2019-08-01")
+	} else {
+		endpoint = azureIMDSEndpoint
+		query.Set("api-version", "2018-02-01")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if appService {
+		req.Header.Set("X-IDENTITY-HEADER", identityHeader)
+	} else {
+		req.Header.Set("Metadata", "true")
+	}
+	return req, nil
+}
@@ -0,0 +1,122 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// azureServer serves the mock repository from the repo/epel7 prefix of the mirrors container of devstoreaccount1
+func azureServer(t *testing.T, authorized func(*http.Request) bool) *httptest.Server {
+	backend := server()
+	t.Cleanup(backend.Close)
+	backendURL, _ := url.Parse(backend.URL)
+
+	azure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-ms-version") == "" || !authorized(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		r.URL.RawQuery = ""
+		http.StripPrefix("/devstoreaccount1/mirrors/repo/epel7", httputil.NewSingleHostReverseProxy(backendURL)).ServeHTTP(w, r)
+	}))
+	t.Cleanup(azure.Close)
+	return azure
+}
+
+func TestAzureBlobClientSAS(t *testing.T) {
+	azure := azureServer(t, func(r *http.Request) bool {
+		return r.URL.Query().Get("sig") == "signature" && r.Header.Get("Authorization") == ""
+	})
+
+	client := &AzureBlobClient{SAS: "?sv=2021-08-06&sr=c&sig=signature", Token: StaticToken("unused"), Endpoint: azure.URL + "/devstoreaccount1"}
+	r, _ := NewRepository(YummySettings{Client: client, URL: Ptr("azblob://devstoreaccount1/mirrors/repo/epel7")})
+
+	packages, code, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Len(t, packages, 2)
+
+	client.SAS = "sv=2021-08-06&sr=c&sig=expired"
+	r.Clear()
+	_, code, err = r.Repomd(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestAzureBlobClientToken(t *testing.T) {
+	azure := azureServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer azure-token"
+	})
+
+	client := &AzureBlobClient{Token: StaticToken("azure-token"), Endpoint: azure.URL + "/devstoreaccount1"}
+	r, _ := NewRepository(YummySettings{Client: client, URL: Ptr("azblob://devstoreaccount1/mirrors/repo/epel7")})
+
+	_, code, err := r.Repomd(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+}
+
+func TestAzureBlobClientEndpoint(t *testing.T) {
+	var requested *http.Request
+	doer := &handlerDoer{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r
+	})}
+	client := &AzureBlobClient{Client: doer}
+
+	req, _ := http.NewRequest(http.MethodGet, "azblob://mirrorstore/mirrors/epel7/repodata/repomd.xml", nil)
+	_, err := client.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, "https://mirrorstore.blob.core.windows.net/mirrors/epel7/repodata/repomd.xml", requested.URL.String())
+
+	req, _ = http.NewRequest(http.MethodGet, "https://example.com/RPM-GPG-KEY", nil)
+	_, err = client.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/RPM-GPG-KEY", requested.URL.String())
+	assert.Empty(t, requested.Header.Get("x-ms-version"))
+}
+
+func TestAzureManagedIdentityTokenSource(t *testing.T) {
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, AzureStorageResource, r.URL.Query().Get("resource"))
+		assert.Equal(t, "user-assigned", r.URL.Query().Get("client_id"))
+		_, _ = w.Write([]byte(`{"access_token": "imds-token", "expires_in": "86399", "token_type": "Bearer"}`))
+	}))
+	defer imds.Close()
+	defer func(endpoint string) { azureIMDSEndpoint = endpoint }(azureIMDSEndpoint)
+	azureIMDSEndpoint = imds.URL
+	t.Setenv("IDENTITY_ENDPOINT", "")
+	t.Setenv("IDENTITY_HEADER", "")
+
+	token, err := AzureManagedIdentityTokenSource(nil, "user-assigned")(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "imds-token", token)
+}
+
+func TestAzureAppServiceTokenSource(t *testing.T) {
+	identity := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-IDENTITY-HEADER") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, "2019-08-01", r.URL.Query().Get("api-version"))
+		_, _ = w.Write([]byte(`{"access_token": "app-service-token", "expires_in": "86399"}`))
+	}))
+	defer identity.Close()
+	t.Setenv("IDENTITY_ENDPOINT", identity.URL)
+	t.Setenv("IDENTITY_HEADER", "secret")
+
+	token, err := AzureManagedIdentityTokenSource(nil, "")(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "app-service-token", token)
+}
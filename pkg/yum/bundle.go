@@ -0,0 +1,219 @@
+package yum
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// bundleManifestName is the name of the manifest in a bundle
+const bundleManifestName = "manifest.json"
+
+// BundleManifest describes the repository metadata in a bundle created by Repository.Export
+type BundleManifest struct {
+	URL      string       `json:"url"` // URL of the repository, without credentials or query
+	Revision string       `json:"revision"`
+	Created  time.Time    `json:"created"`
+	Files    []BundleFile `json:"files"`
+}
+
+// BundleFile is a file in a bundle, with its path relative to the repository URL
+type BundleFile struct {
+	Path         string `json:"path"`
+	ChecksumType string `json:"checksumType,omitempty"`
+	Checksum     string `json:"checksum,omitempty"`
+	Size         int64  `json:"size"`
+}
+
+// Export writes a zstd compressed tar bundle of the repository metadata to w: repomd.xml, its signature if
+// there is one, every metadata file it references, verified against its checksum, and a manifest.json
+// describing the bundle. OpenBundle reads the bundle back, for moving metadata across an air gap.
+func (r *Repository) Export(ctx context.Context, w io.Writer) error {
+	repomd, _, err := r.Repomd(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching repomd.xml: %w", err)
+	}
+
+	manifest := BundleManifest{URL: strippedURL(r.repositoryURL()), Revision: repomd.Revision, Created: time.Now().UTC()}
+	files := map[string][]byte{"repodata/repomd.xml": []byte(*repomd.RepomdString)}
+	manifest.Files = append(manifest.Files, BundleFile{Path: "repodata/repomd.xml", Size: int64(len(*repomd.RepomdString))})
+
+	if sig, _, sigErr := r.Signature(ctx); sigErr == nil {
+		files["repodata/repomd.xml.asc"] = []byte(*sig)
+		manifest.Files = append(manifest.Files, BundleFile{Path: "repodata/repomd.xml.asc", Size: int64(len(*sig))})
+	}
+
+	for _, file := range metadataSyncFiles(repomd) {
+		var content bytes.Buffer
+		if _, _, err = r.downloadVerified(ctx, file.location, file.checksum, file.size, &content); err != nil {
+			return err
+		}
+		files[file.location.Href] = content.Bytes()
+		manifest.Files = append(manifest.Files, BundleFile{
+			Path:         file.location.Href,
+			ChecksumType: file.checksum.Type,
+			Checksum:     file.checksum.Value,
+			Size:         int64(content.Len()),
+		})
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	encoder, err := zstd.NewWriter(w)
+	if err != nil {
+		return err
+	}
+	tarWriter := tar.NewWriter(encoder)
+	if err = writeBundleFile(tarWriter, bundleManifestName, manifestJSON, manifest.Created); err != nil {
+		return err
+	}
+	for _, file := range manifest.Files {
+		if err = writeBundleFile(tarWriter, file.Path, files[file.Path], manifest.Created); err != nil {
+			return err
+		}
+	}
+	if err = tarWriter.Close(); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func writeBundleFile(tarWriter *tar.Writer, name string, content []byte, modified time.Time) error {
+	header := tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), ModTime: modified, Typeflag: tar.TypeReg}
+	if err := tarWriter.WriteHeader(&header); err != nil {
+		return fmt.Errorf("error writing %v to bundle: %w", name, err)
+	}
+	if _, err := tarWriter.Write(content); err != nil {
+		return fmt.Errorf("error writing %v to bundle: %w", name, err)
+	}
+	return nil
+}
+
+// strippedURL returns rawURL without its userinfo, query and fragment, which may hold credentials or signed
+// tokens that must not leave with a bundle, or an empty string if it cannot be parsed
+func strippedURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery, u.ForceQuery, u.Fragment, u.RawFragment = "", false, "", ""
+	return u.String()
+}
+
+// OpenBundle reads a bundle created by Repository.Export into memory, returning a Repository that serves the
+// bundled metadata without network access, along with the bundle's manifest. Files are checked against the
+// manifest as they are read.
+func OpenBundle(reader io.Reader) (Repository, BundleManifest, error) {
+	var manifest BundleManifest
+
+	decoder, err := zstd.NewReader(reader)
+	if err != nil {
+		return Repository{}, manifest, fmt.Errorf("error reading bundle: %w", err)
+	}
+	defer decoder.Close()
+
+	files := map[string][]byte{}
+	tarReader := tar.NewReader(decoder)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return Repository{}, manifest, fmt.Errorf("error reading bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return Repository{}, manifest, fmt.Errorf("error reading %v from bundle: %w", header.Name, err)
+		}
+		files[path.Clean(header.Name)] = content
+	}
+
+	manifestJSON, ok := files[bundleManifestName]
+	if !ok {
+		return Repository{}, manifest, fmt.Errorf("bundle has no %v", bundleManifestName)
+	}
+	if err = json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return Repository{}, manifest, fmt.Errorf("error parsing %v: %w", bundleManifestName, err)
+	}
+
+	bundled := map[string][]byte{}
+	for _, file := range manifest.Files {
+		content, ok := files[path.Clean(file.Path)]
+		if !ok {
+			return Repository{}, manifest, fmt.Errorf("bundle is missing %v", file.Path)
+		}
+		if int64(len(content)) != file.Size {
+			return Repository{}, manifest, fmt.Errorf("size mismatch for %v: expected %d, got %d", file.Path, file.Size, len(content))
+		}
+		if file.ChecksumType != "" {
+			hash, err := newChecksumHash(file.ChecksumType)
+			if err != nil {
+				return Repository{}, manifest, err
+			}
+			hash.Write(content)
+			if sum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(sum, file.Checksum) {
+				return Repository{}, manifest, fmt.Errorf("checksum mismatch for %v: expected %v, got %v", file.Path, file.Checksum, sum)
+			}
+		}
+		bundled[path.Clean(file.Path)] = content
+	}
+
+	repoURL, err := url.Parse(manifest.URL)
+	if err != nil {
+		return Repository{}, manifest, fmt.Errorf("error parsing bundle URL: %w", err)
+	}
+	client := &http.Client{Transport: bundleTransport{basePath: repoURL.Path, files: bundled}}
+	repo, err := NewRepository(YummySettings{Client: client, URL: &manifest.URL})
+	return repo, manifest, err
+}
+
+// bundleTransport answers requests for the files of a bundle, by path relative to the repository URL
+type bundleTransport struct {
+	basePath string
+	files    map[string][]byte
+}
+
+func (t bundleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	response := http.Response{
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    req,
+	}
+
+	relative := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(req.URL.Path, t.basePath)), "/")
+	content, ok := t.files[relative]
+	if !ok || req.Method != http.MethodGet {
+		response.StatusCode = http.StatusNotFound
+		response.Status = "404 Not Found"
+		response.Body = io.NopCloser(strings.NewReader(""))
+		return &response, nil
+	}
+	response.StatusCode = http.StatusOK
+	response.Status = "200 OK"
+	response.ContentLength = int64(len(content))
+	response.Body = io.NopCloser(bytes.NewReader(content))
+	return &response, nil
+}
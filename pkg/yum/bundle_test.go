@@ -0,0 +1,42 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAndOpenBundle(t *testing.T) {
+	s := syncServer(t)
+	// credentials and signed tokens are not written to the manifest
+	url := strings.Replace(s.URL, "http://", "http://user:secret@", 1) + "/?sig=token"
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &url})
+
+	var bundle bytes.Buffer
+	require.NoError(t, r.Export(context.Background(), &bundle))
+	// the bundle can be read without the repository
+	s.Close()
+
+	imported, manifest, err := OpenBundle(bytes.NewReader(bundle.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, s.URL+"/", manifest.URL)
+	require.Len(t, manifest.Files, 2)
+	assert.Equal(t, "repodata/repomd.xml", manifest.Files[0].Path)
+	assert.Equal(t, "sha256", manifest.Files[1].ChecksumType)
+
+	packages, code, err := imported.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Len(t, packages, 2)
+
+	_, code, err = imported.Signature(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 404, code)
+
+	_, _, err = OpenBundle(bytes.NewReader([]byte("not a bundle")))
+	assert.Error(t, err)
+}
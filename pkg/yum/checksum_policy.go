@@ -0,0 +1,100 @@
+package yum
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ChecksumPolicy is how a repository treats metadata files and packages whose checksums use weak digests,
+// md5 or sha1, which FIPS-compliant environments cannot rely on
+type ChecksumPolicy int
+
+const (
+	ChecksumPolicyAllow  ChecksumPolicy = iota // Weak checksums are accepted
+	ChecksumPolicyWarn                         // Weak checksums are logged as warnings
+	ChecksumPolicyReject                       // Repomd and Packages fail with a *WeakChecksumError
+)
+
+// IsWeakChecksumType reports whether checksumType is a weak digest, md5 or sha1. "sha" is yum's name of sha1.
+func IsWeakChecksumType(checksumType string) bool {
+	switch strings.ToLower(checksumType) {
+	case "md5", "sha", "sha1":
+		return true
+	default:
+		return false
+	}
+}
+
+// WeakChecksum is a metadata file or package whose checksum uses a weak digest
+type WeakChecksum struct {
+	MetadataType string `json:"metadataType,omitempty"` // Type of the metadata file in repomd.xml, empty for a package
+	Package      string `json:"package,omitempty"`      // NEVRA of the package, empty for a metadata file
+	Location     string `json:"location"`               // Href of the metadata file or package
+	ChecksumType string `json:"checksumType"`           // Weak digest of the checksum
+}
+
+// WeakChecksumError is returned when ChecksumPolicyReject rejects metadata with weak checksums
+type WeakChecksumError struct {
+	Checksums []WeakChecksum
+}
+
+func (e *WeakChecksumError) Error() string {
+	first := e.Checksums[0]
+	return fmt.Sprintf("%d weak checksums, such as %v of %v", len(e.Checksums), first.ChecksumType, first.Location)
+}
+
+// weakRepomdChecksums returns the metadata files of repomd whose checksum or open checksum is weak
+func weakRepomdChecksums(repomd Repomd) []WeakChecksum {
+	weak := []WeakChecksum{}
+	for _, data := range repomd.Data {
+		for _, checksum := range []Checksum{data.Checksum, data.OpenChecksum} {
+			if IsWeakChecksumType(checksum.Type) {
+				weak = append(weak, WeakChecksum{MetadataType: data.Type, Location: data.Location.Href, ChecksumType: checksum.Type})
+				break
+			}
+		}
+	}
+	return weak
+}
+
+// weakPackageChecksums returns the packages whose checksum is weak
+func weakPackageChecksums(packages []Package) []WeakChecksum {
+	weak := []WeakChecksum{}
+	for _, pkg := range packages {
+		if IsWeakChecksumType(pkg.Checksum.Type) {
+			weak = append(weak, WeakChecksum{Package: pkg.NEVRA().String(), Location: pkg.Location.Href, ChecksumType: pkg.Checksum.Type})
+		}
+	}
+	return weak
+}
+
+// enforceChecksumPolicy logs or rejects the weak checksums found in metadataType, as the ChecksumPolicy
+// setting says
+func (r *Repository) enforceChecksumPolicy(ctx context.Context, metadataType string, weak []WeakChecksum) error {
+	if len(weak) == 0 {
+		return nil
+	}
+	switch r.settings.ChecksumPolicy {
+	case ChecksumPolicyWarn:
+		r.logger().WarnContext(ctx, "weak checksums", "url", redactURL(r.repositoryURL()), "type", metadataType, "count", len(weak), "checksumType", weak[0].ChecksumType)
+	case ChecksumPolicyReject:
+		return &WeakChecksumError{Checksums: weak}
+	}
+	return nil
+}
+
+// WeakChecksums returns the metadata files of repomd.xml and the packages of primary.xml whose checksums use
+// a weak digest, md5 or sha1, whatever the ChecksumPolicy. With ChecksumPolicyReject, the error is a
+// *WeakChecksumError listing the weak checksums of the first file rejected.
+func (r *Repository) WeakChecksums(ctx context.Context) ([]WeakChecksum, int, error) {
+	repomd, code, err := r.Repomd(ctx)
+	if err != nil {
+		return nil, code, err
+	}
+	packages, code, err := r.Packages(ctx)
+	if err != nil {
+		return nil, code, err
+	}
+	return append(weakRepomdChecksums(*repomd), weakPackageChecksums(packages)...), code, nil
+}
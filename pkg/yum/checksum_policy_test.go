@@ -0,0 +1,56 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeakChecksums(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	// the packages of the mock primary.xml have sha1 checksums
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	weak, _, err := r.WeakChecksums(context.Background())
+	require.NoError(t, err)
+	require.Len(t, weak, 2)
+	assert.Equal(t, "sha1", weak[0].ChecksumType)
+	assert.NotEmpty(t, weak[0].Package)
+	assert.NotEmpty(t, weak[0].Location)
+	assert.Empty(t, weak[0].MetadataType)
+
+	var buf bytes.Buffer
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, ChecksumPolicy: ChecksumPolicyWarn, Logger: slog.New(slog.NewTextHandler(&buf, nil))})
+	packages, _, err := r.Packages(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, packages, 2)
+	assert.Contains(t, buf.String(), "weak checksums")
+
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, ChecksumPolicy: ChecksumPolicyReject})
+	_, _, err = r.Repomd(context.Background())
+	assert.NoError(t, err)
+	packages, _, err = r.Packages(context.Background())
+	var weakErr *WeakChecksumError
+	require.ErrorAs(t, err, &weakErr)
+	assert.Len(t, weakErr.Checksums, 2)
+	assert.Nil(t, packages)
+}
+
+func TestWeakRepomdChecksums(t *testing.T) {
+	repomd := Repomd{Data: []Data{
+		{Type: "primary", Location: Location{Href: "repodata/primary.xml.gz"}, Checksum: Checksum{Type: "sha256"}, OpenChecksum: Checksum{Type: "sha256"}},
+		{Type: "filelists", Location: Location{Href: "repodata/filelists.xml.gz"}, Checksum: Checksum{Type: "sha256"}, OpenChecksum: Checksum{Type: "md5"}},
+		{Type: "other", Location: Location{Href: "repodata/other.xml.gz"}, Checksum: Checksum{Type: "sha"}, OpenChecksum: Checksum{Type: "sha"}},
+	}}
+	assert.Equal(t, []WeakChecksum{
+		{MetadataType: "filelists", Location: "repodata/filelists.xml.gz", ChecksumType: "md5"},
+		{MetadataType: "other", Location: "repodata/other.xml.gz", ChecksumType: "sha"},
+	}, weakRepomdChecksums(repomd))
+	assert.False(t, IsWeakChecksumType("SHA256"))
+	assert.True(t, IsWeakChecksumType("SHA1"))
+}
@@ -0,0 +1,124 @@
+package yum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// trailingDataLimit is the number of bytes left after the parsed content of a metadata file, such as the
+// whitespace after the root element of an XML document, that are read to complete its checksums
+const trailingDataLimit = 64 * 1024
+
+// MetadataChecksums are the sha256 checksums of the last metadata file fetched for a type of metadata,
+// computed while it was read, to compare with the checksums of repomd.xml or to use as cache keys
+type MetadataChecksums struct {
+	Checksum     Checksum `json:"checksum"`     // Checksum of the file as downloaded, as the checksum in repomd.xml
+	OpenChecksum Checksum `json:"openChecksum"` // Checksum of the decompressed file, as the open-checksum in repomd.xml
+}
+
+// streamChecksums computes the MetadataChecksums of the files fetched, by metadata type. Every method is a
+// no-op on a nil *streamChecksums.
+type streamChecksums struct {
+	mu      sync.Mutex
+	byType  map[string]*MetadataChecksums
+	pending map[string]*hashingReader // Decompressed content being read, by metadata type
+}
+
+func newStreamChecksums() *streamChecksums {
+	return &streamChecksums{byType: map[string]*MetadataChecksums{}, pending: map[string]*hashingReader{}}
+}
+
+// observeResponse starts the checksums of a successful response, hashing its body as it is read
+func (c *streamChecksums) observeResponse(metadataType string, resp *http.Response, err error) {
+	if c == nil || err != nil || resp.StatusCode != http.StatusOK {
+		return
+	}
+	checksums := &MetadataChecksums{}
+	c.mu.Lock()
+	c.byType[metadataType] = checksums
+	delete(c.pending, metadataType)
+	c.mu.Unlock()
+
+	body := resp.Body
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{c.hashing(body, &checksums.Checksum), body}
+}
+
+// decompressed returns a reader hashing reader, which holds decompressed metadata, as it is read
+func (c *streamChecksums) decompressed(metadataType string, reader io.Reader) io.Reader {
+	if c == nil {
+		return reader
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	checksums, ok := c.byType[metadataType]
+	if !ok {
+		return reader
+	}
+	hashing := c.hashing(reader, &checksums.OpenChecksum)
+	c.pending[metadataType] = hashing
+	return hashing
+}
+
+// hashing returns a reader hashing reader, which sets checksum once it is read to its end
+func (c *streamChecksums) hashing(reader io.Reader, checksum *Checksum) *hashingReader {
+	return &hashingReader{reader: reader, hash: sha256.New(), done: func(sum []byte) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		*checksum = Checksum{Type: "sha256", Value: hex.EncodeToString(sum)}
+	}}
+}
+
+// parsed completes the checksums of metadata whose content was parsed, reading what trails the content
+func (c *streamChecksums) parsed(metadataType string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	hashing, ok := c.pending[metadataType]
+	delete(c.pending, metadataType)
+	c.mu.Unlock()
+	if ok {
+		_, _ = io.CopyN(io.Discard, hashing, trailingDataLimit)
+	}
+}
+
+// hashingReader hashes the data read from reader, calling done with the sum once reader is at its end
+type hashingReader struct {
+	reader io.Reader
+	hash   hash.Hash
+	done   func(sum []byte)
+}
+
+func (h *hashingReader) Read(p []byte) (int, error) {
+	n, err := h.reader.Read(p)
+	h.hash.Write(p[:n])
+	if err == io.EOF && h.done != nil {
+		h.done(h.hash.Sum(nil))
+		h.done = nil
+	}
+	return n, err
+}
+
+// Checksums returns the sha256 checksums of the last metadata file fetched for a type of metadata, such as
+// "primary", computed while it was read. They are kept across calls to Clear. A checksum is empty if the
+// file was not read to its end, as when MaxPackages truncates primary.xml. Returns false if no file was
+// fetched yet.
+func (r *Repository) Checksums(metadataType string) (MetadataChecksums, bool) {
+	if r.checksums == nil {
+		return MetadataChecksums{}, false
+	}
+	r.checksums.mu.Lock()
+	defer r.checksums.mu.Unlock()
+	checksums, ok := r.checksums.byType[metadataType]
+	if !ok {
+		return MetadataChecksums{}, false
+	}
+	return *checksums, true
+}
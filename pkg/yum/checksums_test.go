@@ -0,0 +1,50 @@
+package yum
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksums(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	_, ok := r.Checksums("primary")
+	assert.False(t, ok)
+
+	_, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	gzipReader, err := gzip.NewReader(bytes.NewReader(primaryXML))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(gzipReader)
+	require.NoError(t, err)
+
+	primary, ok := r.Checksums("primary")
+	assert.True(t, ok)
+	assert.Equal(t, Checksum{Type: "sha256", Value: sha256Hex(primaryXML)}, primary.Checksum)
+	assert.Equal(t, Checksum{Type: "sha256", Value: sha256Hex(decompressed)}, primary.OpenChecksum)
+
+	// repomd.xml is not compressed, and read to its end without being decompressed
+	repomd, ok := r.Checksums("repomd")
+	assert.True(t, ok)
+	assert.Equal(t, sha256Hex(repomdXML), repomd.Checksum.Value)
+
+	_, _, err = r.PackageGroups(context.Background())
+	require.NoError(t, err)
+	group, ok := r.Checksums("group")
+	assert.True(t, ok)
+	assert.Equal(t, sha256Hex(compsXML), group.Checksum.Value)
+	assert.Equal(t, group.Checksum, group.OpenChecksum)
+
+	// checksums are kept across Clear
+	r.Clear()
+	_, ok = r.Checksums("primary")
+	assert.True(t, ok)
+}
@@ -0,0 +1,93 @@
+package yum
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped, for requests refused by an open CircuitBreaker
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitBreaker stops requests to a repository after repeated failures, so that bulk jobs do not keep
+// hammering a dead mirror. It opens when Failures requests fail within Window, refusing requests with
+// ErrCircuitOpen until Cooldown has elapsed. A single request is then let through: the breaker closes if it
+// succeeds, and opens for another Cooldown if it fails. Requests fail with transport errors, 5xx responses
+// and 429 Too Many Requests. A CircuitBreaker is safe for concurrent use and can be shared by the
+// repositories of a mirror.
+type CircuitBreaker struct {
+	Failures int
+	Window   time.Duration
+	Cooldown time.Duration
+
+	mu        sync.Mutex
+	failures  []time.Time // Times of the failures within Window, oldest first
+	openUntil time.Time   // End of the cooldown if the breaker is open
+	trial     bool        // Whether the request let through after the cooldown is in flight
+	now       func() time.Time
+}
+
+// NewCircuitBreaker returns a circuit breaker opening after failures failed requests within window, for cooldown
+func NewCircuitBreaker(failures int, window, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Failures: failures, Window: window, Cooldown: cooldown}
+}
+
+func (b *CircuitBreaker) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
+
+// allow returns ErrCircuitOpen, wrapped, if a request may not be sent now
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return nil
+	}
+	if now := b.clock(); now.Before(b.openUntil) || b.trial {
+		retryAt := b.openUntil
+		if retryAt.Before(now) {
+			retryAt = now
+		}
+		return fmt.Errorf("%w: retry after %v", ErrCircuitOpen, retryAt.Format(time.RFC3339))
+	}
+	b.trial = true
+	return nil
+}
+
+// record records the outcome of a request that was allowed
+func (b *CircuitBreaker) record(resp *http.Response, err error) {
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock()
+	if b.trial {
+		b.trial = false
+		if failed {
+			b.openUntil = now.Add(b.Cooldown)
+		} else {
+			b.openUntil = time.Time{}
+			b.failures = nil
+		}
+		return
+	}
+	if !failed {
+		return
+	}
+
+	b.failures = append(b.failures, now)
+	for len(b.failures) > 0 && now.Sub(b.failures[0]) > b.Window {
+		b.failures = b.failures[1:]
+	}
+	if len(b.failures) >= b.Failures {
+		b.openUntil = now.Add(b.Cooldown)
+		b.failures = nil
+	}
+}
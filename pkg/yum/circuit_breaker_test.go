@@ -0,0 +1,83 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	failing := true
+	requests := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		serveRepomdXML(w, r)
+	}))
+	defer s.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(3, time.Minute, 5*time.Minute)
+	breaker.now = func() time.Time { return now }
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, CircuitBreaker: breaker})
+
+	// failures outside of the window do not open the breaker
+	for i := 0; i < 3; i++ {
+		_, code, err := r.Repomd(context.Background())
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+		now = now.Add(40 * time.Second)
+	}
+	assert.Equal(t, 3, requests)
+
+	// three failures within the window open it
+	_, _, _ = r.Repomd(context.Background())
+	_, _, _ = r.Repomd(context.Background())
+	_, code, err := r.Repomd(context.Background())
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, 5, requests)
+
+	// a failed trial after the cooldown opens it again
+	now = now.Add(5 * time.Minute)
+	_, code, _ = r.Repomd(context.Background())
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	_, _, err = r.Repomd(context.Background())
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 6, requests)
+
+	// a successful trial closes it
+	failing = false
+	now = now.Add(5 * time.Minute)
+	_, code, err = r.Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	r.Clear()
+	_, _, err = r.Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 8, requests)
+}
+
+func TestCircuitBreakerTrial(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(1, time.Minute, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	assert.NoError(t, breaker.allow())
+	breaker.record(nil, http.ErrHandlerTimeout)
+	assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen)
+
+	// only one request is let through while the trial is in flight
+	now = now.Add(time.Minute)
+	assert.NoError(t, breaker.allow())
+	assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen)
+	breaker.record(&http.Response{StatusCode: http.StatusNotFound}, nil)
+	assert.NoError(t, breaker.allow())
+}
@@ -0,0 +1,126 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CloneReport is the result of Repository.CloneTo: what was downloaded, and every problem found while
+// verifying the clone on disk
+type CloneReport struct {
+	SyncResult
+	VerifyReport
+	Verified int `json:"verified"` // files on disk that matched their checksum
+}
+
+// CloneTo mirrors the repository into dir like Sync, then verifies the clone end to end rather than trusting
+// the transfer: repomd.xml on disk must match the source, every metadata file must match its checksum and size
+// in that repomd.xml, and every package selected by opts.Filter must match its checksum and size in the
+// primary.xml on disk. Problems are reported with the local path of the file as URL. The returned error is
+// either the error of the sync, in which case nothing was verified, or the error of the verification report.
+func (r *Repository) CloneTo(ctx context.Context, dir string, opts SyncOptions) (CloneReport, error) {
+	var report CloneReport
+	var err error
+
+	if report.SyncResult, err = r.Sync(ctx, dir, opts); err != nil {
+		return report, err
+	}
+
+	repomd, problem := r.verifyCloneRepomd(dir)
+	if problem != nil {
+		report.Problems = append(report.Problems, *problem)
+		return report, report.Err()
+	}
+	report.Verified++
+
+	var primary *Data
+	for i, data := range repomd.Data {
+		if data.Type == "primary" {
+			primary = &repomd.Data[i]
+		}
+		if problem := verifyCloneFile(dir, data.Type, data.Location, data.Checksum, data.Size); problem != nil {
+			report.Problems = append(report.Problems, *problem)
+		} else {
+			report.Verified++
+		}
+	}
+	if primary == nil {
+		return report, report.Err()
+	}
+
+	packages, problem := r.parseClonePrimary(dir, *primary)
+	if problem != nil {
+		report.Problems = append(report.Problems, *problem)
+		return report, report.Err()
+	}
+	for _, pkg := range packages {
+		if opts.Filter != nil && !opts.Filter(pkg) {
+			continue
+		}
+		if problem := verifyCloneFile(dir, "package", pkg.Location, pkg.Checksum, pkg.Size.Package); problem != nil {
+			report.Problems = append(report.Problems, *problem)
+		} else {
+			report.Verified++
+		}
+	}
+	return report, report.Err()
+}
+
+// verifyCloneRepomd reads repomd.xml from dir and checks that it is the repomd.xml of the repository
+func (r *Repository) verifyCloneRepomd(dir string) (*Repomd, *VerifyProblem) {
+	path, err := syncPath(dir, "repodata/repomd.xml")
+	if err != nil {
+		return nil, &VerifyProblem{Type: "repomd", Err: err}
+	}
+	problem := VerifyProblem{Type: "repomd", URL: path}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		problem.Err = err
+		return nil, &problem
+	}
+	if !bytes.Equal(content, []byte(*r.repomd.RepomdString)) {
+		problem.Err = errors.New("repomd.xml does not match the source repository")
+		return nil, &problem
+	}
+	repomd, err := DecodeRepomdXML(bytes.NewReader(content))
+	if err != nil {
+		problem.Err = fmt.Errorf("error parsing repomd.xml: %w", err)
+		return nil, &problem
+	}
+	return &repomd, nil
+}
+
+// parseClonePrimary parses the primary.xml in dir
+func (r *Repository) parseClonePrimary(dir string, primary Data) ([]Package, *VerifyProblem) {
+	path, err := syncPath(dir, primary.Location.Href)
+	if err != nil {
+		return nil, &VerifyProblem{Type: primary.Type, Err: err}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, &VerifyProblem{Type: primary.Type, URL: path, Err: err}
+	}
+	defer f.Close()
+
+	packages, err := ParseCompressedXMLData(f, *r.settings.MaxXmlSize)
+	if err != nil {
+		return nil, &VerifyProblem{Type: primary.Type, URL: path, Err: fmt.Errorf("error parsing primary.xml: %w", err)}
+	}
+	return packages, nil
+}
+
+// verifyCloneFile checks that the file of location in dir has the given checksum and, if not zero, size
+func verifyCloneFile(dir string, fileType string, location Location, checksum Checksum, size int64) *VerifyProblem {
+	path, err := syncPath(dir, location.Href)
+	if err != nil {
+		return &VerifyProblem{Type: fileType, Err: err}
+	}
+	if !fileMatches(path, checksum, size) {
+		return &VerifyProblem{Type: fileType, URL: path, Err: fmt.Errorf("checksum or size mismatch for %v", location.Href)}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package yum
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneTo(t *testing.T) {
+	s := syncServer(t)
+	defer s.Close()
+	dir := t.TempDir()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	report, err := r.CloneTo(context.Background(), dir, SyncOptions{})
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.Equal(t, 3, report.Downloaded)
+	// repomd.xml, primary.xml.gz and both packages
+	assert.Equal(t, 4, report.Verified)
+
+	filtered := t.TempDir()
+	report, err = r.CloneTo(context.Background(), filtered, SyncOptions{Filter: func(pkg Package) bool { return pkg.Name == "foo" }})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Verified)
+	assert.NoFileExists(t, filepath.Join(filtered, "Packages", "bar-1.0-1.x86_64.rpm"))
+}
+
+func TestCloneToSyncError(t *testing.T) {
+	s := syncServer(t)
+	s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	report, err := r.CloneTo(context.Background(), t.TempDir(), SyncOptions{})
+	assert.Error(t, err)
+	assert.Zero(t, report.Verified)
+}
+
+func TestVerifyCloneFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.rpm"), []byte("foo rpm"), 0644))
+	checksum := Checksum{Type: "sha256", Value: sha256Hex([]byte("foo rpm"))}
+
+	assert.Nil(t, verifyCloneFile(dir, "package", Location{Href: "foo.rpm"}, checksum, 7))
+
+	problem := verifyCloneFile(dir, "package", Location{Href: "foo.rpm"}, checksum, 8)
+	require.NotNil(t, problem)
+	assert.Equal(t, filepath.Join(dir, "foo.rpm"), problem.URL)
+
+	problem = verifyCloneFile(dir, "package", Location{Href: "bar.rpm"}, checksum, 0)
+	require.NotNil(t, problem)
+
+	problem = verifyCloneFile(dir, "package", Location{Href: "../foo.rpm"}, checksum, 0)
+	require.NotNil(t, problem)
+	assert.Error(t, problem.Err)
+}
@@ -0,0 +1,69 @@
+package yum
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WritePackagesCSV writes packages to w as CSV with a header row, one row per package with its NEVRA,
+// checksum, summary and sizes, for opening repository inventories in spreadsheets
+func WritePackagesCSV(w io.Writer, packages []Package) error {
+	writer := csv.NewWriter(w)
+	header := []string{"name", "epoch", "version", "release", "arch", "checksum_type", "checksum", "summary", "size", "installed_size", "location"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, pkg := range packages {
+		record := []string{
+			pkg.Name,
+			strconv.FormatInt(int64(pkg.Version.Epoch), 10),
+			pkg.Version.Version,
+			pkg.Version.Release,
+			pkg.Arch,
+			pkg.Checksum.Type,
+			pkg.Checksum.Value,
+			pkg.Summary,
+			strconv.FormatInt(pkg.Size.Package, 10),
+			strconv.FormatInt(pkg.Size.Installed, 10),
+			pkg.Location.Href,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteAdvisoriesCSV writes advisories to w as CSV with a header row, one row per advisory. CVEs and fixed
+// packages are listed in a single cell each, separated by spaces.
+func WriteAdvisoriesCSV(w io.Writer, advisories []Advisory) error {
+	writer := csv.NewWriter(w)
+	header := []string{"id", "type", "severity", "title", "issued", "updated", "cves", "packages"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, advisory := range advisories {
+		packages := make([]string, 0, len(advisory.Packages))
+		for _, pkg := range advisory.Packages {
+			packages = append(packages, pkg.NEVRA().String())
+		}
+		record := []string{
+			advisory.ID,
+			string(advisory.Type),
+			advisory.Severity.String(),
+			advisory.Title,
+			advisory.Issued.Date,
+			advisory.Updated.Date,
+			strings.Join(advisory.CVEs(), " "),
+			strings.Join(packages, " "),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
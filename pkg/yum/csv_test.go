@@ -0,0 +1,57 @@
+package yum
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePackagesCSV(t *testing.T) {
+	var out bytes.Buffer
+	packages := []Package{{
+		Name:     "foo",
+		Arch:     "x86_64",
+		Version:  Version{Version: "1.0", Release: "1.el9", Epoch: 2},
+		Checksum: Checksum{Type: "sha256", Value: "abcd"},
+		Summary:  "Foo, with a comma",
+		Size:     PackageSize{Package: 1024, Installed: 4096},
+		Location: Location{Href: "Packages/f/foo-1.0-1.el9.x86_64.rpm"},
+	}}
+	require.NoError(t, WritePackagesCSV(&out, packages))
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"name", "epoch", "version", "release", "arch", "checksum_type", "checksum", "summary", "size", "installed_size", "location"},
+		{"foo", "2", "1.0", "1.el9", "x86_64", "sha256", "abcd", "Foo, with a comma", "1024", "4096", "Packages/f/foo-1.0-1.el9.x86_64.rpm"},
+	}, records)
+}
+
+func TestWriteAdvisoriesCSV(t *testing.T) {
+	var out bytes.Buffer
+	advisories := []Advisory{{
+		ID:         "RHSA-2024:0001",
+		Type:       AdvisorySecurity,
+		Severity:   SeverityImportant,
+		Title:      "Important: foo security update",
+		Issued:     AdvisoryDate{Date: "2024-01-01 00:00:00"},
+		Updated:    AdvisoryDate{Date: "2024-01-02 00:00:00"},
+		References: []Reference{{ID: "CVE-2024-0001", Type: ReferenceCVE}, {ID: "CVE-2024-0002", Type: ReferenceCVE}},
+		Packages: []AdvisoryPackage{
+			{Name: "foo", Version: "1.0", Release: "2", Arch: "x86_64"},
+			{Name: "foo-libs", Version: "1.0", Release: "2", Arch: "x86_64"},
+		},
+	}}
+	require.NoError(t, WriteAdvisoriesCSV(&out, advisories))
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", "type", "severity", "title", "issued", "updated", "cves", "packages"},
+		{"RHSA-2024:0001", "security", "Important", "Important: foo security update", "2024-01-01 00:00:00", "2024-01-02 00:00:00",
+			"CVE-2024-0001 CVE-2024-0002", "foo-0:1.0-2.x86_64 foo-libs-0:1.0-2.x86_64"},
+	}, records)
+}
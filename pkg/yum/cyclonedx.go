@@ -0,0 +1,141 @@
+package yum
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// cycloneDXAlgorithms maps checksum types to CycloneDX hash algorithms
+var cycloneDXAlgorithms = map[string]string{
+	"md5":    "MD5",
+	"sha":    "SHA-1",
+	"sha1":   "SHA-1",
+	"sha256": "SHA-256",
+	"sha384": "SHA-384",
+	"sha512": "SHA-512",
+}
+
+type cycloneDXBOM struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string              `json:"timestamp"`
+	Tools     cycloneDXTools      `json:"tools"`
+	Component *cycloneDXComponent `json:"component,omitempty"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type        string              `json:"type"`
+	BOMRef      string              `json:"bom-ref,omitempty"`
+	Name        string              `json:"name"`
+	Version     string              `json:"version,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Hashes      []cycloneDXHash     `json:"hashes,omitempty"`
+	Licenses    []cycloneDXLicense  `json:"licenses,omitempty"`
+	PURL        string              `json:"purl,omitempty"`
+	Properties  []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXLicense struct {
+	License cycloneDXLicenseName `json:"license"`
+}
+
+type cycloneDXLicenseName struct {
+	Name string `json:"name"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// WriteCycloneDX writes a CycloneDX 1.5 JSON BOM describing packages to w, with a package URL and the
+// hash of each package, for vulnerability scanners that ingest CycloneDX
+func WriteCycloneDX(w io.Writer, packages []Package, opts SBOMOptions) error {
+	created := opts.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	serialNumber, err := newUUID()
+	if err != nil {
+		return err
+	}
+
+	bom := cycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + serialNumber,
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: created.UTC().Format(time.RFC3339),
+			Tools:     cycloneDXTools{Components: []cycloneDXComponent{{Type: "application", Name: "yummy"}}},
+		},
+		Components: []cycloneDXComponent{},
+	}
+	if opts.Name != "" {
+		bom.Metadata.Component = &cycloneDXComponent{Type: "data", Name: opts.Name}
+	}
+
+	refs := map[string]bool{}
+	for _, pkg := range packages {
+		purl := PackageURL{Distro: opts.Distro, NEVRA: pkg.NEVRA(), RepositoryURL: opts.RepositoryURL}.String()
+		ref := purl
+		for i := 2; refs[ref]; i++ {
+			ref = fmt.Sprintf("%v#%d", purl, i)
+		}
+		refs[ref] = true
+
+		component := cycloneDXComponent{
+			Type:        "library",
+			BOMRef:      ref,
+			Name:        pkg.Name,
+			Version:     evrString(pkg.Version),
+			Description: pkg.Summary,
+			PURL:        purl,
+		}
+		if algorithm, ok := cycloneDXAlgorithms[strings.ToLower(pkg.Checksum.Type)]; ok {
+			component.Hashes = []cycloneDXHash{{Alg: algorithm, Content: pkg.Checksum.Value}}
+		}
+		if pkg.License != "" {
+			component.Licenses = []cycloneDXLicense{{License: cycloneDXLicenseName{Name: pkg.License}}}
+		}
+		if pkg.SourceRPM != "" {
+			component.Properties = []cycloneDXProperty{{Name: "yummy:sourcerpm", Value: pkg.SourceRPM}}
+		}
+		bom.Components = append(bom.Components, component)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bom)
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() (string, error) {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		return "", err
+	}
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]), nil
+}
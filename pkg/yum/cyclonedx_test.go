@@ -0,0 +1,51 @@
+package yum
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCycloneDX(t *testing.T) {
+	nss := Package{
+		Name:      "nss-devel",
+		Arch:      "i686",
+		Version:   Version{Version: "3.19.1", Release: "18.el7"},
+		Checksum:  Checksum{Type: "sha256", Value: "abcd"},
+		Summary:   "Development libraries for Network Security Services",
+		License:   "MPLv2.0",
+		SourceRPM: "nss-3.19.1-18.el7.src.rpm",
+	}
+	var out bytes.Buffer
+	require.NoError(t, WriteCycloneDX(&out, []Package{nss, nss}, SBOMOptions{
+		Name:    "rhel7",
+		Distro:  "RedHat",
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}))
+
+	var bom cycloneDXBOM
+	require.NoError(t, json.Unmarshal(out.Bytes(), &bom))
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "1.5", bom.SpecVersion)
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, bom.SerialNumber)
+	assert.Equal(t, "2024-01-02T03:04:05Z", bom.Metadata.Timestamp)
+	assert.Equal(t, "rhel7", bom.Metadata.Component.Name)
+	require.Len(t, bom.Components, 2)
+	assert.Equal(t, cycloneDXComponent{
+		Type:        "library",
+		BOMRef:      "pkg:rpm/redhat/nss-devel@3.19.1-18.el7?arch=i686",
+		Name:        "nss-devel",
+		Version:     "3.19.1-18.el7",
+		Description: "Development libraries for Network Security Services",
+		Hashes:      []cycloneDXHash{{Alg: "SHA-256", Content: "abcd"}},
+		Licenses:    []cycloneDXLicense{{License: cycloneDXLicenseName{Name: "MPLv2.0"}}},
+		PURL:        "pkg:rpm/redhat/nss-devel@3.19.1-18.el7?arch=i686",
+		Properties:  []cycloneDXProperty{{Name: "yummy:sourcerpm", Value: "nss-3.19.1-18.el7.src.rpm"}},
+	}, bom.Components[0])
+	// bom-refs stay unique when packages are listed twice
+	assert.Equal(t, "pkg:rpm/redhat/nss-devel@3.19.1-18.el7?arch=i686#2", bom.Components[1].BOMRef)
+}
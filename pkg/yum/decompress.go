@@ -0,0 +1,175 @@
+package yum
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/h2non/filetype"
+	"github.com/h2non/filetype/matchers"
+	"github.com/ulikunitz/xz"
+)
+
+// Format is the compression format of a metadata file
+type Format string
+
+const (
+	FormatPlain Format = "plain"
+	FormatGzip  Format = "gzip"
+	FormatXz    Format = "xz"
+	FormatZstd  Format = "zstd"
+	FormatBzip2 Format = "bzip2"
+)
+
+// ErrEmptyBody is returned when metadata is empty, so that its format cannot be detected
+var ErrEmptyBody = errors.New("empty body")
+
+// ErrDecompressedTooLarge is returned by the reader of DecompressingReader when the decompressed data exceeds
+// the size of WithMaxDecompressedSize
+var ErrDecompressedTooLarge = errors.New("decompressed data too large")
+
+// fileTypeHeaderSize is the number of bytes peeked at to detect the format of metadata
+const fileTypeHeaderSize = 20
+
+// DecompressOption changes the reader returned by DecompressingReader
+type DecompressOption func(*decompressOptions)
+
+type decompressOptions struct {
+	maxSize int64
+}
+
+// WithMaxDecompressedSize fails reading with ErrDecompressedTooLarge after maxSize bytes of decompressed
+// data, to guard against decompression bombs
+func WithMaxDecompressedSize(maxSize int64) DecompressOption {
+	return func(o *decompressOptions) {
+		o.maxSize = maxSize
+	}
+}
+
+// DecompressingReader returns a reader decompressing body, whatever repository file it holds, along with the
+// format detected from its first bytes. Uncompressed data is read as is, with FormatPlain. Data compressed
+// twice, as when it is served with a Content-Encoding that the HTTP client does not decode, is decompressed
+// twice. Returns ErrEmptyBody if body is empty.
+func DecompressingReader(body io.Reader, opts ...DecompressOption) (io.Reader, Format, error) {
+	var options decompressOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	bufferedReader := bufio.NewReader(body)
+	header, err := peekHeader(bufferedReader)
+	if err != nil {
+		return nil, "", err
+	}
+	format := detectFormat(header)
+	reader, err := decompress(bufferedReader, format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if format != FormatPlain {
+		// compressed once more for a Content-Encoding that the HTTP client did not decode
+		decompressed := bufio.NewReader(reader)
+		reader = decompressed
+		if header, err = peekHeader(decompressed); err == nil {
+			if innerFormat := detectFormat(header); innerFormat != FormatPlain {
+				if reader, err = decompress(decompressed, innerFormat); err != nil {
+					return nil, "", err
+				}
+			}
+		}
+	}
+
+	if options.maxSize > 0 {
+		reader = &maxSizeReader{reader: reader, remaining: options.maxSize}
+	}
+	return reader, format, nil
+}
+
+// peekHeader returns the first bytes of reader, fewer than fileTypeHeaderSize if it is shorter, without
+// consuming them. Returns ErrEmptyBody if reader is empty.
+func peekHeader(reader *bufio.Reader) ([]byte, error) {
+	header, err := reader.Peek(fileTypeHeaderSize)
+	if err == io.EOF {
+		if len(header) == 0 {
+			return nil, ErrEmptyBody
+		}
+		return header, nil
+	}
+	return header, err
+}
+
+// detectFormat returns the compression format of the data starting with header
+func detectFormat(header []byte) Format {
+	fileType, err := filetype.Match(header)
+	if err != nil {
+		return FormatPlain
+	}
+	switch fileType {
+	case matchers.TypeGz:
+		return FormatGzip
+	case matchers.TypeXz:
+		return FormatXz
+	case matchers.TypeZstd:
+		return FormatZstd
+	case matchers.TypeBz2:
+		return FormatBzip2
+	default:
+		return FormatPlain
+	}
+}
+
+// looksLikeXML reports whether header is the beginning of an uncompressed XML document
+func looksLikeXML(header []byte) bool {
+	header = bytes.TrimPrefix(header, []byte("\xef\xbb\xbf"))
+	return bytes.HasPrefix(bytes.TrimLeft(header, " \t\r\n"), []byte("<"))
+}
+
+// decompress returns a reader decompressing reader, which holds data compressed in format
+func decompress(reader io.Reader, format Format) (io.Reader, error) {
+	var decompressed io.Reader
+	var err error
+	switch format {
+	case FormatPlain:
+		return reader, nil
+	case FormatGzip:
+		decompressed, err = gzip.NewReader(reader)
+	case FormatZstd:
+		decompressed, err = newZstdReader(reader)
+	case FormatXz:
+		decompressed, err = xz.NewReader(reader)
+	case FormatBzip2:
+		decompressed = bzip2.NewReader(reader)
+	default:
+		return nil, fmt.Errorf("unsupported format %v", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error unzipping response body: %w", err)
+	}
+	return decompressed, nil
+}
+
+// maxSizeReader reads from reader until it fails with ErrDecompressedTooLarge after remaining bytes
+type maxSizeReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *maxSizeReader) Read(p []byte) (int, error) {
+	// read one byte more than remaining to tell whether there is more data
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	if int64(n) > r.remaining {
+		n = int(r.remaining)
+		r.remaining = 0
+		return n, ErrDecompressedTooLarge
+	}
+	r.remaining -= int64(n)
+	return n, err
+}
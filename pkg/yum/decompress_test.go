@@ -0,0 +1,60 @@
+package yum
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecompressingReader(t *testing.T) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(primaryXML))
+	require.NoError(t, err)
+	expected, err := io.ReadAll(gzipReader)
+	require.NoError(t, err)
+
+	files := map[string]Format{
+		"mocks/primary.xml.gz":  FormatGzip,
+		"mocks/primary.xml.xz":  FormatXz,
+		"mocks/primary.xml.zst": FormatZstd,
+		"mocks/primary.xml.bz2": FormatBzip2,
+		"mocks/comps.xml":       FormatPlain,
+	}
+	for path, expectedFormat := range files {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		reader, format, err := DecompressingReader(f)
+		require.NoError(t, err, path)
+		assert.Equal(t, expectedFormat, format, path)
+		data, err := io.ReadAll(reader)
+		assert.NoError(t, err, path)
+		if format != FormatPlain {
+			assert.Equal(t, expected, data, path)
+		} else {
+			assert.Contains(t, string(data), "<comps>", path)
+		}
+		f.Close()
+	}
+
+	_, _, err = DecompressingReader(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, ErrEmptyBody)
+}
+
+func TestDecompressingReaderMaxSize(t *testing.T) {
+	reader, _, err := DecompressingReader(bytes.NewReader(primaryXML), WithMaxDecompressedSize(100))
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.ErrorIs(t, err, ErrDecompressedTooLarge)
+	assert.Len(t, data, 100)
+
+	// data of exactly the maximum size is read
+	reader, _, err = DecompressingReader(bytes.NewReader([]byte("<a/>")), WithMaxDecompressedSize(4))
+	require.NoError(t, err)
+	data, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "<a/>", string(data))
+}
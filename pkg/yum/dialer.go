@@ -0,0 +1,84 @@
+package yum
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// NewHTTPClient returns a copy of settings.Client, http.DefaultClient if nil, with a transport configured with
+// the DisableProxy, DialContext, Resolver, TLSConfig, MaxIdleConnsPerHost, IdleConnTimeout and ForceHTTP2 of
+// settings, as NewRepository configures the client of a repository. Repositories given the returned client
+// as their Client, and none of these settings, share its connections, for bulk introspection of many
+// repositories on the same hosts.
+func NewHTTPClient(settings YummySettings) (*http.Client, error) {
+	client, err := configureClient(settings.Client, settings)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := client.(*http.Client)
+	if !ok {
+		return nil, fmt.Errorf("cannot configure the transport of a %T client", client)
+	}
+	if c == http.DefaultClient {
+		copied := *c
+		return &copied, nil
+	}
+	return c, nil
+}
+
+// configureClient returns client, http.DefaultClient if nil, or a copy of it with a transport configured with
+// the connection settings of settings if it sets any
+func configureClient(client HTTPDoer, settings YummySettings) (HTTPDoer, error) {
+	if isNilClient(client) {
+		client = http.DefaultClient
+	}
+	if settings.DisableProxy {
+		direct, err := directClient(client)
+		if err != nil {
+			return nil, err
+		}
+		client = direct
+	}
+	return dialerClient(client, settings)
+}
+
+// dialerClient returns client, or a copy of it with a transport using the DialContext, Resolver, TLSConfig and
+// connection pool settings of settings if it sets any
+func dialerClient(client HTTPDoer, settings YummySettings) (HTTPDoer, error) {
+	if settings.DialContext == nil && settings.Resolver == nil && settings.TLSConfig == nil &&
+		settings.MaxIdleConnsPerHost <= 0 && settings.IdleConnTimeout <= 0 && !settings.ForceHTTP2 {
+		return client, nil
+	}
+	transport, err := cloneTransport(client)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring dialer: %w", err)
+	}
+	switch {
+	case settings.DialContext != nil:
+		transport.DialContext = settings.DialContext
+	case settings.Resolver != nil:
+		// the timeouts of http.DefaultTransport's dialer
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Resolver: settings.Resolver}
+		transport.DialContext = dialer.DialContext
+	}
+	if settings.TLSConfig != nil {
+		transport.TLSClientConfig = settings.TLSConfig.Clone()
+	}
+	if settings.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = settings.MaxIdleConnsPerHost
+		if transport.MaxIdleConns > 0 && transport.MaxIdleConns < settings.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = settings.MaxIdleConnsPerHost
+		}
+	}
+	if settings.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = settings.IdleConnTimeout
+	}
+	if settings.ForceHTTP2 {
+		transport.ForceAttemptHTTP2 = true
+	}
+	configured := *client.(*http.Client)
+	configured.Transport = transport
+	return &configured, nil
+}
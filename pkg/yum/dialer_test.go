@@ -0,0 +1,134 @@
+package yum
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialContext(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "proxy.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	s := httptest.NewUnstartedServer(http.HandlerFunc(serveRepomdXML))
+	s.Listener = listener
+	s.Start()
+	defer s.Close()
+
+	dialContext := func(ctx context.Context, network, address string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socket)
+	}
+	url := "http://repo.internal/"
+	r, err := NewRepository(YummySettings{Client: &http.Client{}, URL: &url, DialContext: dialContext})
+	require.NoError(t, err)
+	_, code, err := r.Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestResolver(t *testing.T) {
+	errNoDNS := errors.New("no DNS server")
+	queries := 0
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			queries++
+			return nil, errNoDNS
+		},
+	}
+	url := "http://repo.internal/"
+	r, err := NewRepository(YummySettings{URL: &url, Resolver: resolver})
+	require.NoError(t, err)
+	_, _, err = r.Repomd(context.Background())
+	assert.Error(t, err)
+	assert.Positive(t, queries)
+}
+
+func TestTLSConfig(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(serveRepomdXML))
+	defer s.Close()
+
+	client := &http.Client{}
+	r, _ := NewRepository(YummySettings{Client: client, URL: &s.URL})
+	_, _, err := r.Repomd(context.Background())
+	assert.Error(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+	r.Configure(YummySettings{TLSConfig: &tls.Config{RootCAs: pool}})
+	_, code, err := r.Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, client.Transport, "the given client is not modified")
+
+	// the transport of a client that is not an *http.Client cannot be configured
+	_, err = NewRepository(YummySettings{Client: &handlerDoer{}, URL: &s.URL, TLSConfig: &tls.Config{}})
+	assert.ErrorContains(t, err, "error configuring dialer")
+}
+
+func TestTransportTuning(t *testing.T) {
+	var protocols []int
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protocols = append(protocols, r.ProtoMajor)
+		serveRepomdXML(w, r)
+	}))
+	s.EnableHTTP2 = true
+	s.StartTLS()
+	defer s.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+
+	// a custom TLS config disables HTTP/2 unless it is forced
+	client := &http.Client{Transport: &http.Transport{}}
+	r, err := NewRepository(YummySettings{Client: client, URL: &s.URL, TLSConfig: &tls.Config{RootCAs: pool}})
+	require.NoError(t, err)
+	_, _, err = r.Repomd(context.Background())
+	require.NoError(t, err)
+
+	r, err = NewRepository(YummySettings{Client: client, URL: &s.URL, TLSConfig: &tls.Config{RootCAs: pool},
+		MaxIdleConnsPerHost: 64, IdleConnTimeout: time.Minute, ForceHTTP2: true})
+	require.NoError(t, err)
+	_, _, err = r.Repomd(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, protocols)
+
+	transport := r.settings.Client.(*http.Client).Transport.(*http.Transport)
+	assert.Equal(t, 64, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.Zero(t, client.Transport.(*http.Transport).MaxIdleConnsPerHost, "the given client is not modified")
+	assert.False(t, client.Transport.(*http.Transport).ForceAttemptHTTP2)
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	client, err := NewHTTPClient(YummySettings{MaxIdleConnsPerHost: 32, DisableProxy: true})
+	require.NoError(t, err)
+	transport := client.Transport.(*http.Transport)
+	assert.Equal(t, 32, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 100, transport.MaxIdleConns)
+	assert.Nil(t, transport.Proxy)
+	assert.NotSame(t, http.DefaultClient, client)
+
+	// repositories given the client share its transport
+	url := "http://repo.internal/"
+	r, err := NewRepository(YummySettings{Client: client, URL: &url})
+	require.NoError(t, err)
+	assert.Same(t, client, r.settings.Client)
+
+	client, err = NewHTTPClient(YummySettings{})
+	require.NoError(t, err)
+	assert.NotSame(t, http.DefaultClient, client)
+
+	_, err = NewHTTPClient(YummySettings{Client: &handlerDoer{}})
+	assert.Error(t, err)
+}
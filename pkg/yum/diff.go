@@ -0,0 +1,178 @@
+package yum
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// RepositoryDiff lists the packages, advisories and module streams that differ between two repositories
+type RepositoryDiff struct {
+	AddedPackages        []Package        `json:"addedPackages,omitempty"`
+	RemovedPackages      []Package        `json:"removedPackages,omitempty"`
+	ChangedPackages      []PackageChange  `json:"changedPackages,omitempty"` // packages with the same NEVRA but a different checksum, such as rebuilds
+	AddedAdvisories      []Advisory       `json:"addedAdvisories,omitempty"`
+	RemovedAdvisories    []Advisory       `json:"removedAdvisories,omitempty"`
+	ChangedAdvisories    []AdvisoryChange `json:"changedAdvisories,omitempty"` // advisories with the same ID but a different version or updated date
+	AddedModuleStreams   []Stream         `json:"addedModuleStreams,omitempty"`
+	RemovedModuleStreams []Stream         `json:"removedModuleStreams,omitempty"`
+}
+
+// PackageChange is a package present in both repositories of a diff, with different content
+type PackageChange struct {
+	From Package `json:"from"`
+	To   Package `json:"to"`
+}
+
+// AdvisoryChange is an advisory present in both repositories of a diff that was updated
+type AdvisoryChange struct {
+	From Advisory `json:"from"`
+	To   Advisory `json:"to"`
+}
+
+// Empty returns true if the repositories of the diff have the same packages, advisories and module streams
+func (d RepositoryDiff) Empty() bool {
+	return len(d.AddedPackages) == 0 && len(d.RemovedPackages) == 0 && len(d.ChangedPackages) == 0 &&
+		len(d.AddedAdvisories) == 0 && len(d.RemovedAdvisories) == 0 && len(d.ChangedAdvisories) == 0 &&
+		len(d.AddedModuleStreams) == 0 && len(d.RemovedModuleStreams) == 0
+}
+
+// Diff compares the packages, advisories and module streams of two repositories, such as two snapshots
+// of the same repository. Changes are reported going from the first repository to the second.
+func Diff(ctx context.Context, from YumRepository, to YumRepository) (*RepositoryDiff, error) {
+	fromPackages, _, err := from.Packages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching packages: %w", err)
+	}
+	toPackages, _, err := to.Packages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching packages: %w", err)
+	}
+	fromAdvisories, _, err := from.Advisories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching advisories: %w", err)
+	}
+	toAdvisories, _, err := to.Advisories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching advisories: %w", err)
+	}
+	fromModuleMDs, _, err := from.ModuleMDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching module streams: %w", err)
+	}
+	toModuleMDs, _, err := to.ModuleMDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching module streams: %w", err)
+	}
+
+	diff := RepositoryDiff{}
+	diff.AddedPackages, diff.RemovedPackages, diff.ChangedPackages = diffPackages(fromPackages, toPackages)
+	diff.AddedAdvisories, diff.RemovedAdvisories, diff.ChangedAdvisories = diffAdvisories(fromAdvisories, toAdvisories)
+	diff.AddedModuleStreams, diff.RemovedModuleStreams = diffModuleStreams(fromModuleMDs, toModuleMDs)
+	return &diff, nil
+}
+
+// diffPackages compares packages by NEVRA, returning them sorted by NEVRA
+func diffPackages(from []Package, to []Package) (added []Package, removed []Package, changed []PackageChange) {
+	fromByNEVRA := map[NEVRA]Package{}
+	for _, pkg := range from {
+		fromByNEVRA[pkg.NEVRA()] = pkg
+	}
+	toByNEVRA := map[NEVRA]Package{}
+	for _, pkg := range to {
+		toByNEVRA[pkg.NEVRA()] = pkg
+		fromPkg, ok := fromByNEVRA[pkg.NEVRA()]
+		if !ok {
+			added = append(added, pkg)
+		} else if fromPkg.Checksum != pkg.Checksum {
+			changed = append(changed, PackageChange{From: fromPkg, To: pkg})
+		}
+	}
+	for _, pkg := range from {
+		if _, ok := toByNEVRA[pkg.NEVRA()]; !ok {
+			removed = append(removed, pkg)
+		}
+	}
+
+	sortPackages := func(packages []Package) {
+		sort.Slice(packages, func(i, j int) bool {
+			return packages[i].NEVRA().String() < packages[j].NEVRA().String()
+		})
+	}
+	sortPackages(added)
+	sortPackages(removed)
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[i].To.NEVRA().String() < changed[j].To.NEVRA().String()
+	})
+	return added, removed, changed
+}
+
+// diffAdvisories compares advisories by ID, returning them sorted by ID
+func diffAdvisories(from []Advisory, to []Advisory) (added []Advisory, removed []Advisory, changed []AdvisoryChange) {
+	fromByID := map[string]Advisory{}
+	for _, advisory := range from {
+		fromByID[advisory.ID] = advisory
+	}
+	toByID := map[string]Advisory{}
+	for _, advisory := range to {
+		toByID[advisory.ID] = advisory
+		fromAdvisory, ok := fromByID[advisory.ID]
+		if !ok {
+			added = append(added, advisory)
+		} else if fromAdvisory.Version != advisory.Version || fromAdvisory.Updated != advisory.Updated {
+			changed = append(changed, AdvisoryChange{From: fromAdvisory, To: advisory})
+		}
+	}
+	for _, advisory := range from {
+		if _, ok := toByID[advisory.ID]; !ok {
+			removed = append(removed, advisory)
+		}
+	}
+
+	sortAdvisories := func(advisories []Advisory) {
+		sort.Slice(advisories, func(i, j int) bool {
+			return advisories[i].ID < advisories[j].ID
+		})
+	}
+	sortAdvisories(added)
+	sortAdvisories(removed)
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[i].To.ID < changed[j].To.ID
+	})
+	return added, removed, changed
+}
+
+// diffModuleStreams compares module streams by name, stream, version, context and arch, returning them
+// sorted the same way
+func diffModuleStreams(from []ModuleMD, to []ModuleMD) (added []Stream, removed []Stream) {
+	fromByNSVCA := map[string]bool{}
+	for _, moduleMD := range from {
+		fromByNSVCA[streamNSVCA(moduleMD.Data)] = true
+	}
+	toByNSVCA := map[string]bool{}
+	for _, moduleMD := range to {
+		toByNSVCA[streamNSVCA(moduleMD.Data)] = true
+		if !fromByNSVCA[streamNSVCA(moduleMD.Data)] {
+			added = append(added, moduleMD.Data)
+		}
+	}
+	for _, moduleMD := range from {
+		if !toByNSVCA[streamNSVCA(moduleMD.Data)] {
+			removed = append(removed, moduleMD.Data)
+		}
+	}
+
+	sortStreams := func(streams []Stream) {
+		sort.Slice(streams, func(i, j int) bool {
+			return streamNSVCA(streams[i]) < streamNSVCA(streams[j])
+		})
+	}
+	sortStreams(added)
+	sortStreams(removed)
+	return added, removed
+}
+
+// streamNSVCA formats a module stream as name:stream:version:context:arch
+func streamNSVCA(stream Stream) string {
+	return fmt.Sprintf("%v:%v:%v:%v:%v", stream.Name, stream.Stream, stream.Version, stream.Context, stream.Arch)
+}
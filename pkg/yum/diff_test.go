@@ -0,0 +1,57 @@
+package yum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	foo := Package{Name: "foo", Arch: "noarch", Version: Version{Version: "1.0", Release: "1"}, Checksum: Checksum{Type: "sha256", Value: "aaaa"}}
+	rebuiltFoo := foo
+	rebuiltFoo.Checksum.Value = "bbbb"
+	bar := Package{Name: "bar", Arch: "x86_64", Version: Version{Version: "2.0", Release: "1"}, Checksum: Checksum{Type: "sha256", Value: "cccc"}}
+	baz := Package{Name: "baz", Arch: "x86_64", Version: Version{Version: "3.0", Release: "1"}, Checksum: Checksum{Type: "sha256", Value: "dddd"}}
+	updatedAdvisory := Advisory{ID: "RHSA-1", Version: "1", Updated: AdvisoryDate{Date: "2024-01-01"}}
+	removedAdvisory := Advisory{ID: "RHSA-2"}
+	addedAdvisory := Advisory{ID: "RHSA-3"}
+	nodejs18 := ModuleMD{Document: "modulemd", Version: 2, Data: Stream{Name: "nodejs", Stream: "18", Version: "1", Context: "abc", Arch: "x86_64"}}
+	nodejs20 := ModuleMD{Document: "modulemd", Version: 2, Data: Stream{Name: "nodejs", Stream: "20", Version: "1", Context: "abc", Arch: "x86_64"}}
+
+	from := NewMockYumRepository(t)
+	from.On("Packages", ctx).Return([]Package{foo, bar}, 200, nil)
+	from.On("Advisories", ctx).Return([]Advisory{updatedAdvisory, removedAdvisory}, 200, nil)
+	from.On("ModuleMDs", ctx).Return([]ModuleMD{nodejs18}, 200, nil)
+
+	to := NewMockYumRepository(t)
+	to.On("Packages", ctx).Return([]Package{rebuiltFoo, baz}, 200, nil)
+	newerAdvisory := updatedAdvisory
+	newerAdvisory.Updated.Date = "2024-02-01"
+	to.On("Advisories", ctx).Return([]Advisory{addedAdvisory, newerAdvisory}, 200, nil)
+	to.On("ModuleMDs", ctx).Return([]ModuleMD{nodejs18, nodejs20}, 200, nil)
+
+	diff, err := Diff(ctx, from, to)
+	require.NoError(t, err)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []Package{baz}, diff.AddedPackages)
+	assert.Equal(t, []Package{bar}, diff.RemovedPackages)
+	assert.Equal(t, []PackageChange{{From: foo, To: rebuiltFoo}}, diff.ChangedPackages)
+	assert.Equal(t, []Advisory{addedAdvisory}, diff.AddedAdvisories)
+	assert.Equal(t, []Advisory{removedAdvisory}, diff.RemovedAdvisories)
+	assert.Equal(t, []AdvisoryChange{{From: updatedAdvisory, To: newerAdvisory}}, diff.ChangedAdvisories)
+	assert.Equal(t, []Stream{nodejs20.Data}, diff.AddedModuleStreams)
+	assert.Empty(t, diff.RemovedModuleStreams)
+
+	same, err := Diff(ctx, from, from)
+	require.NoError(t, err)
+	assert.True(t, same.Empty())
+
+	failing := NewMockYumRepository(t)
+	failing.On("Packages", mock.Anything).Return(nil, 404, assert.AnError)
+	_, err = Diff(ctx, from, failing)
+	assert.ErrorIs(t, err, assert.AnError)
+}
@@ -0,0 +1,155 @@
+package yum
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// errRangeIgnored is returned by download when a range was requested but the server will not serve it
+	errRangeIgnored = errors.New("range request not honored")
+	// errIntegrity is wrapped by download errors where the downloaded file does not match its size or checksum
+	errIntegrity = errors.New("integrity check failed")
+)
+
+// DownloadPackage streams the RPM of a package from the repository to dest, verifying it against the
+// checksum and size from primary.xml. Returns the number of bytes written, response code and error.
+// As the RPM is streamed, dest has already been written to when a checksum mismatch is reported.
+func (r *Repository) DownloadPackage(ctx context.Context, pkg Package, dest io.Writer) (int64, int, error) {
+	if pkg.Location.Href == "" {
+		return 0, 0, fmt.Errorf("package %v has no location", pkg.Name)
+	}
+
+	return r.downloadVerified(ctx, pkg.Location, pkg.Checksum, pkg.Size.Package, dest)
+}
+
+// DownloadPackageToFile downloads the RPM of a package to path, verifying it like DownloadPackage.
+// The RPM is downloaded to path + ".part" first. If an earlier download was interrupted, the partial
+// file is resumed with a Range request rather than downloaded again from the start.
+// Returns the number of bytes written, response code and error.
+func (r *Repository) DownloadPackageToFile(ctx context.Context, pkg Package, path string) (int64, int, error) {
+	if pkg.Location.Href == "" {
+		return 0, 0, fmt.Errorf("package %v has no location", pkg.Name)
+	}
+
+	return r.downloadFile(ctx, pkg.Location, pkg.Checksum, pkg.Size.Package, path)
+}
+
+// downloadVerified streams the file at location to dest, verifying its checksum and, if not zero, its size
+func (r *Repository) downloadVerified(ctx context.Context, location Location, checksum Checksum, size int64, dest io.Writer) (int64, int, error) {
+	hash, err := newChecksumHash(checksum.Type)
+	if err != nil {
+		return 0, 0, err
+	}
+	return r.download(ctx, location, checksum, size, dest, hash, 0)
+}
+
+// downloadFile downloads the file at location to destPath through a partial file, resuming the partial file
+// left by an interrupted download if there is one. The partial file is kept if the download fails, unless
+// its content does not match checksum.
+func (r *Repository) downloadFile(ctx context.Context, location Location, checksum Checksum, size int64, destPath string) (int64, int, error) {
+	hash, err := newChecksumHash(checksum.Type)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return 0, 0, err
+	}
+	partPath := destPath + ".part"
+	part, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer part.Close()
+
+	// hashing the partial file also leaves it positioned at its end, ready to be appended to
+	offset, err := io.Copy(hash, part)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	written, code, err := r.download(ctx, location, checksum, size, part, hash, offset)
+	if errors.Is(err, errRangeIgnored) {
+		r.logger().InfoContext(ctx, "range request not honored, restarting download", "href", location.Href, "offset", offset)
+		if err = restartPartialFile(part, hash); err != nil {
+			return 0, code, err
+		}
+		written, code, err = r.download(ctx, location, checksum, size, part, hash, 0)
+	}
+	if err != nil {
+		if errors.Is(err, errIntegrity) {
+			part.Close()
+			os.Remove(partPath)
+		}
+		return written, code, err
+	}
+
+	if err = part.Close(); err != nil {
+		return written, code, err
+	}
+	return written, code, os.Rename(partPath, destPath)
+}
+
+// restartPartialFile empties a partial file and resets the hash of its content
+func restartPartialFile(part *os.File, hash hash.Hash) error {
+	hash.Reset()
+	if err := part.Truncate(0); err != nil {
+		return err
+	}
+	_, err := part.Seek(0, io.SeekStart)
+	return err
+}
+
+// download streams the file at location from offset to dest, then verifies the checksum and, if not zero,
+// the size of the whole file. hash must already hold the first offset bytes of the file. If offset is
+// not zero and the server does not honor the range request, nothing is written and errRangeIgnored is
+// returned. Returns the number of bytes written, response code and error.
+func (r *Repository) download(ctx context.Context, location Location, checksum Checksum, size int64, dest io.Writer, hash hash.Hash, offset int64) (int64, int, error) {
+	fileURL, err := r.getLocationURL(location)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error parsing URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error creating request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := r.do(req, "download")
+	if err != nil {
+		return 0, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", fileURL, err)
+	}
+	defer resp.Body.Close()
+
+	if offset > 0 && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusRequestedRangeNotSatisfiable) {
+		return 0, resp.StatusCode, errRangeIgnored
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return 0, resp.StatusCode, fmt.Errorf("Cannot fetch %v: %d", fileURL, resp.StatusCode)
+	}
+
+	written, err := io.Copy(io.MultiWriter(dest, hash), resp.Body)
+	if err != nil {
+		return written, resp.StatusCode, fmt.Errorf("error downloading %v: %w", fileURL, err)
+	}
+
+	if total := offset + written; size != 0 && total != size {
+		return written, resp.StatusCode, fmt.Errorf("%w: size mismatch for %v: expected %d, got %d", errIntegrity, fileURL, size, total)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(sum, checksum.Value) {
+		return written, resp.StatusCode, fmt.Errorf("%w: checksum mismatch for %v: expected %v, got %v", errIntegrity, fileURL, checksum.Value, sum)
+	}
+	return written, resp.StatusCode, nil
+}
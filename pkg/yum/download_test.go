@@ -0,0 +1,109 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadPackage(t *testing.T) {
+	rpm := []byte("not really an rpm")
+	sum := sha256.Sum256(rpm)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Packages/f/foo-1.0-1.x86_64.rpm", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(rpm)
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	pkg := Package{
+		Name:     "foo",
+		Checksum: Checksum{Type: "sha256", Value: hex.EncodeToString(sum[:])},
+		Size:     PackageSize{Package: int64(len(rpm))},
+		Location: Location{Href: "Packages/f/foo-1.0-1.x86_64.rpm"},
+	}
+
+	var dest bytes.Buffer
+	written, code, err := r.DownloadPackage(context.Background(), pkg, &dest)
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, int64(len(rpm)), written)
+	assert.Equal(t, rpm, dest.Bytes())
+
+	pkg.Checksum.Value = "0000"
+	_, _, err = r.DownloadPackage(context.Background(), pkg, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	pkg.Location.Href = "Packages/missing.rpm"
+	_, code, err = r.DownloadPackage(context.Background(), pkg, &bytes.Buffer{})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestDownloadPackageToFileResumes(t *testing.T) {
+	rpm := bytes.Repeat([]byte("rpm payload "), 100)
+	sum := sha256.Sum256(rpm)
+
+	var ranges []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Packages/foo.rpm", func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "foo.rpm", time.Time{}, bytes.NewReader(rpm))
+	})
+	mux.HandleFunc("/Packages/norange.rpm", func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		_, _ = w.Write(rpm)
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	pkg := Package{
+		Name:     "foo",
+		Checksum: Checksum{Type: "sha256", Value: hex.EncodeToString(sum[:])},
+		Size:     PackageSize{Package: int64(len(rpm))},
+		Location: Location{Href: "Packages/foo.rpm"},
+	}
+
+	// an interrupted download left the first half of the rpm behind
+	dest := filepath.Join(t.TempDir(), "foo.rpm")
+	require.NoError(t, os.WriteFile(dest+".part", rpm[:500], 0644))
+
+	written, code, err := r.DownloadPackageToFile(context.Background(), pkg, dest)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPartialContent, code)
+	assert.Equal(t, int64(len(rpm)-500), written)
+	assert.Equal(t, []string{"bytes=500-"}, ranges)
+	downloaded, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, rpm, downloaded)
+	assert.NoFileExists(t, dest+".part")
+
+	// servers ignoring the range are downloaded from the start again
+	ranges = nil
+	pkg.Location.Href = "Packages/norange.rpm"
+	require.NoError(t, os.WriteFile(dest+".part", rpm[:500], 0644))
+	written, _, err = r.DownloadPackageToFile(context.Background(), pkg, dest)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(rpm)), written)
+	assert.Equal(t, []string{"bytes=500-", ""}, ranges)
+
+	// a corrupt partial download is discarded
+	require.NoError(t, os.WriteFile(dest+".part", []byte("corrupt"), 0644))
+	pkg.Location.Href = "Packages/foo.rpm"
+	_, _, err = r.DownloadPackageToFile(context.Background(), pkg, dest)
+	assert.Error(t, err)
+	assert.NoFileExists(t, dest+".part")
+}
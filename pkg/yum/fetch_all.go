@@ -0,0 +1,82 @@
+package yum
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// FetchResult is the outcome of fetching one type of metadata
+type FetchResult struct {
+	URL        string // URL of the file, empty if unknown
+	StatusCode int
+	Err        error
+}
+
+// FetchAll fetches and caches the repomd, primary, comps, modules, updateinfo and filelists metadata and the
+// repomd signature of the repository, concurrently once repomd.xml is fetched. The results are keyed by
+// metadata type: "repomd", "primary", "group", "modules", "updateinfo", "filelists" and "signature".
+// The returned error is a *MultiError holding the error of every type that failed, nil if all were
+// fetched. Packages are cached without modular filtering, which Packages applies when called.
+func (r *Repository) FetchAll(ctx context.Context) (map[string]FetchResult, error) {
+	results := map[string]FetchResult{}
+
+	_, code, err := r.Repomd(ctx)
+	results["repomd"] = FetchResult{URL: r.metadataURL("repomd"), StatusCode: code, Err: err}
+	if err != nil {
+		return results, &MultiError{Errors: []MetadataError{{Type: "repomd", URL: results["repomd"].URL, StatusCode: code, Err: err}}}
+	}
+
+	// each fetch caches its metadata in a distinct field of r, and only reads the cached repomd
+	fetches := map[string]func(context.Context) (int, error){
+		"primary": func(ctx context.Context) (int, error) {
+			_, code, err := traced(ctx, r, "Packages", r.fetchPackages)
+			return code, err
+		},
+		"group": func(ctx context.Context) (int, error) {
+			_, code, err := r.Comps(ctx)
+			return code, err
+		},
+		"modules": func(ctx context.Context) (int, error) {
+			_, code, err := r.ModuleMDs(ctx)
+			return code, err
+		},
+		"updateinfo": func(ctx context.Context) (int, error) {
+			_, code, err := r.Advisories(ctx)
+			return code, err
+		},
+		"filelists": func(ctx context.Context) (int, error) {
+			_, code, err := r.Filelists(ctx)
+			return code, err
+		},
+		"signature": func(ctx context.Context) (int, error) {
+			_, code, err := r.Signature(ctx)
+			return code, err
+		},
+	}
+
+	var mu sync.Mutex
+	var group errgroup.Group
+	for metadataType, fetch := range fetches {
+		group.Go(func() error {
+			code, err := fetch(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			results[metadataType] = FetchResult{URL: r.metadataURL(metadataType), StatusCode: code, Err: err}
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	var errs []MetadataError
+	for _, metadataType := range []string{"primary", "group", "modules", "updateinfo", "filelists", "signature"} {
+		if result := results[metadataType]; result.Err != nil {
+			errs = append(errs, MetadataError{Type: metadataType, URL: result.URL, StatusCode: result.StatusCode, Err: result.Err})
+		}
+	}
+	if len(errs) > 0 {
+		return results, &MultiError{Errors: errs}
+	}
+	return results, nil
+}
@@ -0,0 +1,115 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed "mocks/filelists.xml.gz"
+var filelistsXML []byte
+
+func filelistsServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repodata/repomd.xml", serveRepomdXML)
+	mux.HandleFunc("/repodata/primary.xml.gz", servePrimaryXML)
+	mux.HandleFunc("/repodata/comps.xml", serveCompsXML)
+	mux.HandleFunc("/repodata/module.yaml.zst", serveModulesMd)
+	mux.HandleFunc("/repodata/updateinfo.xml.gz", serveUpdateInfoXML)
+	mux.HandleFunc("/repodata/filelists.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/gzip")
+		_, _ = w.Write(filelistsXML)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestFilelists(t *testing.T) {
+	s := filelistsServer()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	filelists, code, err := r.Filelists(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	require.Len(t, filelists, 2)
+	assert.Equal(t, "b2fb721826000bb9b69f46ec4c7c0040ee3b1a90", filelists[0].PkgID)
+	assert.Equal(t, "nss-devel", filelists[0].Name)
+	assert.Equal(t, Version{Version: "3.19.1", Release: "18.el7"}, filelists[0].Version)
+	assert.Equal(t, []PackageFile{
+		{Path: "/usr/bin/nss-config"},
+		{Path: "/usr/include/nss3", Type: "dir"},
+		{Path: "/usr/include/nss3/cert.h"},
+		{Path: "/usr/lib/pkgconfig/nss.pc"},
+	}, filelists[0].Files)
+	assert.Equal(t, PackageFile{Path: "/var/lib/tpm-quote-tools/cache", Type: "ghost"}, filelists[1].Files[2])
+
+	// the package of the file list has the pkgid as checksum
+	packages, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, packages[0].Checksum.Value, filelists[0].PkgID)
+
+	// MaxPackages truncates the file lists
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, MaxPackages: 1})
+	filelists, _, err = r.Filelists(context.Background())
+	var truncated *TruncatedError
+	require.ErrorAs(t, err, &truncated)
+	assert.Equal(t, 1, truncated.Limit)
+	assert.Len(t, filelists, 1)
+	_, _, err = r.Filelists(context.Background())
+	assert.ErrorAs(t, err, &truncated)
+
+	// the decompressed filelists.xml is read up to the max size
+	_, err = parseFilelistsXML(io.NopCloser(bytes.NewReader(filelistsXML)), 100, 0)
+	assert.ErrorContains(t, err, "unexpected EOF")
+}
+
+func TestFetchAll(t *testing.T) {
+	s := filelistsServer()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	results, err := r.FetchAll(context.Background())
+
+	// the mock server does not serve a signature
+	assert.ErrorContains(t, err, "signature: received http 404")
+	assert.Equal(t, 404, results["signature"].StatusCode)
+	var multiErr *MultiError
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors, 1)
+	assert.Equal(t, MetadataError{Type: "signature", URL: s.URL + "/repodata/repomd.xml.asc", StatusCode: 404, Err: results["signature"].Err}, multiErr.Errors[0])
+	assert.Equal(t, s.URL+"/repodata/primary.xml.gz", results["primary"].URL)
+	for _, metadataType := range []string{"repomd", "primary", "group", "modules", "updateinfo", "filelists"} {
+		assert.NoError(t, results[metadataType].Err, metadataType)
+		assert.Equal(t, 200, results[metadataType].StatusCode, metadataType)
+	}
+
+	assert.Len(t, r.packages, 2)
+	assert.NotNil(t, r.comps)
+	assert.NotEmpty(t, r.moduleMDs)
+	assert.NotEmpty(t, r.advisories)
+	assert.Len(t, r.filelists, 2)
+}
+
+func TestFetchAllRepomdError(t *testing.T) {
+	s := filelistsServer()
+	defer s.Close()
+
+	url := s.URL + "/missing"
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &url})
+	results, err := r.FetchAll(context.Background())
+	assert.ErrorContains(t, err, "repomd")
+	assert.Equal(t, map[string]FetchResult{"repomd": results["repomd"]}, results)
+	assert.Equal(t, 404, results["repomd"].StatusCode)
+	var metadataErr *MetadataError
+	require.ErrorAs(t, err, &metadataErr)
+	assert.Equal(t, "repomd", metadataErr.Type)
+	assert.Equal(t, url+"/repodata/repomd.xml", metadataErr.URL)
+	assert.Equal(t, 404, metadataErr.StatusCode)
+}
@@ -0,0 +1,151 @@
+package yum
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// PackageFiles lists the files of a package, from the filelists metadata of a repository. Packages are
+// identified by the checksum of their rpm, the Checksum.Value of the Package.
+type PackageFiles struct {
+	PkgID   string        `xml:"pkgid,attr" json:"pkgid"`
+	Name    string        `xml:"name,attr" json:"name"`
+	Arch    string        `xml:"arch,attr" json:"arch"`
+	Version Version       `xml:"version" json:"version"`
+	Files   []PackageFile `xml:"file" json:"files,omitempty"`
+}
+
+// PackageFile is a file of a package
+type PackageFile struct {
+	Path string `xml:",chardata" json:"path"`
+	Type string `xml:"type,attr,omitempty" json:"type"` // "dir" or "ghost", empty for regular files
+}
+
+// Filelists fetches and parses the filelists metadata of the repository. Returns response code and error.
+// If the filelists were successfully fetched previously, will return cached filelists. With MaxPackages,
+// the file lists of the first MaxPackages packages are returned with a *TruncatedError if there are more.
+func (r *Repository) Filelists(ctx context.Context) ([]PackageFiles, int, error) {
+	var err error
+	var filelistsURL *string
+	var resp *http.Response
+	var filelists []PackageFiles
+
+	r.cacheRequest(ctx, "filelists", r.filelists != nil)
+	if r.filelists != nil {
+		return r.filelists, 200, r.filelistsTruncated
+	}
+
+	if _, _, err = r.Repomd(ctx); err != nil {
+		return nil, 0, fmt.Errorf("error parsing repomd.xml: %w", err)
+	}
+
+	if filelistsURL, err = r.getFilelistsURL(); err != nil {
+		return nil, 0, fmt.Errorf("error parsing filelists URL: %w", err)
+	}
+
+	if filelistsURL == nil {
+		return []PackageFiles{}, 200, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *filelistsURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	if resp, err = r.do(req, "filelists"); err != nil {
+		return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", *filelistsURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("Cannot fetch %v: %d", *filelistsURL, resp.StatusCode)
+	}
+
+	var truncated *TruncatedError
+	filelists, err = parseFilelistsXML(r.decompressedBody(resp, "filelists"), *r.settings.MaxXmlSize, r.settings.MaxPackages)
+	if err != nil {
+		if !errors.As(err, &truncated) {
+			return nil, resp.StatusCode, fmt.Errorf("error parsing filelists.xml: %w", err)
+		}
+		r.logger().WarnContext(ctx, "truncated file lists", "url", redactURL(r.repositoryURL()), "limit", truncated.Limit)
+	}
+	r.parsed("filelists", len(filelists))
+	r.filelists = filelists
+	r.filelistsTruncated = err
+
+	return filelists, resp.StatusCode, err
+}
+
+func (r *Repository) getFilelistsURL() (*string, error) {
+	var filelistsLocation Location
+
+	for _, data := range r.repomd.Data {
+		if data.Type == "filelists" {
+			filelistsLocation = data.Location
+		}
+	}
+
+	if filelistsLocation.Href == "" {
+		return nil, nil
+	}
+
+	filelistsURL, err := r.getLocationURL(filelistsLocation)
+	if err != nil {
+		return nil, err
+	}
+	return &filelistsURL, nil
+}
+
+// ParseFilelistsXML creates a PackageFiles array from a compressed or uncompressed filelists.xml, reading at
+// most DefaultMaxXmlSize bytes of it
+func ParseFilelistsXML(body io.ReadCloser) ([]PackageFiles, error) {
+	return parseFilelistsXML(body, DefaultMaxXmlSize, 0)
+}
+
+// parseFilelistsXML parses a compressed or uncompressed filelists.xml, reading at most maxSize bytes. If
+// maxPackages is positive, it returns the file lists of the first maxPackages packages with a
+// *TruncatedError if there are more.
+func parseFilelistsXML(body io.ReadCloser, maxSize int64, maxPackages int) ([]PackageFiles, error) {
+	filelists := []PackageFiles{}
+
+	reader, err := ExtractIfCompressed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxSize))
+	var element, item string
+	for {
+		t, decodeError := decoder.Token()
+
+		if decodeError == io.EOF {
+			break
+		} else if decodeError != nil {
+			return nil, fmt.Errorf("error decoding token: %w", xmlParseError(decoder, element, item, decodeError))
+		} else if t == nil {
+			break
+		}
+
+		elType, ok := t.(xml.StartElement)
+		if ok && elType.Name.Local == "package" {
+			if maxPackages > 0 && len(filelists) == maxPackages {
+				return filelists, &TruncatedError{Limit: maxPackages}
+			}
+			var packageFiles PackageFiles
+			if decodeElementError := decoder.DecodeElement(&packageFiles, &elType); decodeElementError != nil {
+				return nil, xmlParseError(decoder, elType.Name.Local, cmp.Or(packageFiles.Name, item), decodeElementError)
+			}
+			item = packageFiles.Name
+			filelists = append(filelists, packageFiles)
+		} else if ok {
+			element = elType.Name.Local
+		}
+	}
+
+	return filelists, nil
+}
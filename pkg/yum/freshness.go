@@ -0,0 +1,97 @@
+package yum
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoTimestamp is returned when a repomd.xml has no timestamp to tell the age of the metadata from
+var ErrNoTimestamp = errors.New("repomd.xml has no timestamps")
+
+// Time returns the time the file was generated, and false if its timestamp is missing or invalid
+func (d Data) Time() (time.Time, bool) {
+	if d.Timestamp == "" {
+		return time.Time{}, false
+	}
+	seconds, err := parseTimestamp(d.Timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0).UTC(), true
+}
+
+// LastUpdated returns the time the newest metadata file of the repository was generated. If no file has a
+// timestamp, the revision is used if it is a unix timestamp, see RevisionTime. Returns false if neither
+// tells the time.
+func (r Repomd) LastUpdated() (time.Time, bool) {
+	var newest time.Time
+	for _, data := range r.Data {
+		if generated, ok := data.Time(); ok && generated.After(newest) {
+			newest = generated
+		}
+	}
+	if !newest.IsZero() {
+		return newest, true
+	}
+	return r.RevisionTime()
+}
+
+// RevisionTime returns the revision as a time if it is a unix timestamp, as createrepo writes by default,
+// and false if it is an opaque string such as a compose ID
+func (r Repomd) RevisionTime() (time.Time, bool) {
+	seconds, ok := revisionNumber(r.Revision)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0).UTC(), true
+}
+
+// CompareRevisions orders two repomd.xml revisions, returning -1 if a is older than b, 0 if they are the
+// same and +1 if a is newer. Revisions that are unix timestamps are compared as numbers. Opaque revisions
+// can only be told apart, not ordered: false is returned if a and b differ and are not both timestamps.
+func CompareRevisions(a, b string) (int, bool) {
+	if a == b {
+		return 0, true
+	}
+	first, okA := revisionNumber(a)
+	second, okB := revisionNumber(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	switch {
+	case first < second:
+		return -1, true
+	case first > second:
+		return 1, true
+	}
+	return 0, true
+}
+
+// revisionNumber parses a revision made of digits, with an optional fractional part
+func revisionNumber(revision string) (float64, bool) {
+	revision = strings.TrimSpace(revision)
+	integer, fraction, _ := strings.Cut(revision, ".")
+	if integer == "" || strings.Trim(integer, "0123456789") != "" || strings.Trim(fraction, "0123456789") != "" {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(revision, 64)
+	return number, err == nil
+}
+
+// IsStale reports whether the newest metadata of the repository is older than maxAge, to flag abandoned
+// repositories. Returns ErrNoTimestamp if repomd.xml does not tell when the metadata was generated.
+// Returns response code and error.
+func (r *Repository) IsStale(ctx context.Context, maxAge time.Duration) (bool, int, error) {
+	repomd, code, err := r.Repomd(ctx)
+	if err != nil {
+		return false, code, err
+	}
+	lastUpdated, ok := repomd.LastUpdated()
+	if !ok {
+		return false, code, ErrNoTimestamp
+	}
+	return time.Since(lastUpdated) > maxAge, code, nil
+}
@@ -0,0 +1,93 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepomdLastUpdated(t *testing.T) {
+	repomd := Repomd{Revision: "1600000000", Data: []Data{
+		{Type: "primary", Timestamp: "1700000000"},
+		{Type: "group", Timestamp: "1700000100.25"},
+		{Type: "updateinfo", Timestamp: "invalid"},
+	}}
+	lastUpdated, ok := repomd.LastUpdated()
+	assert.True(t, ok)
+	assert.Equal(t, time.Unix(1700000100, 0).UTC(), lastUpdated)
+
+	_, ok = repomd.Data[2].Time()
+	assert.False(t, ok)
+
+	// the revision is used without timestamps
+	lastUpdated, ok = Repomd{Revision: "1600000000", Data: []Data{{Type: "primary"}}}.LastUpdated()
+	assert.True(t, ok)
+	assert.Equal(t, time.Unix(1600000000, 0).UTC(), lastUpdated)
+
+	_, ok = Repomd{Revision: "rhel-9.4"}.LastUpdated()
+	assert.False(t, ok)
+}
+
+func TestRevisionTime(t *testing.T) {
+	revision, ok := Repomd{Revision: " 1700000000 "}.RevisionTime()
+	assert.True(t, ok)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), revision)
+
+	revision, ok = Repomd{Revision: "1700000000.75"}.RevisionTime()
+	assert.True(t, ok)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), revision)
+
+	for _, opaque := range []string{"", "rhel-9.4", "1e9", "-1", "NaN", "12.3.4"} {
+		_, ok = Repomd{Revision: opaque}.RevisionTime()
+		assert.False(t, ok, opaque)
+	}
+}
+
+func TestCompareRevisions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+		ok       bool
+	}{
+		{"1700000000", "1700000000", 0, true},
+		{"1700000000", "1700000001", -1, true},
+		{"999", "1000", -1, true},
+		{"1700000000.5", "1700000000.25", 1, true},
+		{"compose-1", "compose-1", 0, true},
+		{"compose-1", "compose-2", 0, false},
+		{"1700000000", "compose-2", 0, false},
+	}
+	for _, test := range tests {
+		result, ok := CompareRevisions(test.a, test.b)
+		assert.Equal(t, test.expected, result, "%v %v", test.a, test.b)
+		assert.Equal(t, test.ok, ok, "%v %v", test.a, test.b)
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	// the newest file of the mock repomd.xml was generated in October 2023
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	stale, code, err := r.IsStale(context.Background(), 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, stale)
+
+	stale, _, err = r.IsStale(context.Background(), time.Since(time.Unix(1698193209, 0))+time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, stale)
+
+	opaque := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<repomd xmlns="http://linux.duke.edu/metadata/repo"><revision>rhel-9.4</revision></repomd>`))
+	}))
+	defer opaque.Close()
+	r, _ = NewRepository(YummySettings{Client: opaque.Client(), URL: &opaque.URL})
+	_, _, err = r.IsStale(context.Background(), time.Hour)
+	assert.ErrorIs(t, err, ErrNoTimestamp)
+}
@@ -0,0 +1,261 @@
+package yum
+
+import (
+	"cmp"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultGCSEndpoint is the Cloud Storage endpoint of GCSClient when Endpoint is not set
+	DefaultGCSEndpoint = "https://storage.googleapis.com"
+	// GCSReadOnlyScope is the OAuth 2.0 scope of the tokens NewGCSClient authenticates with
+	GCSReadOnlyScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+	googleTokenURL      = "https://oauth2.googleapis.com/token"
+	googleMetadataHost  = "metadata.google.internal"
+	googleJWTGrantType  = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	googleTokenLifetime = time.Hour
+)
+
+// GCSClient is an HTTPDoer that serves repositories from Google Cloud Storage buckets, so that mirrors kept in
+// a bucket can be introspected without a public HTTP endpoint. Set it as YummySettings.Client with a URL of
+// the form gs://bucket/prefix: requests for gs URLs are sent to the Cloud Storage XML API, authenticated with
+// Token, and other requests, such as those for GPG keys on web servers, are sent as they are.
+type GCSClient struct {
+	Client   HTTPDoer    // Sends the requests, http.DefaultClient if nil
+	Token    TokenSource // Authenticates the requests for gs URLs, anonymous for public buckets if nil
+	Endpoint string      // Cloud Storage endpoint, DefaultGCSEndpoint if empty
+}
+
+// NewGCSClient returns a GCSClient authenticated with the Application Default Credentials, as found by
+// GoogleDefaultTokenSource
+func NewGCSClient() (*GCSClient, error) {
+	token, err := GoogleDefaultTokenSource(http.DefaultClient, GCSReadOnlyScope)
+	if err != nil {
+		return nil, err
+	}
+	return &GCSClient{Token: token}, nil
+}
+
+// Do sends req, translating gs://bucket/object URLs to the Cloud Storage XML API
+func (c *GCSClient) Do(req *http.Request) (*http.Response, error) {
+	client := c.Client
+	if isNilClient(client) {
+		client = http.DefaultClient
+	}
+	if req.URL.Scheme != "gs" {
+		return client.Do(req)
+	}
+
+	endpoint, err := url.Parse(cmp.Or(c.Endpoint, DefaultGCSEndpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Cloud Storage endpoint: %w", err)
+	}
+	objectURL := *endpoint
+	objectURL.Path = path.Join("/", endpoint.Path, req.URL.Host, req.URL.Path)
+
+	gcsReq := req.Clone(req.Context())
+	gcsReq.URL = &objectURL
+	gcsReq.Host = ""
+	if c.Token != nil {
+		token, err := c.Token(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("error getting Cloud Storage token: %w", err)
+		}
+		gcsReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	return client.Do(gcsReq)
+}
+
+// googleCredentials is a credentials file, of a service account or of a user logged in with gcloud
+type googleCredentials struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// GoogleDefaultTokenSource returns a TokenSource of Google OAuth 2.0 access tokens for scopes, using the
+// Application Default Credentials: the credentials file named by GOOGLE_APPLICATION_CREDENTIALS, else the
+// file written by gcloud auth application-default login, else the service account of the metadata server of
+// Compute Engine, GKE and Cloud Run, which is only contacted when a token is needed. Service account and
+// authorized user files are supported. Tokens are requested with client and cached until they expire.
+func GoogleDefaultTokenSource(client HTTPDoer, scopes ...string) (TokenSource, error) {
+	if isNilClient(client) {
+		client = http.DefaultClient
+	}
+
+	credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if credentialsPath == "" {
+		if wellKnown := gcloudCredentialsPath(); wellKnown != "" {
+			if _, err := os.Stat(wellKnown); err == nil {
+				credentialsPath = wellKnown
+			}
+		}
+	}
+	if credentialsPath == "" {
+		return googleMetadataTokenSource(client, scopes), nil
+	}
+
+	content, err := os.ReadFile(credentialsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Google credentials: %w", err)
+	}
+	return GoogleCredentialsTokenSource(content, client, scopes...)
+}
+
+// GoogleCredentialsTokenSource returns a TokenSource of Google OAuth 2.0 access tokens for scopes from the
+// JSON of a service account key or an authorized user credentials file
+func GoogleCredentialsTokenSource(credentialsJSON []byte, client HTTPDoer, scopes ...string) (TokenSource, error) {
+	if isNilClient(client) {
+		client = http.DefaultClient
+	}
+
+	var credentials googleCredentials
+	if err := json.Unmarshal(credentialsJSON, &credentials); err != nil {
+		return nil, fmt.Errorf("error parsing Google credentials: %w", err)
+	}
+	tokenURL := cmp.Or(credentials.TokenURI, googleTokenURL)
+
+	switch credentials.Type {
+	case "service_account":
+		key, err := parseRSAPrivateKey(credentials.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing private key of %v: %w", credentials.ClientEmail, err)
+		}
+		return cachingTokenSource(func(ctx context.Context) (accessToken, error) {
+			assertion, err := googleJWT(credentials, key, tokenURL, scopes, time.Now())
+			if err != nil {
+				return accessToken{}, err
+			}
+			return postOAuthForm(ctx, client, tokenURL, url.Values{"grant_type": {googleJWTGrantType}, "assertion": {assertion}})
+		}), nil
+	case "authorized_user":
+		return cachingTokenSource(func(ctx context.Context) (accessToken, error) {
+			return postOAuthForm(ctx, client, tokenURL, url.Values{
+				"grant_type":    {"refresh_token"},
+				"client_id":     {credentials.ClientID},
+				"client_secret": {credentials.ClientSecret},
+				"refresh_token": {credentials.RefreshToken},
+			})
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported Google credentials type %q", credentials.Type)
+	}
+}
+
+// gcloudCredentialsPath returns the path of the credentials file written by gcloud auth application-default login
+func gcloudCredentialsPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "gcloud", "application_default_credentials.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gcloud", "application_default_credentials.json")
+}
+
+// googleMetadataTokenSource returns a TokenSource of the tokens of the default service account of the
+// metadata server, at GCE_METADATA_HOST if set
+func googleMetadataTokenSource(client HTTPDoer, scopes []string) TokenSource {
+	tokenURL := url.URL{
+		Scheme: "http",
+		Host:   cmp.Or(os.Getenv("GCE_METADATA_HOST"), googleMetadataHost),
+		Path:   "/computeMetadata/v1/instance/service-accounts/default/token",
+	}
+	if len(scopes) > 0 {
+		tokenURL.RawQuery = url.Values{"scopes": {strings.Join(scopes, ",")}}.Encode()
+	}
+	return cachingTokenSource(func(ctx context.Context) (accessToken, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+		if err != nil {
+			return accessToken{}, fmt.Errorf("error creating request: %w", err)
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+		return fetchOAuthToken(client, req)
+	})
+}
+
+// postOAuthForm requests a token from an OAuth 2.0 token endpoint with a form
+func postOAuthForm(ctx context.Context, client HTTPDoer, tokenURL string, form url.Values) (accessToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return accessToken{}, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return fetchOAuthToken(client, req)
+}
+
+// googleJWT returns the signed JWT a service account exchanges for an access token
+func googleJWT(credentials googleCredentials, key *rsa.PrivateKey, audience string, scopes []string, now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	if credentials.PrivateKeyID != "" {
+		header["kid"] = credentials.PrivateKeyID
+	}
+	claims := map[string]any{
+		"iss":   credentials.ClientEmail,
+		"scope": strings.Join(scopes, " "),
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(googleTokenLifetime).Unix(),
+	}
+
+	var parts []string
+	for _, part := range []any{header, claims} {
+		encoded, err := json.Marshal(part)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, base64.RawURLEncoding.EncodeToString(encoded))
+	}
+	signingInput := strings.Join(parts, ".")
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing JWT: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseRSAPrivateKey parses a PEM encoded PKCS #8 or PKCS #1 RSA private key
+func parseRSAPrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("no PEM private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported %T private key", parsed)
+	}
+	return key, nil
+}
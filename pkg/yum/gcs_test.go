@@ -0,0 +1,170 @@
+package yum
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCSClient(t *testing.T) {
+	backend := server()
+	defer backend.Close()
+	backendURL, _ := url.Parse(backend.URL)
+
+	var paths []string
+	gcs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gcs-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		paths = append(paths, r.URL.Path)
+		http.StripPrefix("/mirror-bucket/epel", httputil.NewSingleHostReverseProxy(backendURL)).ServeHTTP(w, r)
+	}))
+	defer gcs.Close()
+
+	client := &GCSClient{Token: StaticToken("gcs-token"), Endpoint: gcs.URL}
+	r, _ := NewRepository(YummySettings{Client: client, URL: Ptr("gs://mirror-bucket/epel")})
+
+	packages, code, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Len(t, packages, 2)
+	assert.Equal(t, []string{"/mirror-bucket/epel/repodata/repomd.xml", "/mirror-bucket/epel/repodata/primary.xml.gz"}, paths)
+
+	// other URLs are fetched as they are, without the token
+	req, _ := http.NewRequest(http.MethodGet, backend.URL+"/gpgkey.pub", nil)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, paths, 2)
+
+	client.Token = StaticToken("wrong")
+	r.Clear()
+	_, code, err = r.Repomd(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestGoogleServiceAccountToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	var requests int
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, googleJWTGrantType, r.PostForm.Get("grant_type"))
+
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		require.Len(t, parts, 3)
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+		var claims map[string]any
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, json.Unmarshal(payload, &claims))
+		assert.Equal(t, "mirror@project.iam.gserviceaccount.com", claims["iss"])
+		assert.Equal(t, GCSReadOnlyScope, claims["scope"])
+
+		_, _ = w.Write([]byte(`{"access_token": "sa-token", "expires_in": 3600, "token_type": "Bearer"}`))
+	}))
+	defer tokenServer.Close()
+
+	credentials, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "mirror@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})),
+		"token_uri":    tokenServer.URL,
+	})
+	source, err := GoogleCredentialsTokenSource(credentials, nil, GCSReadOnlyScope)
+	require.NoError(t, err)
+
+	for range 2 {
+		token, err := source(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "sa-token", token)
+	}
+	assert.Equal(t, 1, requests)
+}
+
+func TestGoogleAuthorizedUserToken(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "refresh" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token": "user-token", "expires_in": 3599}`))
+	}))
+	defer tokenServer.Close()
+
+	dir := t.TempDir()
+	credentialsPath := filepath.Join(dir, "credentials.json")
+	require.NoError(t, os.WriteFile(credentialsPath, []byte(`{"type": "authorized_user", "client_id": "id", "client_secret": "secret",
+"refresh_token": "refresh", "token_uri": "`+tokenServer.URL+`"}`), 0600))
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", credentialsPath)
+
+	source, err := GoogleDefaultTokenSource(nil, GCSReadOnlyScope)
+	require.NoError(t, err)
+	token, err := source(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "user-token", token)
+
+	source, err = GoogleCredentialsTokenSource([]byte(`{"type": "authorized_user", "refresh_token": "expired", "token_uri": "`+tokenServer.URL+`"}`), nil)
+	require.NoError(t, err)
+	_, err = source(context.Background())
+	assert.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestGoogleMetadataToken(t *testing.T) {
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, "/computeMetadata/v1/instance/service-accounts/default/token", r.URL.Path)
+		assert.Equal(t, GCSReadOnlyScope, r.URL.Query().Get("scopes"))
+		_, _ = w.Write([]byte(`{"access_token": "metadata-token", "expires_in": "3599"}`))
+	}))
+	defer metadata.Close()
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(metadata.URL, "http://"))
+
+	source, err := GoogleDefaultTokenSource(nil, GCSReadOnlyScope)
+	require.NoError(t, err)
+	token, err := source(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "metadata-token", token)
+}
+
+func TestGoogleCredentialsUnsupported(t *testing.T) {
+	_, err := GoogleCredentialsTokenSource([]byte(`{"type": "external_account"}`), nil)
+	assert.ErrorContains(t, err, "external_account")
+
+	_, err = GoogleCredentialsTokenSource([]byte(`{"type": "service_account", "private_key": "none"}`), nil)
+	assert.Error(t, err)
+}
@@ -4,13 +4,72 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
 )
 
+// GPGKeyInfo describes a public key, so callers can display and pin its fingerprint
+type GPGKeyInfo struct {
+	Fingerprint string          `json:"fingerprint"`       // upper case hex fingerprint of the primary key
+	KeyID       string          `json:"keyId"`             // upper case hex long key id of the primary key
+	Created     time.Time       `json:"created"`           // creation time of the primary key
+	Expires     *time.Time      `json:"expires,omitempty"` // expiry of the primary key, nil if it does not expire
+	Revoked     bool            `json:"revoked"`
+	UserIDs     []string        `json:"userIds,omitempty"`
+	Subkeys     []GPGSubkeyInfo `json:"subkeys,omitempty"`
+}
+
+// GPGSubkeyInfo describes a subkey of a public key
+type GPGSubkeyInfo struct {
+	Fingerprint string     `json:"fingerprint"`
+	KeyID       string     `json:"keyId"`
+	Created     time.Time  `json:"created"`
+	Expires     *time.Time `json:"expires,omitempty"`
+	Revoked     bool       `json:"revoked"`
+}
+
 // FetchGPGKey GETs GPG Key from url with request timeout maximum timeout.
-func FetchGPGKey(ctx context.Context, url string, client *http.Client) (*string, int, error) {
+// With WithFingerprint, url is instead a keyserver the key is looked up on, and with WithWKD the key
+// is first looked up in the Web Key Directory of an email address. See GPGKeyOption.
+func FetchGPGKey(ctx context.Context, url string, client HTTPDoer, opts ...GPGKeyOption) (*string, int, error) {
+	var lookup gpgKeyLookup
+	for _, opt := range opts {
+		opt(&lookup)
+	}
+
+	if lookup.wkdAddress != "" {
+		gpgKeyString, code, err := fetchWKDKey(ctx, lookup.wkdAddress, client)
+		if err == nil {
+			gpgKeyString, err = selectFingerprint(*gpgKeyString, lookup.fingerprint)
+		}
+		if err == nil || url == "" {
+			return gpgKeyString, code, err
+		}
+	}
+
+	if lookup.fingerprint != "" {
+		keyserverURL, err := hkpLookupURL(url, lookup.fingerprint)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error parsing keyserver URL: %w", err)
+		}
+		url = keyserverURL
+	}
+
+	gpgKeyString, code, err := fetchGPGKey(ctx, url, client)
+	if err == nil {
+		gpgKeyString, err = selectFingerprint(*gpgKeyString, lookup.fingerprint)
+	}
+	if err != nil {
+		return nil, code, err
+	}
+	return gpgKeyString, code, nil
+}
+
+func fetchGPGKey(ctx context.Context, url string, client HTTPDoer) (*string, int, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error creating request: %w", err)
@@ -21,15 +80,74 @@ func FetchGPGKey(ctx context.Context, url string, client *http.Client) (*string,
 	}
 	defer resp.Body.Close()
 	code := resp.StatusCode
-	gpgKeyString, err := responseBodyToString(resp.Body)
+	body, err := readGPGKeyBody(resp.Body)
 	if err != nil {
 		return nil, 0, err
 	}
-	if err == nil && code < 200 || code > 299 {
+	gpgKeyString := Ptr(string(body))
+	if code < 200 || code > 299 {
 		return nil, code, fmt.Errorf("received http %d", code)
 	}
-	if _, openpgpErr := openpgp.ReadArmoredKeyRing(strings.NewReader(*gpgKeyString)); err != nil {
-		return nil, code, openpgpErr //Bad key
+	if err := checkGPGKey(*gpgKeyString); err != nil {
+		return nil, code, err
 	}
 	return gpgKeyString, code, nil
 }
+
+// checkGPGKey returns an error unless gpgKey is an armored key or key ring, so that a page served in place
+// of a key, such as a catch-all HTML page, is not taken for one
+func checkGPGKey(gpgKey string) error {
+	if _, err := openpgp.ReadArmoredKeyRing(strings.NewReader(gpgKey)); err != nil {
+		return fmt.Errorf("error reading gpg key: %w", err)
+	}
+	return nil
+}
+
+// ParseGPGKeyInfo returns information on every key in an armored key or key ring
+func ParseGPGKeyInfo(gpgKey string) ([]GPGKeyInfo, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(gpgKey))
+	if err != nil {
+		return nil, fmt.Errorf("error reading gpg key: %w", err)
+	}
+
+	now := time.Now()
+	infos := make([]GPGKeyInfo, 0, len(entities))
+	for _, entity := range entities {
+		info := GPGKeyInfo{
+			Fingerprint: fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint),
+			KeyID:       entity.PrimaryKey.KeyIdString(),
+			Created:     entity.PrimaryKey.CreationTime,
+			Revoked:     entity.Revoked(now),
+			UserIDs:     []string{},
+			Subkeys:     []GPGSubkeyInfo{},
+		}
+		if identity := entity.PrimaryIdentity(); identity != nil {
+			info.Expires = keyExpiry(entity.PrimaryKey, identity.SelfSignature)
+		}
+		for name := range entity.Identities {
+			info.UserIDs = append(info.UserIDs, name)
+		}
+		sort.Strings(info.UserIDs)
+
+		for _, subkey := range entity.Subkeys {
+			info.Subkeys = append(info.Subkeys, GPGSubkeyInfo{
+				Fingerprint: fmt.Sprintf("%X", subkey.PublicKey.Fingerprint),
+				KeyID:       subkey.PublicKey.KeyIdString(),
+				Created:     subkey.PublicKey.CreationTime,
+				Expires:     keyExpiry(subkey.PublicKey, subkey.Sig),
+				Revoked:     subkey.Revoked(now),
+			})
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// keyExpiry returns the expiry set by a key's self signature, or nil if the key does not expire
+func keyExpiry(key *packet.PublicKey, sig *packet.Signature) *time.Time {
+	if sig == nil || sig.KeyLifetimeSecs == nil || *sig.KeyLifetimeSecs == 0 {
+		return nil
+	}
+	expiry := key.CreationTime.Add(time.Duration(*sig.KeyLifetimeSecs) * time.Second)
+	return &expiry
+}
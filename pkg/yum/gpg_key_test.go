@@ -4,9 +4,11 @@ import (
 	"context"
 	_ "embed"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 //go:embed "mocks/gpgkey.pub"
@@ -24,8 +26,80 @@ func TestFetchGPGKey(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestFetchGPGKeyNotAKey(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>not a key</html>"))
+	}))
+	defer s.Close()
+
+	gpg, code, err := FetchGPGKey(context.Background(), s.URL+"/gpgkey.pub", s.Client())
+	assert.ErrorContains(t, err, "error reading gpg key")
+	assert.Nil(t, gpg)
+	assert.Equal(t, 200, code)
+}
+
 func serveGPGKey(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/octet-stream")
 	body := gpgKey
 	_, _ = w.Write(body)
 }
+
+func TestParseGPGKeyInfo(t *testing.T) {
+	infos, err := ParseGPGKeyInfo(string(gpgKey))
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	info := infos[0]
+	assert.Equal(t, "BC528686B50D79E339D3721CEB3E94ADBE1229CF", info.Fingerprint)
+	assert.Equal(t, "EB3E94ADBE1229CF", info.KeyID)
+	assert.Equal(t, 2015, info.Created.Year())
+	assert.Nil(t, info.Expires)
+	assert.False(t, info.Revoked)
+	assert.Equal(t, []string{"Microsoft (Release signing) <gpgsecurity@microsoft.com>"}, info.UserIDs)
+
+	_, err = ParseGPGKeyInfo("not a key")
+	assert.Error(t, err)
+}
+
+func TestRepositoryGPGKey(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	// the mock server does not serve repodata/repomd.xml.key
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	gpg, code, err := r.GPGKey(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, gpg)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, GPGKeyPaths: []string{"repodata/repomd.xml.key", "gpgkey.pub"}})
+	gpg, code, err = r.GPGKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, string(gpgKey), *gpg)
+}
+
+func TestRepositoryGPGKeyNotAKey(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gpgkey.pub" {
+			serveGPGKey(w, r)
+			return
+		}
+		// a catch-all page served for any path
+		_, _ = w.Write([]byte("<html>not a key</html>"))
+	}))
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	gpg, code, err := r.GPGKey(context.Background())
+	assert.ErrorContains(t, err, "error reading gpg key")
+	assert.Nil(t, gpg)
+	assert.Equal(t, 200, code)
+	assert.Nil(t, r.gpgKey)
+
+	// the page is skipped for the next path
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, GPGKeyPaths: []string{"repodata/repomd.xml.key", "gpgkey.pub"}})
+	gpg, code, err = r.GPGKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, string(gpgKey), *gpg)
+}
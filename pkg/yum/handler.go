@@ -0,0 +1,212 @@
+package yum
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler is an http.Handler re-serving a repository to yum clients from a local mirror directory,
+// making yummy a lightweight caching proxy. repomd.xml and its signature are served from the
+// repository's cache, while metadata files and packages are downloaded into the directory as by Sync
+// on first request. Paths not listed in the repository's metadata are not served.
+//
+// The Handler calls the Repository from several goroutines, so the Repository must not be used
+// elsewhere while the Handler serves it. Calling Refresh makes the Handler fetch repomd.xml again.
+type Handler struct {
+	repo      *Repository
+	dir       string
+	mutex     sync.Mutex              // guards repo, files, verified and downloads
+	files     map[string]syncFile     // files that can be served by path
+	verified  map[string]verifiedFile // files of the mirror directory verified against their checksum, by path
+	downloads map[string]*sync.Mutex  // serializes downloads of each path
+}
+
+// verifiedFile is the size and modification time of a file when it was verified, so that it is only read and
+// hashed again if it changed on disk
+type verifiedFile struct {
+	size    int64
+	modTime time.Time
+}
+
+// NewHandler returns a Handler serving repo from the mirror directory dir
+func NewHandler(repo *Repository, dir string) *Handler {
+	return &Handler{repo: repo, dir: dir, downloads: map[string]*sync.Mutex{}}
+}
+
+// Refresh drops the cached metadata, so the next request fetches repomd.xml from the repository again
+func (h *Handler) Refresh() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.repo.Clear()
+	h.files = nil
+	h.verified = nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	href := strings.TrimPrefix(path.Clean("/"+req.URL.Path), "/")
+	switch href {
+	case "repodata/repomd.xml":
+		h.serveRepomd(w, req)
+	case "repodata/repomd.xml.asc":
+		h.serveSignature(w, req)
+	default:
+		h.serveFile(w, req, href)
+	}
+}
+
+func (h *Handler) serveRepomd(w http.ResponseWriter, req *http.Request) {
+	h.mutex.Lock()
+	repomd, code, err := h.repo.Repomd(req.Context())
+	h.mutex.Unlock()
+	if err != nil {
+		h.serveError(w, req, code, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write([]byte(*repomd.RepomdString))
+}
+
+func (h *Handler) serveSignature(w http.ResponseWriter, req *http.Request) {
+	h.mutex.Lock()
+	sig, code, err := h.repo.Signature(req.Context())
+	h.mutex.Unlock()
+	if err != nil {
+		h.serveError(w, req, code, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pgp-signature")
+	_, _ = w.Write([]byte(*sig))
+}
+
+// serveFile serves a metadata file or package from the mirror directory, downloading it first if needed.
+// Files are verified against their checksum once, when they are downloaded or first served, and then served
+// as they are until they change on disk or the Handler is refreshed.
+func (h *Handler) serveFile(w http.ResponseWriter, req *http.Request, href string) {
+	file, download, code, err := h.lookup(req, href)
+	if err != nil {
+		h.serveError(w, req, code, err)
+		return
+	}
+	if download == nil {
+		http.NotFound(w, req)
+		return
+	}
+	filePath, err := syncPath(h.dir, href)
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	if !h.isVerified(href, filePath) {
+		download.Lock()
+		if !h.isVerified(href, filePath) {
+			if _, _, err = h.repo.syncFile(req.Context(), h.dir, file); err == nil {
+				h.setVerified(href, filePath, file)
+			}
+		}
+		download.Unlock()
+		if err != nil {
+			h.serveError(w, req, 0, err)
+			return
+		}
+	}
+	http.ServeFile(w, req, filePath)
+}
+
+// isVerified returns true if the file at filePath was verified and has not changed since
+func (h *Handler) isVerified(href string, filePath string) bool {
+	h.mutex.Lock()
+	verified, ok := h.verified[href]
+	h.mutex.Unlock()
+	if !ok {
+		return false
+	}
+	info, err := os.Stat(filePath)
+	return err == nil && info.Size() == verified.size && info.ModTime().Equal(verified.modTime)
+}
+
+// setVerified records that the file at filePath was verified against file, unless the Handler was refreshed
+// since and serves another version of it
+func (h *Handler) setVerified(href string, filePath string, file syncFile) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if current, ok := h.files[href]; !ok || current.checksum != file.checksum {
+		return
+	}
+	if h.verified == nil {
+		h.verified = map[string]verifiedFile{}
+	}
+	h.verified[href] = verifiedFile{size: info.Size(), modTime: info.ModTime()}
+}
+
+// lookup returns the file served at href and the lock serializing its downloads, or a nil lock if the
+// repository has no such file
+func (h *Handler) lookup(req *http.Request, href string) (syncFile, *sync.Mutex, int, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.files == nil {
+		repomd, code, err := h.repo.Repomd(req.Context())
+		if err != nil {
+			return syncFile{}, nil, code, err
+		}
+		packages, code, err := h.repo.Packages(req.Context())
+		if err != nil {
+			return syncFile{}, nil, code, err
+		}
+
+		files := map[string]syncFile{}
+		for _, file := range metadataSyncFiles(repomd) {
+			files[path.Clean(file.location.Href)] = file
+		}
+		for _, pkg := range packages {
+			files[path.Clean(pkg.Location.Href)] = syncFile{location: pkg.Location, checksum: pkg.Checksum, size: pkg.Size.Package}
+		}
+		h.files = files
+	}
+
+	file, ok := h.files[href]
+	if !ok {
+		return syncFile{}, nil, http.StatusOK, nil
+	}
+	if h.downloads[href] == nil {
+		h.downloads[href] = &sync.Mutex{}
+	}
+	return file, h.downloads[href], http.StatusOK, nil
+}
+
+// UpstreamError returns the status code and message a proxy or API returns to its clients when a request to a
+// repository failed with code: not found stays not found, anything else is a bad gateway. The message does
+// not include the error, which holds URLs that may carry credentials or signing tokens, and should be logged.
+func UpstreamError(code int) (int, string) {
+	status := http.StatusBadGateway
+	if code == http.StatusNotFound {
+		status = http.StatusNotFound
+	}
+	if code == 0 {
+		return status, "error fetching repository metadata"
+	}
+	return status, fmt.Sprintf("error fetching repository metadata: upstream status %d", code)
+}
+
+// serveError logs err and answers the request with the status of code and a message without details
+func (h *Handler) serveError(w http.ResponseWriter, req *http.Request, code int, err error) {
+	h.repo.logger().WarnContext(req.Context(), "proxy request failed", "path", req.URL.Path, "error", err)
+	status, message := UpstreamError(code)
+	http.Error(w, message, status)
+}
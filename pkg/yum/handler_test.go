@@ -0,0 +1,95 @@
+package yum
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	upstream := syncServer(t)
+	defer upstream.Close()
+	dir := t.TempDir()
+
+	repo, _ := NewRepository(YummySettings{Client: upstream.Client(), URL: &upstream.URL})
+	proxy := httptest.NewServer(NewHandler(&repo, dir))
+	defer proxy.Close()
+
+	get := func(href string) (int, string) {
+		resp, err := proxy.Client().Get(proxy.URL + "/" + href)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get("Packages/foo-1.0-1.x86_64.rpm")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "foo rpm", body)
+	assert.FileExists(t, filepath.Join(dir, "Packages", "foo-1.0-1.x86_64.rpm"))
+
+	code, _ = get("Packages/unknown.rpm")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = get("../../etc/passwd")
+	assert.Equal(t, http.StatusNotFound, code)
+	// the upstream repository has no signature, and errors do not show its URL
+	code, body = get("repodata/repomd.xml.asc")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "error fetching repository metadata: upstream status 404\n", body)
+
+	// yum clients can read the proxied repository
+	client, _ := NewRepository(YummySettings{Client: proxy.Client(), URL: &proxy.URL})
+	packages, _, err := client.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, packages, 2)
+}
+
+func TestHandlerVerifiesOnce(t *testing.T) {
+	upstream := syncServer(t)
+	defer upstream.Close()
+	dir := t.TempDir()
+
+	repo, _ := NewRepository(YummySettings{Client: upstream.Client(), URL: &upstream.URL})
+	handler := NewHandler(&repo, dir)
+	proxy := httptest.NewServer(handler)
+	defer proxy.Close()
+
+	get := func() string {
+		resp, err := proxy.Client().Get(proxy.URL + "/Packages/foo-1.0-1.x86_64.rpm")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+	assert.Equal(t, "foo rpm", get())
+
+	// a verified file is served without being hashed again while its size and modification time are the same
+	filePath := filepath.Join(dir, "Packages", "foo-1.0-1.x86_64.rpm")
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filePath, []byte("bar rpm"), 0644))
+	require.NoError(t, os.Chtimes(filePath, info.ModTime(), info.ModTime()))
+	assert.Equal(t, "bar rpm", get())
+
+	// a file changed on disk is verified, and downloaded, again
+	require.NoError(t, os.WriteFile(filePath, []byte("corrupted"), 0644))
+	assert.Equal(t, "foo rpm", get())
+
+	// as is every file once the handler is refreshed
+	info, err = os.Stat(filePath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filePath, []byte("bar rpm"), 0644))
+	require.NoError(t, os.Chtimes(filePath, info.ModTime(), info.ModTime()))
+	assert.Equal(t, "bar rpm", get())
+	handler.Refresh()
+	assert.Equal(t, "foo rpm", get())
+}
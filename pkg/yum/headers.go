@@ -0,0 +1,70 @@
+package yum
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ResponseHeaders are the caching headers of the last successful response for a type of metadata
+type ResponseHeaders struct {
+	LastModified  time.Time     `json:"lastModified"`  // Zero if the response had no valid Last-Modified header
+	ETag          string        `json:"etag"`          // Entity tag, quoted and possibly weak as sent by the server
+	ContentLength int64         `json:"contentLength"` // -1 if unknown
+	Age           time.Duration `json:"age"`           // Time the response spent in caches, zero if not cached
+	Date          time.Time     `json:"date"`          // Zero if the response had no valid Date header
+}
+
+// parseResponseHeaders reads the caching headers of resp
+func parseResponseHeaders(resp *http.Response) ResponseHeaders {
+	headers := ResponseHeaders{
+		ETag:          resp.Header.Get("ETag"),
+		ContentLength: resp.ContentLength,
+	}
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		headers.LastModified = lastModified
+	}
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		headers.Date = date
+	}
+	if age, err := strconv.ParseInt(resp.Header.Get("Age"), 10, 64); err == nil && age > 0 {
+		headers.Age = time.Duration(age) * time.Second
+	}
+	return headers
+}
+
+// responseHeaders holds the ResponseHeaders of the last successful response by metadata type. Every
+// method is a no-op on a nil *responseHeaders.
+type responseHeaders struct {
+	mu     sync.Mutex
+	byType map[string]ResponseHeaders
+}
+
+func newResponseHeaders() *responseHeaders {
+	return &responseHeaders{byType: map[string]ResponseHeaders{}}
+}
+
+// record keeps the headers of resp if it is successful
+func (h *responseHeaders) record(metadataType string, resp *http.Response, err error) {
+	if h == nil || err != nil || resp.StatusCode != http.StatusOK {
+		return
+	}
+	headers := parseResponseHeaders(resp)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.byType[metadataType] = headers
+}
+
+// ResponseHeaders returns the caching headers of the last successful response for a type of metadata, such
+// as "repomd" or "primary", so that callers can make their own freshness decisions. They are kept across
+// calls to Clear. Returns false if no response was received yet.
+func (r *Repository) ResponseHeaders(metadataType string) (ResponseHeaders, bool) {
+	if r.headers == nil {
+		return ResponseHeaders{}, false
+	}
+	r.headers.mu.Lock()
+	defer r.headers.mu.Unlock()
+	headers, ok := r.headers.byType[metadataType]
+	return headers, ok
+}
@@ -0,0 +1,55 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseHeaders(t *testing.T) {
+	lastModified := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		w.Header().Set("ETag", `"abc123"`)
+		w.Header().Set("Age", "42")
+		serveRepomdXML(w, r)
+	})
+	mux.HandleFunc("/repodata/primary.xml.gz", servePrimaryXML)
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	_, ok := r.ResponseHeaders("repomd")
+	assert.False(t, ok)
+
+	_, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+
+	repomd, ok := r.ResponseHeaders("repomd")
+	assert.True(t, ok)
+	assert.Equal(t, lastModified, repomd.LastModified)
+	assert.Equal(t, `"abc123"`, repomd.ETag)
+	assert.Equal(t, int64(len(repomdXML)), repomd.ContentLength)
+	assert.Equal(t, 42*time.Second, repomd.Age)
+	assert.False(t, repomd.Date.IsZero())
+
+	primary, ok := r.ResponseHeaders("primary")
+	assert.True(t, ok)
+	assert.True(t, primary.LastModified.IsZero())
+	assert.Empty(t, primary.ETag)
+	assert.Zero(t, primary.Age)
+
+	// failed responses do not replace the headers
+	s.Config.Handler = http.NotFoundHandler()
+	r.Clear()
+	_, _, err = r.Repomd(context.Background())
+	assert.Error(t, err)
+	repomd, _ = r.ResponseHeaders("repomd")
+	assert.Equal(t, `"abc123"`, repomd.ETag)
+}
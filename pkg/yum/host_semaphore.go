@@ -0,0 +1,54 @@
+package yum
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+)
+
+// HostSemaphore caps the number of concurrent requests to each host, so that FetchAll, Sync and bulk downloads
+// do not trip the connection limits of a CDN or mirror. Repositories given the same HostSemaphore as their
+// YummySettings.HostSemaphore share its caps.
+type HostSemaphore struct {
+	max   int
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// NewHostSemaphore returns a HostSemaphore allowing up to max concurrent requests to each host
+func NewHostSemaphore(max int) *HostSemaphore {
+	return &HostSemaphore{max: max, slots: map[string]chan struct{}{}}
+}
+
+// acquire waits for a slot for a request to host, and returns the function releasing it
+func (s *HostSemaphore) acquire(ctx context.Context, host string) (func(), error) {
+	host = strings.ToLower(host)
+	s.mu.Lock()
+	slots, ok := s.slots[host]
+	if !ok {
+		slots = make(chan struct{}, max(s.max, 1))
+		s.slots[host] = slots
+	}
+	s.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-slots }) }, nil
+}
+
+// releasingBody is a response body releasing the slot of its request when it is closed, as the connection is
+// in use until then
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,75 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostSemaphore(t *testing.T) {
+	backend := filelistsServer()
+	defer backend.Close()
+
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		backend.Config.Handler.ServeHTTP(w, r)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer s.Close()
+
+	semaphore := NewHostSemaphore(2)
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, HostSemaphore: semaphore})
+	results, _ := r.FetchAll(context.Background())
+	for _, metadataType := range []string{"repomd", "primary", "group", "modules", "updateinfo", "filelists"} {
+		assert.NoError(t, results[metadataType].Err, metadataType)
+	}
+	assert.LessOrEqual(t, maxInFlight, 2)
+
+	// every slot is released once the responses are read
+	release, err := semaphore.acquire(context.Background(), s.Listener.Addr().String())
+	require.NoError(t, err)
+	release()
+	assert.Empty(t, semaphore.slots[s.Listener.Addr().String()])
+}
+
+func TestHostSemaphoreWait(t *testing.T) {
+	semaphore := NewHostSemaphore(1)
+	release, err := semaphore.acquire(context.Background(), "cdn.example.com")
+	require.NoError(t, err)
+
+	// other hosts have their own slots
+	other, err := semaphore.acquire(context.Background(), "mirror.example.com")
+	require.NoError(t, err)
+	other()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = semaphore.acquire(ctx, "CDN.example.com")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release()
+	next, err := semaphore.acquire(context.Background(), "cdn.example.com")
+	require.NoError(t, err)
+	next()
+
+	url := "http://cdn.example.com/"
+	_, _ = semaphore.acquire(context.Background(), "cdn.example.com")
+	r, _ := NewRepository(YummySettings{Client: &handlerDoer{}, URL: &url, HostSemaphore: semaphore})
+	_, _, err = r.Repomd(ctx)
+	assert.ErrorContains(t, err, "host semaphore")
+}
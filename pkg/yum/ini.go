@@ -0,0 +1,76 @@
+package yum
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// iniSection is a named section of an INI file, such as .treeinfo or dnf .repo files
+type iniSection struct {
+	Name string
+	Keys map[string]string
+}
+
+// parseINI parses an INI file into its sections, in the order they appear. Lines starting with
+// # or ; are comments, and indented lines continue the value of the previous key.
+func parseINI(reader io.Reader) ([]iniSection, error) {
+	sections := []iniSection{}
+	var current *iniSection
+	var lastKey string
+
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: invalid section header %v", lineNumber, line)
+			}
+			sections = append(sections, iniSection{Name: strings.TrimSpace(line[1 : len(line)-1]), Keys: map[string]string{}})
+			current = &sections[len(sections)-1]
+			lastKey = ""
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("line %d: key outside of a section", lineNumber)
+		}
+
+		if raw[0] == ' ' || raw[0] == '\t' {
+			if lastKey != "" {
+				current.Keys[lastKey] = strings.TrimSpace(current.Keys[lastKey] + "\n" + line)
+				continue
+			}
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		lastKey = strings.TrimSpace(key)
+		current.Keys[lastKey] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// iniSectionByName returns the first section with the given name
+func iniSectionByName(sections []iniSection, name string) (iniSection, bool) {
+	for _, section := range sections {
+		if section.Name == name {
+			return section, true
+		}
+	}
+	return iniSection{}, false
+}
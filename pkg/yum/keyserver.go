@@ -0,0 +1,207 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// maxGPGKeySize is the size of the largest key FetchGPGKey reads
+const maxGPGKeySize = 4 << 20
+
+// GPGKeyOption changes how FetchGPGKey resolves a key, for repositories that only publish a fingerprint
+type GPGKeyOption func(*gpgKeyLookup)
+
+type gpgKeyLookup struct {
+	fingerprint string
+	wkdAddress  string
+}
+
+// WithFingerprint looks the key up by fingerprint on the HKP keyserver at the url passed to FetchGPGKey,
+// such as "hkps://keys.openpgp.org". The fetched key, however it was resolved, must have this fingerprint.
+func WithFingerprint(fingerprint string) GPGKeyOption {
+	return func(l *gpgKeyLookup) {
+		l.fingerprint = normalizeFingerprint(fingerprint)
+	}
+}
+
+// WithWKD looks the key up in the Web Key Directory of the email address before falling back to the
+// url passed to FetchGPGKey, if any.
+func WithWKD(address string) GPGKeyOption {
+	return func(l *gpgKeyLookup) {
+		l.wkdAddress = address
+	}
+}
+
+func normalizeFingerprint(fingerprint string) string {
+	fingerprint = strings.TrimPrefix(strings.ReplaceAll(fingerprint, " ", ""), "0x")
+	return strings.ToUpper(fingerprint)
+}
+
+// hkpLookupURL returns the HKP lookup URL of a fingerprint on a keyserver. The hkp and hkps schemes
+// are mapped to http on port 11371 and https.
+func hkpLookupURL(keyserver string, fingerprint string) (string, error) {
+	u, err := url.Parse(keyserver)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "hkp":
+		u.Scheme = "http"
+		if u.Port() == "" {
+			u.Host = u.Host + ":11371"
+		}
+	case "hkps":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("unsupported keyserver scheme %q", u.Scheme)
+	}
+	u.Path = "/pks/lookup"
+	u.RawQuery = url.Values{"op": {"get"}, "options": {"mr"}, "search": {"0x" + fingerprint}}.Encode()
+	return u.String(), nil
+}
+
+// wkdURLs returns the advanced and direct Web Key Directory URLs of an email address
+func wkdURLs(address string) ([]string, error) {
+	local, domain, found := strings.Cut(address, "@")
+	if !found || local == "" || domain == "" {
+		return nil, fmt.Errorf("invalid email address %v", address)
+	}
+	domain = strings.ToLower(domain)
+	hash := sha1.Sum([]byte(strings.ToLower(local)))
+	hu := zbase32(hash[:]) + "?l=" + url.QueryEscape(local)
+	return []string{
+		fmt.Sprintf("https://openpgpkey.%v/.well-known/openpgpkey/%v/hu/%v", domain, domain, hu),
+		fmt.Sprintf("https://%v/.well-known/openpgpkey/hu/%v", domain, hu),
+	}, nil
+}
+
+// fetchWKDKey fetches the binary key of an email address from its Web Key Directory and armors it
+func fetchWKDKey(ctx context.Context, address string, client HTTPDoer) (*string, int, error) {
+	urls, err := wkdURLs(address)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var code int
+	for _, wkdURL := range urls {
+		req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, wkdURL, nil)
+		if reqErr != nil {
+			return nil, 0, fmt.Errorf("error creating request: %w", reqErr)
+		}
+		resp, doErr := client.Do(req)
+		if doErr != nil {
+			code, err = erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", wkdURL, doErr)
+			continue
+		}
+		code = resp.StatusCode
+		body, readErr := readGPGKeyBody(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			err = readErr
+			continue
+		}
+		if code != http.StatusOK {
+			err = fmt.Errorf("Cannot fetch %v: %d", wkdURL, code)
+			continue
+		}
+		if _, keyErr := openpgp.ReadKeyRing(bytes.NewReader(body)); keyErr != nil {
+			return nil, code, fmt.Errorf("error reading gpg key: %w", keyErr)
+		}
+
+		armored, armorErr := armorPublicKey(func(w io.Writer) error {
+			_, err := w.Write(body)
+			return err
+		})
+		if armorErr != nil {
+			return nil, code, armorErr
+		}
+		return &armored, code, nil
+	}
+	return nil, code, err
+}
+
+// selectFingerprint returns the key of the armored key ring with the given primary key fingerprint. Other keys
+// a keyserver or Web Key Directory returned along with it are dropped, so that they are not trusted too: the
+// key is armored again on its own if there are any. An empty fingerprint selects the whole key ring.
+func selectFingerprint(gpgKey string, fingerprint string) (*string, error) {
+	if fingerprint == "" {
+		return &gpgKey, nil
+	}
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(gpgKey))
+	if err != nil {
+		return nil, fmt.Errorf("error reading gpg key: %w", err)
+	}
+	for _, entity := range entities {
+		if fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint) != fingerprint {
+			continue
+		}
+		if len(entities) == 1 {
+			return &gpgKey, nil
+		}
+		armored, err := armorPublicKey(entity.Serialize)
+		if err != nil {
+			return nil, err
+		}
+		return &armored, nil
+	}
+	return nil, fmt.Errorf("gpg key does not match fingerprint %v", fingerprint)
+}
+
+// armorPublicKey armors the binary public key written by write
+func armorPublicKey(write func(w io.Writer) error) (string, error) {
+	var armored bytes.Buffer
+	w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return "", err
+	}
+	if err = write(w); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	return armored.String(), nil
+}
+
+// readGPGKeyBody reads a key from a response body, failing if it is larger than maxGPGKeySize, so that a
+// keyserver cannot make the key fetched take unbounded memory
+func readGPGKeyBody(body io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(body, maxGPGKeySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxGPGKeySize {
+		return nil, fmt.Errorf("gpg key larger than %d bytes", maxGPGKeySize)
+	}
+	return content, nil
+}
+
+const zbase32Alphabet = "ybndrfg8ejkmcpqxot1uwisza345h769"
+
+// zbase32 encodes data as z-base-32, as used by Web Key Directory
+func zbase32(data []byte) string {
+	var result strings.Builder
+	var buffer, bits uint
+	for _, b := range data {
+		buffer = buffer<<8 | uint(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			result.WriteByte(zbase32Alphabet[buffer>>bits&31])
+		}
+	}
+	if bits > 0 {
+		result.WriteByte(zbase32Alphabet[buffer<<(5-bits)&31])
+	}
+	return result.String()
+}
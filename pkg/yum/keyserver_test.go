@@ -0,0 +1,132 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockKeyFingerprint = "BC528686B50D79E339D3721CEB3E94ADBE1229CF"
+
+func TestHKPLookupURL(t *testing.T) {
+	lookupURL, err := hkpLookupURL("hkps://keys.openpgp.org", mockKeyFingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, "https://keys.openpgp.org/pks/lookup?op=get&options=mr&search=0x"+mockKeyFingerprint, lookupURL)
+
+	lookupURL, err = hkpLookupURL("hkp://keyserver.ubuntu.com", mockKeyFingerprint)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(lookupURL, "http://keyserver.ubuntu.com:11371/pks/lookup?"))
+
+	_, err = hkpLookupURL("ftp://keys.openpgp.org", mockKeyFingerprint)
+	assert.Error(t, err)
+}
+
+func TestWKDURLs(t *testing.T) {
+	// example from the Web Key Directory draft
+	urls, err := wkdURLs("Joe.Doe@Example.ORG")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://openpgpkey.example.org/.well-known/openpgpkey/example.org/hu/iy9q119eutrkn8s1mk4r39qejnbu3n5q?l=Joe.Doe",
+		"https://example.org/.well-known/openpgpkey/hu/iy9q119eutrkn8s1mk4r39qejnbu3n5q?l=Joe.Doe",
+	}, urls)
+
+	_, err = wkdURLs("not an address")
+	assert.Error(t, err)
+}
+
+func TestFetchGPGKeyByFingerprint(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pks/lookup", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("search") != "0x"+mockKeyFingerprint {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		serveGPGKey(w, r)
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	gpg, code, err := FetchGPGKey(context.Background(), s.URL, s.Client(), WithFingerprint("bc52 8686 b50d 79e3 39d3 721c eb3e 94ad be12 29cf"))
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, string(gpgKey), *gpg)
+
+	_, code, err = FetchGPGKey(context.Background(), s.URL, s.Client(), WithFingerprint("0000000000000000000000000000000000000000"))
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestFetchGPGKeyFromWKD(t *testing.T) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(string(gpgKey)))
+	require.NoError(t, err)
+	var binaryKey strings.Builder
+	require.NoError(t, entities[0].Serialize(&binaryKey))
+
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/.well-known/openpgpkey/hu/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(binaryKey.String()))
+	}))
+	defer s.Close()
+
+	// the direct method is used, as openpgpkey.<host> does not resolve
+	address := "gpgsecurity@" + strings.TrimPrefix(s.URL, "https://")
+	gpg, code, err := FetchGPGKey(context.Background(), "", s.Client(), WithWKD(address), WithFingerprint(mockKeyFingerprint))
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	infos, err := ParseGPGKeyInfo(*gpg)
+	require.NoError(t, err)
+	assert.Equal(t, mockKeyFingerprint, infos[0].Fingerprint)
+}
+
+func TestFetchGPGKeyByFingerprintExtraKeys(t *testing.T) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(string(gpgKey)))
+	require.NoError(t, err)
+	other, err := openpgp.NewEntity("other", "", "other@example.com", nil)
+	require.NoError(t, err)
+	keyRing, err := armorPublicKey(func(w io.Writer) error {
+		if err := other.Serialize(w); err != nil {
+			return err
+		}
+		return entities[0].Serialize(w)
+	})
+	require.NoError(t, err)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(keyRing))
+	}))
+	defer s.Close()
+
+	// only the key with the fingerprint is returned
+	gpg, _, err := FetchGPGKey(context.Background(), s.URL, s.Client(), WithFingerprint(mockKeyFingerprint))
+	require.NoError(t, err)
+	infos, err := ParseGPGKeyInfo(*gpg)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, mockKeyFingerprint, infos[0].Fingerprint)
+
+	// without a fingerprint, the key ring is returned as it is
+	gpg, _, err = FetchGPGKey(context.Background(), s.URL, s.Client())
+	require.NoError(t, err)
+	assert.Equal(t, keyRing, *gpg)
+}
+
+func TestFetchGPGKeyTooLarge(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("a"), maxGPGKeySize+1))
+	}))
+	defer s.Close()
+
+	_, _, err := FetchGPGKey(context.Background(), s.URL, s.Client(), WithFingerprint(mockKeyFingerprint))
+	assert.ErrorContains(t, err, "larger than")
+}
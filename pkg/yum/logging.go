@@ -0,0 +1,50 @@
+package yum
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// discardLogger is the logger of repositories without a Logger, which logs nothing
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler that is never enabled
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// logger returns the Logger of the settings, or a logger that logs nothing if none is set
+func (r *Repository) logger() *slog.Logger {
+	if r.settings.Logger == nil {
+		return discardLogger
+	}
+	return r.settings.Logger
+}
+
+// cacheRequest logs and counts a request for metadata of the given type, served from cache if hit is true
+func (r *Repository) cacheRequest(ctx context.Context, metadataType string, hit bool) {
+	r.observers().cacheRequest(metadataType, hit)
+	if hit {
+		r.logger().DebugContext(ctx, "metadata served from cache", "type", metadataType)
+	}
+}
+
+// logResponse logs the response to a request for a file of the given metadata type, at warn level if the
+// request failed
+func (r *Repository) logResponse(req *http.Request, metadataType string, duration time.Duration, resp *http.Response, err error) {
+	logger := r.logger()
+	if err != nil {
+		logger.WarnContext(req.Context(), "fetch failed", "type", metadataType, "url", req.URL.Redacted(), "duration", duration, "error", err)
+		return
+	}
+	level := slog.LevelDebug
+	if resp.StatusCode >= http.StatusBadRequest {
+		level = slog.LevelWarn
+	}
+	logger.Log(req.Context(), level, "fetched", "type", metadataType, "url", req.URL.Redacted(), "status", resp.StatusCode, "duration", duration)
+}
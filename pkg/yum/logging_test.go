@@ -0,0 +1,89 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogging(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, Logger: logger})
+
+	_, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	_, _, err = r.Packages(context.Background())
+	require.NoError(t, err)
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	fetched := map[string]float64{}
+	cacheHits := map[string]int{}
+	for _, record := range records {
+		switch record["msg"] {
+		case "fetched":
+			assert.Equal(t, "DEBUG", record["level"])
+			fetched[record["type"].(string)] = record["status"].(float64)
+		case "metadata served from cache":
+			cacheHits[record["type"].(string)]++
+		}
+	}
+	assert.Equal(t, map[string]float64{"repomd": 200, "primary": 200}, fetched)
+	assert.Equal(t, 1, cacheHits["primary"])
+	assert.Greater(t, cacheHits["repomd"], 0)
+}
+
+func TestLoggingFailedFetch(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	url := strings.Replace(s.URL, "http://", "http://user:secret@", 1) + "/missing"
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &url, Logger: logger})
+
+	_, _, err := r.Repomd(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "level=WARN msg=fetched type=repomd")
+	assert.Contains(t, buf.String(), "status=404")
+	assert.Contains(t, buf.String(), "user:xxxxx@")
+	assert.NotContains(t, buf.String(), "secret")
+}
+
+func TestParseModuleDocumentsSkipped(t *testing.T) {
+	yaml := `---
+document: modulemd-defaults
+version: 1
+data:
+  module: nodejs
+---
+document: modulemd-packager
+version: 2
+data:
+  name: nodejs
+---
+document: unknown
+version: 1
+...
+`
+	documents, err := parseModuleDocuments(io.NopCloser(strings.NewReader(yaml)))
+	require.NoError(t, err)
+	assert.Empty(t, documents.moduleMDs)
+	assert.Equal(t, map[string]int{"modulemd-packager version 2": 1, "unknown": 1}, documents.skipped)
+}
@@ -0,0 +1,252 @@
+package yum
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics are the Prometheus collectors a repository reports to when YummySettings.MetricsRegisterer is
+// set, as an observer of its requests. Metrics are labeled by metadata type, such as "repomd" or "primary".
+type metrics struct {
+	fetchDuration     *prometheus.HistogramVec
+	downloadedBytes   *prometheus.CounterVec
+	decompressedBytes *prometheus.CounterVec
+	parsedItems       *prometheus.CounterVec
+	cacheRequests     *prometheus.CounterVec
+	httpResponses     *prometheus.CounterVec
+}
+
+// newMetrics creates the collectors and registers them with registerer. Collectors already registered by
+// another repository are shared, so any number of repositories can report to the same registerer.
+func newMetrics(registerer prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "yummy_fetch_duration_seconds",
+			Help: "Duration of metadata and package fetches, until the response body is closed.",
+		}, []string{"type"}),
+		downloadedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "yummy_downloaded_bytes_total",
+			Help: "Bytes of response bodies read.",
+		}, []string{"type"}),
+		decompressedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "yummy_decompressed_bytes_total",
+			Help: "Bytes of metadata read after decompression.",
+		}, []string{"type"}),
+		parsedItems: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "yummy_parsed_items_total",
+			Help: "Items parsed from metadata, such as packages or advisories.",
+		}, []string{"type"}),
+		cacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "yummy_cache_requests_total",
+			Help: "Requests for metadata, by whether they were served from the repository's cache.",
+		}, []string{"type", "result"}),
+		httpResponses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "yummy_http_responses_total",
+			Help: "HTTP responses received, by status code.",
+		}, []string{"type", "code"}),
+	}
+
+	var err error
+	if m.fetchDuration, err = register(registerer, m.fetchDuration); err != nil {
+		return nil, err
+	}
+	if m.downloadedBytes, err = register(registerer, m.downloadedBytes); err != nil {
+		return nil, err
+	}
+	if m.decompressedBytes, err = register(registerer, m.decompressedBytes); err != nil {
+		return nil, err
+	}
+	if m.parsedItems, err = register(registerer, m.parsedItems); err != nil {
+		return nil, err
+	}
+	if m.cacheRequests, err = register(registerer, m.cacheRequests); err != nil {
+		return nil, err
+	}
+	if m.httpResponses, err = register(registerer, m.httpResponses); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// register registers collector, returning the collector registered before it if there is one
+func register[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	err := registerer.Register(collector)
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+			return existing, nil
+		}
+	}
+	return collector, err
+}
+
+// fetched counts the status code of a response
+func (m *metrics) fetched(metadataType string, resp *http.Response) {
+	if resp != nil {
+		m.httpResponses.WithLabelValues(metadataType, strconv.Itoa(resp.StatusCode)).Inc()
+	}
+}
+
+func (m *metrics) downloaded(metadataType string, n int) {
+	m.downloadedBytes.WithLabelValues(metadataType).Add(float64(n))
+}
+
+func (m *metrics) finished(metadataType string, duration time.Duration) {
+	m.fetchDuration.WithLabelValues(metadataType).Observe(duration.Seconds())
+}
+
+func (m *metrics) decompressed(metadataType string, n int) {
+	m.decompressedBytes.WithLabelValues(metadataType).Add(float64(n))
+}
+
+func (m *metrics) parsed(metadataType string, count int) {
+	m.parsedItems.WithLabelValues(metadataType).Add(float64(count))
+}
+
+func (m *metrics) cacheRequest(metadataType string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheRequests.WithLabelValues(metadataType, result).Inc()
+}
+
+// observer collects the metrics or statistics of the requests of a repository
+type observer interface {
+	// fetched counts a response, or a request that failed without one if resp is nil
+	fetched(metadataType string, resp *http.Response)
+	// downloaded counts n bytes read from a response body
+	downloaded(metadataType string, n int)
+	// finished records the duration of a fetch, until its response body was closed or its request failed
+	finished(metadataType string, duration time.Duration)
+	// decompressed counts n bytes of metadata read after decompression
+	decompressed(metadataType string, n int)
+	// parsed counts the items parsed from metadata, such as packages or advisories
+	parsed(metadataType string, count int)
+	// cacheRequest counts a request for metadata, served from cache if hit is true
+	cacheRequest(metadataType string, hit bool)
+}
+
+// observers reports the requests of a repository to each of its observers, metering response bodies and
+// decompressed metadata once for all of them
+type observers []observer
+
+// observers returns the observers of the repository: its metrics, if enabled, and its statistics
+func (r *Repository) observers() observers {
+	var o observers
+	if r.metrics != nil {
+		o = append(o, r.metrics)
+	}
+	if r.stats != nil {
+		o = append(o, r.stats)
+	}
+	return o
+}
+
+// observeResponse reports a response, or a failed request, and wraps the response body to report the bytes
+// read and the fetch duration, from start until the body is closed
+func (o observers) observeResponse(metadataType string, start time.Time, resp *http.Response, err error) {
+	if len(o) == 0 {
+		return
+	}
+	if err != nil || resp == nil {
+		for _, observer := range o {
+			observer.fetched(metadataType, nil)
+			observer.finished(metadataType, time.Since(start))
+		}
+		return
+	}
+	for _, observer := range o {
+		observer.fetched(metadataType, resp)
+	}
+	resp.Body = &meteredBody{
+		ReadCloser: resp.Body,
+		count: func(n int) {
+			for _, observer := range o {
+				observer.downloaded(metadataType, n)
+			}
+		},
+		onClose: func() {
+			duration := time.Since(start)
+			for _, observer := range o {
+				observer.finished(metadataType, duration)
+			}
+		},
+	}
+}
+
+// decompressed returns a reader reporting the bytes read from reader, which holds decompressed metadata
+func (o observers) decompressed(metadataType string, reader io.Reader) io.Reader {
+	if len(o) == 0 {
+		return reader
+	}
+	return &meteredBody{ReadCloser: io.NopCloser(reader), count: func(n int) {
+		for _, observer := range o {
+			observer.decompressed(metadataType, n)
+		}
+	}}
+}
+
+func (o observers) parsed(metadataType string, count int) {
+	for _, observer := range o {
+		observer.parsed(metadataType, count)
+	}
+}
+
+func (o observers) cacheRequest(metadataType string, hit bool) {
+	for _, observer := range o {
+		observer.cacheRequest(metadataType, hit)
+	}
+}
+
+// meteredBody calls count with the number of bytes of every read through it, and onClose, if set, once when
+// closed
+type meteredBody struct {
+	io.ReadCloser
+	count   func(int)
+	onClose func()
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.count(n)
+	}
+	return n, err
+}
+
+func (b *meteredBody) Close() error {
+	if b.onClose != nil {
+		b.onClose()
+		b.onClose = nil
+	}
+	return b.ReadCloser.Close()
+}
+
+// decompressedBody returns the body of resp, decompressed if the repository has observers so that the
+// decompressed bytes can be counted. Parsers accept uncompressed as well as compressed metadata, so
+// either can be parsed.
+func (r *Repository) decompressedBody(resp *http.Response, metadataType string) io.ReadCloser {
+	if len(r.observers()) == 0 {
+		return resp.Body
+	}
+	reader, err := ExtractIfCompressed(resp.Body)
+	if err != nil {
+		return io.NopCloser(&errorReader{err: err})
+	}
+	return io.NopCloser(r.decompressed(metadataType, reader))
+}
+
+// errorReader fails every read with err
+type errorReader struct {
+	err error
+}
+
+func (e *errorReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
@@ -0,0 +1,62 @@
+package yum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	registry := prometheus.NewRegistry()
+	settings := YummySettings{
+		Client:            s.Client(),
+		URL:               &s.URL,
+		MetricsRegisterer: registry,
+	}
+	r, err := NewRepository(settings)
+	require.NoError(t, err)
+
+	_, _, err = r.Packages(context.Background())
+	require.NoError(t, err)
+	_, _, err = r.Packages(context.Background())
+	require.NoError(t, err)
+	_, _, err = r.Advisories(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(len(primaryXML)), testutil.ToFloat64(r.metrics.downloadedBytes.WithLabelValues("primary")))
+	assert.Greater(t, testutil.ToFloat64(r.metrics.decompressedBytes.WithLabelValues("primary")), float64(len(primaryXML)))
+	assert.Greater(t, testutil.ToFloat64(r.metrics.decompressedBytes.WithLabelValues("updateinfo")), float64(0))
+	assert.Equal(t, float64(2), testutil.ToFloat64(r.metrics.parsedItems.WithLabelValues("primary")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.metrics.cacheRequests.WithLabelValues("primary", "miss")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.metrics.cacheRequests.WithLabelValues("primary", "hit")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.metrics.httpResponses.WithLabelValues("repomd", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.metrics.httpResponses.WithLabelValues("primary", "200")))
+	assert.Equal(t, 3, testutil.CollectAndCount(r.metrics.fetchDuration))
+
+	// A second repository reports to the collectors already registered
+	other, err := NewRepository(settings)
+	require.NoError(t, err)
+	_, _, err = other.Repomd(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, float64(2), testutil.ToFloat64(r.metrics.httpResponses.WithLabelValues("repomd", "200")))
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	assert.Nil(t, r.metrics)
+
+	packages, code, err := r.Packages(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Len(t, packages, 2)
+}
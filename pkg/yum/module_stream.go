@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
 	"gopkg.in/yaml.v3"
@@ -13,92 +16,486 @@ import (
 
 // Better userfacing struct
 type ModuleStream struct {
-	Name    string
-	Streams []Stream
+	Name    string   `json:"name"`
+	Streams []Stream `json:"streams,omitempty"`
 }
 
 type Stream struct {
-	Name        string                 `mapstructure:"name"`
-	Stream      string                 `mapstructure:"stream"`
-	Version     string                 `mapstructure:"version"`
-	Context     string                 `mapstructure:"context"`
-	Arch        string                 `mapstructure:"arch"`
-	Summary     string                 `mapstructure:"summary"`
-	Description string                 `mapstructure:"description"`
-	Artifacts   Artifacts              `mapstructure:"artifacts"`
-	Profiles    map[string]RpmProfiles `mapstructure:"profiles"`
+	Name          string                 `mapstructure:"name" yaml:"name,omitempty" json:"name"`
+	Stream        string                 `mapstructure:"stream" yaml:"stream,omitempty" json:"stream"`
+	Version       string                 `mapstructure:"version" yaml:"version,omitempty" json:"version"`
+	Context       string                 `mapstructure:"context" yaml:"context,omitempty" json:"context"`
+	Arch          string                 `mapstructure:"arch" yaml:"arch,omitempty" json:"arch"`
+	Summary       string                 `mapstructure:"summary" yaml:"summary,omitempty" json:"summary"`
+	Description   string                 `mapstructure:"description" yaml:"description,omitempty" json:"description"`
+	StaticContext bool                   `mapstructure:"static_context" yaml:"static_context,omitempty" json:"staticContext"`
+	License       License                `mapstructure:"license" yaml:"license,omitempty" json:"license"`
+	Dependencies  []Dependencies         `mapstructure:"dependencies" yaml:"dependencies,omitempty" json:"dependencies,omitempty"`
+	Components    Components             `mapstructure:"components" yaml:"components,omitempty" json:"components"`
+	Artifacts     Artifacts              `mapstructure:"artifacts" yaml:"artifacts,omitempty" json:"artifacts"`
+	Profiles      map[string]RpmProfiles `mapstructure:"profiles" yaml:"profiles,omitempty" json:"profiles,omitempty"`
+}
+
+// Components are the sources a module stream is built from, keyed by component name
+type Components struct {
+	Rpms    map[string]ComponentRpm    `mapstructure:"rpms" yaml:"rpms,omitempty" json:"rpms,omitempty"`
+	Modules map[string]ComponentModule `mapstructure:"modules" yaml:"modules,omitempty" json:"modules,omitempty"`
+}
+
+type ComponentRpm struct {
+	Name          string   `mapstructure:"name" yaml:"name,omitempty" json:"name"`
+	Rationale     string   `mapstructure:"rationale" yaml:"rationale,omitempty" json:"rationale"`
+	Repository    string   `mapstructure:"repository" yaml:"repository,omitempty" json:"repository"`
+	Cache         string   `mapstructure:"cache" yaml:"cache,omitempty" json:"cache"`
+	Ref           string   `mapstructure:"ref" yaml:"ref,omitempty" json:"ref"`
+	Buildroot     bool     `mapstructure:"buildroot" yaml:"buildroot,omitempty" json:"buildroot"`
+	SrpmBuildroot bool     `mapstructure:"srpm-buildroot" yaml:"srpm-buildroot,omitempty" json:"srpmBuildroot"`
+	BuildOrder    int      `mapstructure:"buildorder" yaml:"buildorder,omitempty" json:"buildOrder"`
+	BuildAfter    []string `mapstructure:"buildafter" yaml:"buildafter,omitempty" json:"buildAfter,omitempty"`
+	BuildOnly     bool     `mapstructure:"buildonly" yaml:"buildonly,omitempty" json:"buildOnly"`
+	Arches        []string `mapstructure:"arches" yaml:"arches,omitempty" json:"arches,omitempty"`
+	Multilib      []string `mapstructure:"multilib" yaml:"multilib,omitempty" json:"multilib,omitempty"`
+}
+
+type ComponentModule struct {
+	Rationale  string `mapstructure:"rationale" yaml:"rationale,omitempty" json:"rationale"`
+	Repository string `mapstructure:"repository" yaml:"repository,omitempty" json:"repository"`
+	Ref        string `mapstructure:"ref" yaml:"ref,omitempty" json:"ref"`
+	BuildOrder int    `mapstructure:"buildorder" yaml:"buildorder,omitempty" json:"buildOrder"`
+}
+
+type License struct {
+	Module  []string `mapstructure:"module" yaml:"module,omitempty" json:"module,omitempty"`
+	Content []string `mapstructure:"content" yaml:"content,omitempty" json:"content,omitempty"`
+}
+
+// Dependencies lists the streams of other modules needed to build and run a stream, keyed by module name
+type Dependencies struct {
+	BuildRequires map[string][]string `mapstructure:"buildrequires" yaml:"buildrequires,omitempty" json:"buildRequires,omitempty"`
+	Requires      map[string][]string `mapstructure:"requires" yaml:"requires,omitempty" json:"requires,omitempty"`
 }
 
 type RpmProfiles struct {
-	Rpms []string `mapstructure:"rpms"`
+	Rpms []string `mapstructure:"rpms" yaml:"rpms,omitempty" json:"rpms,omitempty"`
 }
 
 type Artifacts struct {
-	Rpms []string `mapstructure:"rpms"`
+	Rpms   []string                          `mapstructure:"rpms" yaml:"rpms,omitempty" json:"rpms,omitempty"`
+	RpmMap map[string]map[string]RpmMapEntry `mapstructure:"rpm-map" yaml:"rpm-map,omitempty" json:"rpmMap,omitempty"` // Checksum type to checksum to artifact
+}
+
+type RpmMapEntry struct {
+	Name    string `mapstructure:"name" yaml:"name,omitempty" json:"name"`
+	Epoch   int32  `mapstructure:"epoch" yaml:"epoch" json:"epoch"`
+	Version string `mapstructure:"version" yaml:"version,omitempty" json:"version"`
+	Release string `mapstructure:"release" yaml:"release,omitempty" json:"release"`
+	Arch    string `mapstructure:"arch" yaml:"arch,omitempty" json:"arch"`
+	Nevra   string `mapstructure:"nevra" yaml:"nevra,omitempty" json:"nevra"`
+}
+
+// StreamPackages are the packages that belong to a module stream
+type StreamPackages struct {
+	Stream   Stream    `json:"stream"`
+	Packages []Package `json:"packages,omitempty"`
 }
 
 type ModuleMD struct {
-	Document string `mapstructure:"document"`
-	Version  int    `mapstructure:"version"`
-	Data     Stream `yaml:"data"`
+	Document string `mapstructure:"document" yaml:"document" json:"document"`
+	Version  int    `mapstructure:"version" yaml:"version" json:"version"`
+	Data     Stream `yaml:"data" json:"data"`
+}
+
+// Platforms returns the platform streams, such as "el8", the stream can run on
+func (s Stream) Platforms() []string {
+	platforms := []string{}
+	for _, dependencies := range s.Dependencies {
+		for _, platform := range dependencies.Requires["platform"] {
+			if !slices.Contains(platforms, platform) {
+				platforms = append(platforms, platform)
+			}
+		}
+	}
+	return platforms
+}
+
+// RequiresSatisfied reports whether enabled, a map of module name to enabled stream including the
+// "platform" module, satisfies the run time requirements of the stream. Each entry in Dependencies
+// is an alternative, so only one of them needs to be satisfied.
+func (s Stream) RequiresSatisfied(enabled map[string]string) bool {
+	if len(s.Dependencies) == 0 {
+		return true
+	}
+	for _, dependencies := range s.Dependencies {
+		if streamsSatisfied(dependencies.Requires, enabled) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamsSatisfied checks enabled against a map of module name to streams. An empty list of streams
+// matches any enabled stream, and streams prefixed with "-" exclude that stream.
+func streamsSatisfied(required map[string][]string, enabled map[string]string) bool {
+	for module, streams := range required {
+		enabledStream, ok := enabled[module]
+		if !ok {
+			return false
+		}
+		if len(streams) == 0 {
+			continue
+		}
+		matched := false
+		excluding := true
+		for _, stream := range streams {
+			if excluded, found := strings.CutPrefix(stream, "-"); found {
+				if excluded == enabledStream {
+					return false
+				}
+			} else {
+				excluding = false
+				if stream == enabledStream {
+					matched = true
+				}
+			}
+		}
+		if !matched && !excluding {
+			return false
+		}
+	}
+	return true
+}
+
+// ModuleStreamPackages returns which of packages belong to which module stream. Artifacts are matched
+// against packages by checksum when the stream has an rpm-map, and by NEVRA otherwise.
+// Artifacts that cannot be parsed or are not found in packages are skipped.
+func ModuleStreamPackages(moduleMDs []ModuleMD, packages []Package) []StreamPackages {
+	byNEVRA := make(map[NEVRA]int, len(packages))
+	byChecksum := make(map[Checksum]int, len(packages))
+	for i, pkg := range packages {
+		byNEVRA[pkg.NEVRA()] = i
+		byChecksum[pkg.Checksum] = i
+	}
+
+	result := make([]StreamPackages, 0, len(moduleMDs))
+	for _, moduleMD := range moduleMDs {
+		streamPackages := StreamPackages{Stream: moduleMD.Data, Packages: []Package{}}
+		found := map[int]bool{}
+		add := func(index int, ok bool) {
+			if ok && !found[index] {
+				found[index] = true
+				streamPackages.Packages = append(streamPackages.Packages, packages[index])
+			}
+		}
+
+		for checksumType, entries := range moduleMD.Data.Artifacts.RpmMap {
+			for checksum := range entries {
+				index, ok := byChecksum[Checksum{Type: checksumType, Value: checksum}]
+				add(index, ok)
+			}
+		}
+		for _, artifact := range moduleMD.Data.Artifacts.Rpms {
+			nevra, err := ParseNEVRA(artifact)
+			if err != nil {
+				continue
+			}
+			index, ok := byNEVRA[nevra]
+			add(index, ok)
+		}
+		result = append(result, streamPackages)
+	}
+	return result
+}
+
+// FilterModularPackages filters packages the way dnf's modular filtering does. enabled maps module names
+// to their enabled stream. Packages that are artifacts of a module stream are only kept if that stream
+// is enabled, and non-modular packages are hidden if an enabled stream provides a package of the same name.
+func FilterModularPackages(packages []Package, moduleMDs []ModuleMD, enabled map[string]string) []Package {
+	modular := map[NEVRA]bool{}
+	enabledNEVRAs := map[NEVRA]bool{}
+	enabledNames := map[string]bool{}
+	for _, streamPackages := range ModuleStreamPackages(moduleMDs, packages) {
+		stream := streamPackages.Stream
+		streamEnabled := enabled[stream.Name] == stream.Stream
+		for _, pkg := range streamPackages.Packages {
+			modular[pkg.NEVRA()] = true
+			if streamEnabled {
+				enabledNEVRAs[pkg.NEVRA()] = true
+				enabledNames[pkg.Name] = true
+			}
+		}
+	}
+
+	filtered := make([]Package, 0, len(packages))
+	for _, pkg := range packages {
+		nevra := pkg.NEVRA()
+		if modular[nevra] {
+			if enabledNEVRAs[nevra] {
+				filtered = append(filtered, pkg)
+			}
+		} else if !enabledNames[pkg.Name] {
+			filtered = append(filtered, pkg)
+		}
+	}
+	return filtered
+}
+
+// moduleMDv3 is the data of a version 3 modulemd or modulemd-packager document.
+// Instead of one context per document, it lists configurations that each become a stream context,
+// the platform is given separately from the other dependencies, and licenses are a plain list.
+type moduleMDv3 struct {
+	Name           string                 `mapstructure:"name"`
+	Stream         string                 `mapstructure:"stream"`
+	Version        string                 `mapstructure:"version"`
+	Arch           string                 `mapstructure:"arch"`
+	Summary        string                 `mapstructure:"summary"`
+	Description    string                 `mapstructure:"description"`
+	License        []string               `mapstructure:"license"`
+	Configurations []configurationV3      `mapstructure:"configurations"`
+	Components     Components             `mapstructure:"components"`
+	Artifacts      Artifacts              `mapstructure:"artifacts"`
+	Profiles       map[string]RpmProfiles `mapstructure:"profiles"`
+}
+
+type configurationV3 struct {
+	Context       string              `mapstructure:"context"`
+	Platform      string              `mapstructure:"platform"`
+	BuildRequires map[string][]string `mapstructure:"buildrequires"`
+	Requires      map[string][]string `mapstructure:"requires"`
+}
+
+// streams converts the v3 document into one v2 style Stream per configuration. The contexts of the
+// configurations are static, as libmodulemd marks the streams it expands from v3.
+func (v3 moduleMDv3) streams() []Stream {
+	configurations := v3.Configurations
+	if len(configurations) == 0 {
+		configurations = []configurationV3{{}}
+	}
+
+	streams := make([]Stream, 0, len(configurations))
+	for _, configuration := range configurations {
+		dependencies := Dependencies{
+			BuildRequires: map[string][]string{},
+			Requires:      map[string][]string{},
+		}
+		for module, moduleStreams := range configuration.BuildRequires {
+			dependencies.BuildRequires[module] = moduleStreams
+		}
+		for module, moduleStreams := range configuration.Requires {
+			dependencies.Requires[module] = moduleStreams
+		}
+		if configuration.Platform != "" {
+			dependencies.BuildRequires["platform"] = []string{configuration.Platform}
+			dependencies.Requires["platform"] = []string{configuration.Platform}
+		}
+
+		streams = append(streams, Stream{
+			Name:          v3.Name,
+			Stream:        v3.Stream,
+			Version:       v3.Version,
+			Context:       configuration.Context,
+			StaticContext: true,
+			Arch:          v3.Arch,
+			Summary:       v3.Summary,
+			Description:   v3.Description,
+			License:       License{Module: v3.License},
+			Dependencies:  []Dependencies{dependencies},
+			Components:    v3.Components,
+			Artifacts:     v3.Artifacts,
+			Profiles:      v3.Profiles,
+		})
+	}
+	return streams
+}
+
+// ModuleTranslation is the data of a modulemd-translations document for one module stream
+type ModuleTranslation struct {
+	Module       string                            `mapstructure:"module" json:"module"`
+	Stream       string                            `mapstructure:"stream" json:"stream"`
+	Modified     int64                             `mapstructure:"modified" json:"modified"`
+	Translations map[string]ModuleTranslationEntry `mapstructure:"translations" json:"translations,omitempty"`
+}
+
+// ModuleTranslationEntry holds the translated strings of a module stream for a single locale
+type ModuleTranslationEntry struct {
+	Summary     string            `mapstructure:"summary" json:"summary"`
+	Description string            `mapstructure:"description" json:"description"`
+	Profiles    map[string]string `mapstructure:"profiles" json:"profiles,omitempty"` // Profile name to translated profile description
+}
+
+type ModuleTranslations []ModuleTranslation
+
+// ForLocale returns the translation of the given module stream for locale, falling back to the language
+// without its territory (e.g. "pt" for "pt_BR"). If several documents translate the same stream,
+// the most recently modified one is used.
+func (mt ModuleTranslations) ForLocale(module, stream, locale string) (ModuleTranslationEntry, bool) {
+	var latest *ModuleTranslation
+	for i := range mt {
+		if mt[i].Module != module || mt[i].Stream != stream {
+			continue
+		}
+		if _, ok := forLocale(mt[i].Translations, locale); !ok {
+			continue
+		}
+		if latest == nil || mt[i].Modified > latest.Modified {
+			latest = &mt[i]
+		}
+	}
+	if latest == nil {
+		return ModuleTranslationEntry{}, false
+	}
+	return forLocale(latest.Translations, locale)
+}
+
+// Summary returns the summary of stream translated to locale, or the untranslated summary if there is no translation
+func (mt ModuleTranslations) Summary(stream Stream, locale string) string {
+	if entry, ok := mt.ForLocale(stream.Name, stream.Stream, locale); ok && entry.Summary != "" {
+		return entry.Summary
+	}
+	return stream.Summary
+}
+
+// Description returns the description of stream translated to locale, or the untranslated description if there is no translation
+func (mt ModuleTranslations) Description(stream Stream, locale string) string {
+	if entry, ok := mt.ForLocale(stream.Name, stream.Stream, locale); ok && entry.Description != "" {
+		return entry.Description
+	}
+	return stream.Description
 }
 
 // ModuleMDs Returns the modulemd documents from the "modules" metadata in the given yum repository
 func (r *Repository) ModuleMDs(ctx context.Context) ([]ModuleMD, int, error) {
+	return traced(ctx, r, "ModuleMDs", func(ctx context.Context) ([]ModuleMD, int, error) {
+		r.cacheRequest(ctx, "modules", r.moduleMDs != nil)
+		if r.moduleMDs != nil {
+			spanCacheHit(ctx)
+			return r.moduleMDs, 200, nil
+		}
+		code, err := r.fetchModules(ctx)
+		spanItems(ctx, len(r.moduleMDs))
+		return r.moduleMDs, code, err
+	})
+}
+
+// ModuleStreams Returns the module streams of the given yum repository, grouped by module name.
+// Modules are sorted by name, and the streams of each module by stream and then version.
+func (r *Repository) ModuleStreams(ctx context.Context) ([]ModuleStream, int, error) {
+	moduleMDs, code, err := r.ModuleMDs(ctx)
+	if err != nil {
+		return nil, code, err
+	}
+
+	indexByName := map[string]int{}
+	moduleStreams := []ModuleStream{}
+	for _, moduleMD := range moduleMDs {
+		index, ok := indexByName[moduleMD.Data.Name]
+		if !ok {
+			index = len(moduleStreams)
+			indexByName[moduleMD.Data.Name] = index
+			moduleStreams = append(moduleStreams, ModuleStream{Name: moduleMD.Data.Name})
+		}
+		moduleStreams[index].Streams = append(moduleStreams[index].Streams, moduleMD.Data)
+	}
+
+	sort.Slice(moduleStreams, func(i, j int) bool {
+		return moduleStreams[i].Name < moduleStreams[j].Name
+	})
+	for _, moduleStream := range moduleStreams {
+		streams := moduleStream.Streams
+		sort.SliceStable(streams, func(i, j int) bool {
+			if c := compareNumericStrings(streams[i].Stream, streams[j].Stream); c != 0 {
+				return c < 0
+			}
+			return compareNumericStrings(streams[i].Version, streams[j].Version) < 0
+		})
+	}
+	return moduleStreams, code, nil
+}
+
+// ModuleTranslations Returns the modulemd-translations documents from the "modules" metadata in the given yum repository
+func (r *Repository) ModuleTranslations(ctx context.Context) (ModuleTranslations, int, error) {
+	if r.moduleTranslations != nil {
+		return r.moduleTranslations, 200, nil
+	}
+	code, err := r.fetchModules(ctx)
+	return r.moduleTranslations, code, err
+}
+
+// fetchModules fetches and parses the "modules" metadata, populating r.moduleMDs and r.moduleTranslations
+func (r *Repository) fetchModules(ctx context.Context) (int, error) {
 	var modulesURL *string
 	var err error
 	var resp *http.Response
-	var moduleMDs []ModuleMD
-
-	if r.moduleMDs != nil {
-		return r.moduleMDs, 200, nil
-	}
+	var documents moduleDocuments
 
 	if _, _, err := r.Repomd(ctx); err != nil {
-		return nil, 0, fmt.Errorf("error parsing repomd.xml: %w", err)
+		return 0, fmt.Errorf("error parsing repomd.xml: %w", err)
 	}
 
 	if modulesURL, err = r.getModulesURL(); err != nil {
-		return nil, 0, fmt.Errorf("error parsing modules md URL: %w", err)
+		return 0, fmt.Errorf("error parsing modules md URL: %w", err)
 	}
 
-	if modulesURL != nil {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, *modulesURL, nil)
-		if err != nil {
-			return nil, 0, fmt.Errorf("error creating request: %w", err)
-		}
+	if modulesURL == nil {
+		return 0, nil
+	}
 
-		if resp, err = r.settings.Client.Do(req); err != nil {
-			return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", modulesURL, err)
-		}
-		defer resp.Body.Close()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *modulesURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
 
-		if moduleMDs, err = parseModuleMDs(resp.Body); err != nil {
-			return nil, resp.StatusCode, fmt.Errorf("error parsing comps.xml: %w", err)
-		}
+	if resp, err = r.do(req, "modules"); err != nil {
+		return erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", modulesURL, err)
+	}
+	defer resp.Body.Close()
+
+	if documents, err = parseModuleDocuments(r.decompressedBody(resp, "modules")); err != nil {
+		return resp.StatusCode, fmt.Errorf("error parsing modules yaml: %w", err)
+	}
 
-		return moduleMDs, resp.StatusCode, nil
+	for document, count := range documents.skipped {
+		r.logger().WarnContext(ctx, "skipped module documents that are not understood", "url", *modulesURL, "document", document, "count", count)
 	}
-	r.moduleMDs = moduleMDs
-	return moduleMDs, 0, err
+	r.parsed("modules", len(documents.moduleMDs))
+	r.moduleMDs = documents.moduleMDs
+	r.moduleTranslations = documents.translations
+	return resp.StatusCode, nil
+}
+
+// moduleDocuments holds the documents of a modules yaml file that are understood
+type moduleDocuments struct {
+	moduleMDs    []ModuleMD
+	translations ModuleTranslations
+	skipped      map[string]int // Number of documents of each type that are not understood, except defaults and obsoletes
 }
 
 // parses modulemd objects from a given io reader
+func parseModuleMDs(body io.ReadCloser) ([]ModuleMD, error) {
+	documents, err := parseModuleDocuments(body)
+	return documents.moduleMDs, err
+}
+
+// parses module documents from a given io reader
 // modules yaml files include different types of documents which is hard to parse
 // this implements a two step process:
 //
 //	Parse each document into a map, with the value of interface, and then
-//	use mapstructure to parse the interface into a ModuleMD struct
-func parseModuleMDs(body io.ReadCloser) ([]ModuleMD, error) {
-	moduleMDs := make([]ModuleMD, 0)
+//	use mapstructure to parse the interface into a ModuleMD or ModuleTranslation struct
+func parseModuleDocuments(body io.ReadCloser) (moduleDocuments, error) {
+	documents := moduleDocuments{
+		moduleMDs:    make([]ModuleMD, 0),
+		translations: make(ModuleTranslations, 0),
+		skipped:      map[string]int{},
+	}
 
 	reader, err := ExtractIfCompressed(body)
 	if err != nil {
-		return moduleMDs, fmt.Errorf("error extracting compressed streams: %w", err)
+		return documents, fmt.Errorf("error extracting compressed streams: %w", err)
 	}
 
 	decoder := yaml.NewDecoder(reader)
-	for {
+	var item string
+	for index := 1; ; index++ {
 		var doc map[string]interface{}
 
 		// Decode the next document
@@ -107,25 +504,75 @@ func parseModuleMDs(body io.ReadCloser) ([]ModuleMD, error) {
 			if errors.Is(err, io.EOF) {
 				break
 			}
-			return nil, fmt.Errorf("error decoding streams: %w", err)
+			parseErr := &ParseError{Offset: -1, Element: fmt.Sprintf("document %d", index), Item: item, Err: err}
+			return moduleDocuments{}, fmt.Errorf("error decoding streams: %w", parseErr)
 		}
-		// Only care about modulemds right now
-		if doc["document"] == "modulemd" {
-			var module ModuleMD
-			config := &mapstructure.DecoderConfig{
-				WeaklyTypedInput: true,
-				Result:           &module,
+		if name := moduleDocumentName(doc); name != "" {
+			item = name
+		}
+		documentError := func(err error) error {
+			return &ParseError{Offset: -1, Element: fmt.Sprintf("document %d (%v)", index, doc["document"]), Item: item, Err: err}
+		}
+		switch doc["document"] {
+		case "modulemd", "modulemd-packager":
+			if version, _ := doc["version"].(int); version >= 3 {
+				var module moduleMDv3
+				if err = decodeModuleDocument(doc["data"], &module); err != nil {
+					return moduleDocuments{}, documentError(err)
+				}
+				for _, stream := range module.streams() {
+					documents.moduleMDs = append(documents.moduleMDs, ModuleMD{Document: "modulemd", Version: version, Data: stream})
+				}
+				break
 			}
-			mapDecode, err := mapstructure.NewDecoder(config)
-			if err != nil {
-				return moduleMDs, fmt.Errorf("error creating map decoder: %w", err)
+			if doc["document"] != "modulemd" {
+				documents.skipped[fmt.Sprintf("%v version %v", doc["document"], doc["version"])]++
+				break
 			}
-			err = mapDecode.Decode(doc)
-			if err != nil {
-				return nil, fmt.Errorf("error decoding map: %w", err)
+			var module ModuleMD
+			if err = decodeModuleDocument(doc, &module); err != nil {
+				return moduleDocuments{}, documentError(err)
+			}
+			documents.moduleMDs = append(documents.moduleMDs, module)
+		case "modulemd-translations":
+			var translation ModuleTranslation
+			if err = decodeModuleDocument(doc["data"], &translation); err != nil {
+				return moduleDocuments{}, documentError(err)
 			}
-			moduleMDs = append(moduleMDs, module)
+			documents.translations = append(documents.translations, translation)
+		case "modulemd-defaults", "modulemd-obsoletes":
+		default:
+			documents.skipped[fmt.Sprint(doc["document"])]++
 		}
 	}
-	return moduleMDs, nil
+	return documents, nil
+}
+
+// moduleDocumentName returns the module name and stream of a yaml document, empty if it has none
+func moduleDocumentName(doc map[string]interface{}) string {
+	data, _ := doc["data"].(map[string]interface{})
+	name, _ := data["name"].(string)
+	if name == "" {
+		return ""
+	}
+	if stream, ok := data["stream"]; ok {
+		return fmt.Sprintf("%v:%v", name, stream)
+	}
+	return name
+}
+
+// decodeModuleDocument uses mapstructure to decode a generic yaml document into result
+func decodeModuleDocument(doc interface{}, result interface{}) error {
+	config := &mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           result,
+	}
+	mapDecode, err := mapstructure.NewDecoder(config)
+	if err != nil {
+		return fmt.Errorf("error creating map decoder: %w", err)
+	}
+	if err = mapDecode.Decode(doc); err != nil {
+		return fmt.Errorf("error decoding map: %w", err)
+	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package yum
 
 import (
+	"context"
 	_ "embed"
 	"os"
 	"testing"
@@ -41,7 +42,238 @@ func TestParseRhel8Modules(t *testing.T) {
 			value, ok := module.Data.Profiles["common"]
 			assert.True(t, ok)
 			assert.Equal(t, []string{"ruby"}, value.Rpms)
+			assert.Equal(t, []string{"el8"}, module.Data.Platforms())
 		}
 	}
 	assert.True(t, found)
 }
+
+func TestParseModuleTranslations(t *testing.T) {
+	f, err := os.Open("mocks/translations.modules.yaml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	documents, err := parseModuleDocuments(f)
+	require.NoError(t, err)
+	require.Len(t, documents.moduleMDs, 1)
+	require.Len(t, documents.translations, 2)
+
+	stream := documents.moduleMDs[0].Data
+	translations := documents.translations
+
+	// the most recently modified translation wins
+	entry, ok := translations.ForLocale("nodejs", "8", "de")
+	assert.True(t, ok)
+	assert.Equal(t, "Javascript-Laufzeitumgebung", entry.Summary)
+	assert.Equal(t, "Standardprofil", entry.Profiles["default"])
+	assert.Equal(t, "Javascript-Laufzeitumgebung", translations.Summary(stream, "de_AT"))
+	assert.Equal(t, "Node.js ist eine Plattform.", translations.Description(stream, "de"))
+
+	// missing descriptions and locales fall back to the untranslated values
+	assert.Equal(t, "Ambiente de execução Javascript", translations.Summary(stream, "pt_BR"))
+	assert.Equal(t, stream.Description, translations.Description(stream, "pt_BR"))
+	assert.Equal(t, stream.Summary, translations.Summary(stream, "fr"))
+
+	_, ok = translations.ForLocale("nodejs", "10", "de")
+	assert.False(t, ok)
+}
+
+func TestParseModuleMDsV3(t *testing.T) {
+	f, err := os.Open("mocks/v3.modules.yaml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	modules, err := parseModuleMDs(f)
+	require.NoError(t, err)
+	require.Len(t, modules, 3)
+
+	// each v3 configuration becomes its own stream context
+	first, second := modules[0], modules[1]
+	assert.Equal(t, 3, first.Version)
+	assert.Equal(t, "foo", first.Data.Name)
+	assert.Equal(t, "latest", first.Data.Stream)
+	assert.Equal(t, "CTX1", first.Data.Context)
+	assert.Equal(t, "CTX2", second.Data.Context)
+	assert.True(t, first.Data.StaticContext)
+	assert.True(t, second.Data.StaticContext)
+	assert.Equal(t, []string{"MIT"}, first.Data.License.Module)
+	assert.Equal(t, []string{"bar", "bar-devel"}, first.Data.Profiles["container"].Rpms)
+	require.Len(t, first.Data.Dependencies, 1)
+	assert.Equal(t, []string{"f33"}, first.Data.Dependencies[0].Requires["platform"])
+	assert.Equal(t, []string{"v1"}, first.Data.Dependencies[0].Requires["appframework"])
+	assert.Equal(t, []string{"f34"}, second.Data.Dependencies[0].BuildRequires["platform"])
+	assert.Equal(t, []string{"v2"}, second.Data.Dependencies[0].BuildRequires["appframework"])
+
+	// v2 documents keep their own layout
+	v2 := modules[2]
+	assert.Equal(t, 2, v2.Version)
+	assert.True(t, v2.Data.StaticContext)
+	assert.Equal(t, []string{"MIT"}, v2.Data.License.Module)
+	assert.Equal(t, []string{"MIT and ASL 2.0"}, v2.Data.License.Content)
+	require.Len(t, v2.Data.Dependencies, 1)
+	assert.Equal(t, []string{"f29"}, v2.Data.Dependencies[0].Requires["platform"])
+}
+
+func TestFetchModuleStreams(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	moduleStreams, code, err := r.ModuleStreams(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+
+	names := []string{}
+	for _, moduleStream := range moduleStreams {
+		names = append(names, moduleStream.Name)
+	}
+	assert.Equal(t, []string{"dwm", "meson", "ninja", "nodejs", "postgresql"}, names)
+
+	streams := func(moduleStream ModuleStream) []string {
+		result := []string{}
+		for _, stream := range moduleStream.Streams {
+			assert.Equal(t, moduleStream.Name, stream.Name)
+			result = append(result, stream.Stream)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"development", "legacy", "master"}, streams(moduleStreams[2]))
+	assert.Equal(t, []string{"5", "8", "10", "11"}, streams(moduleStreams[3]))
+	assert.Equal(t, []string{"6", "9.6"}, streams(moduleStreams[4]))
+}
+
+func TestStreamDependencies(t *testing.T) {
+	f, err := os.Open("mocks/v3.modules.yaml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	modules, err := parseModuleMDs(f)
+	require.NoError(t, err)
+
+	foo := modules[0].Data
+	assert.Equal(t, []string{"f33"}, foo.Platforms())
+	assert.True(t, foo.RequiresSatisfied(map[string]string{"platform": "f33", "appframework": "v1"}))
+	assert.False(t, foo.RequiresSatisfied(map[string]string{"platform": "f33", "appframework": "v2"}))
+	assert.False(t, foo.RequiresSatisfied(map[string]string{"platform": "f33"}))
+
+	nodejs := modules[2].Data
+	assert.Equal(t, []string{"f29"}, nodejs.Platforms())
+	assert.True(t, nodejs.RequiresSatisfied(map[string]string{"platform": "f29"}))
+	assert.False(t, nodejs.RequiresSatisfied(map[string]string{"platform": "f30"}))
+
+	// alternatives, wildcards and exclusions
+	stream := Stream{Dependencies: []Dependencies{
+		{Requires: map[string][]string{"platform": {"-f28"}, "perl": {}}},
+		{Requires: map[string][]string{"platform": {"f28"}}},
+	}}
+	assert.True(t, stream.RequiresSatisfied(map[string]string{"platform": "f29", "perl": "5.26"}))
+	assert.False(t, stream.RequiresSatisfied(map[string]string{"platform": "f29"}))
+	assert.True(t, stream.RequiresSatisfied(map[string]string{"platform": "f28"}))
+	assert.True(t, Stream{}.RequiresSatisfied(nil))
+}
+
+func TestModuleStreamPackages(t *testing.T) {
+	xmlFile, err := os.Open("mocks/primary.xml.gz")
+	require.NoError(t, err)
+	defer xmlFile.Close()
+	packages, err := ParseCompressedXMLData(xmlFile, DefaultMaxXmlSize)
+	require.NoError(t, err)
+
+	moduleMDs := []ModuleMD{
+		{Data: Stream{Name: "nss", Artifacts: Artifacts{Rpms: []string{
+			"nss-devel-0:3.19.1-18.el7.i686",
+			"nss-devel-0:3.19.1-18.el7.src",
+			"not-a-nevra",
+		}}}},
+		{Data: Stream{Name: "tpm", Artifacts: Artifacts{
+			Rpms: []string{"tpm-quote-tools-0:1.0.2-3.el7.x86_64"},
+			RpmMap: map[string]map[string]RpmMapEntry{
+				"sha1": {"3dd206e9d42ccf37a9e16ba407ac38a738a8b5eb": {Name: "tpm-quote-tools"}},
+			},
+		}}},
+		{Data: Stream{Name: "empty"}},
+	}
+
+	result := ModuleStreamPackages(moduleMDs, packages)
+	require.Len(t, result, 3)
+	assert.Equal(t, "nss", result[0].Stream.Name)
+	require.Len(t, result[0].Packages, 1)
+	assert.Equal(t, "nss-devel", result[0].Packages[0].Name)
+	require.Len(t, result[1].Packages, 1)
+	assert.Equal(t, "tpm-quote-tools", result[1].Packages[0].Name)
+	assert.Empty(t, result[2].Packages)
+}
+
+func TestFilterModularPackages(t *testing.T) {
+	pkg := func(name, version, release string) Package {
+		return Package{Name: name, Arch: "x86_64", Version: Version{Version: version, Release: release}}
+	}
+	packages := []Package{
+		pkg("nodejs", "8.11.4", "1.module+el8"),
+		pkg("nodejs", "10.1.0", "1.module+el8"),
+		pkg("nodejs", "6.0.0", "1.el8"),
+		pkg("npm", "6.0.0", "1.el8"),
+		pkg("bash", "4.4", "1.el8"),
+	}
+	moduleMDs := []ModuleMD{
+		{Data: Stream{Name: "nodejs", Stream: "8", Artifacts: Artifacts{Rpms: []string{"nodejs-0:8.11.4-1.module+el8.x86_64"}}}},
+		{Data: Stream{Name: "nodejs", Stream: "10", Artifacts: Artifacts{Rpms: []string{"nodejs-0:10.1.0-1.module+el8.x86_64"}}}},
+	}
+
+	names := func(packages []Package) []string {
+		result := []string{}
+		for _, p := range packages {
+			result = append(result, p.NEVRA().String())
+		}
+		return result
+	}
+
+	// with no streams enabled, all modular packages are hidden
+	assert.Equal(t, []string{
+		"nodejs-0:6.0.0-1.el8.x86_64",
+		"npm-0:6.0.0-1.el8.x86_64",
+		"bash-0:4.4-1.el8.x86_64",
+	}, names(FilterModularPackages(packages, moduleMDs, map[string]string{})))
+
+	// an enabled stream hides the other stream and non-modular packages of the same name
+	assert.Equal(t, []string{
+		"nodejs-0:10.1.0-1.module+el8.x86_64",
+		"npm-0:6.0.0-1.el8.x86_64",
+		"bash-0:4.4-1.el8.x86_64",
+	}, names(FilterModularPackages(packages, moduleMDs, map[string]string{"nodejs": "10"})))
+}
+
+func TestFetchPackagesModularFiltering(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, EnabledModuleStreams: map[string]string{"nodejs": "8"}})
+	packages, code, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	// none of the mock packages are modular
+	assert.Len(t, packages, 2)
+	assert.NotNil(t, r.moduleMDs)
+}
+
+func TestParseModuleComponents(t *testing.T) {
+	f, err := os.Open("mocks/module.yaml.zst")
+	require.NoError(t, err)
+	defer f.Close()
+
+	modules, err := parseModuleMDs(f)
+	require.NoError(t, err)
+
+	nodejs := modules[2].Data
+	assert.Equal(t, "10", nodejs.Stream)
+	assert.False(t, nodejs.StaticContext)
+	assert.Equal(t, []string{"MIT"}, nodejs.License.Module)
+	assert.NotEmpty(t, nodejs.License.Content)
+	require.Len(t, nodejs.Components.Rpms, 3)
+	component := nodejs.Components.Rpms["nodejs"]
+	assert.Equal(t, "Javascript runtime and npm package manager.", component.Rationale)
+	assert.Equal(t, "git+https://src.fedoraproject.org/rpms/nodejs", component.Repository)
+	assert.Equal(t, "10", component.Ref)
+	assert.Equal(t, 10, component.BuildOrder)
+	assert.Equal(t, "master", nodejs.Components.Rpms["nghttp2"].Ref)
+}
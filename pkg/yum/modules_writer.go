@@ -0,0 +1,59 @@
+package yum
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModuleDefaults is a modulemd-defaults document, setting the default stream of a module and the
+// default profiles of its streams
+type ModuleDefaults struct {
+	Module   string              `mapstructure:"module" yaml:"module" json:"module"`
+	Stream   string              `mapstructure:"stream" yaml:"stream,omitempty" json:"stream"`
+	Profiles map[string][]string `mapstructure:"profiles" yaml:"profiles,omitempty" json:"profiles,omitempty"` // Stream to default profiles
+}
+
+// moduleDefaultsDocument wraps ModuleDefaults in its document header
+type moduleDefaultsDocument struct {
+	Document string         `yaml:"document"`
+	Version  int            `yaml:"version"`
+	Data     ModuleDefaults `yaml:"data"`
+}
+
+// MarshalYAML writes the stream version as an integer, as modulemd requires, although it is parsed as a string
+func (s Stream) MarshalYAML() (interface{}, error) {
+	type plainStream Stream
+	var node yaml.Node
+	if err := node.Encode(plainStream(s)); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "version" {
+			node.Content[i+1].Tag = "!!int"
+			node.Content[i+1].Style = 0
+		}
+	}
+	return &node, nil
+}
+
+// WriteModulesYAML writes module streams and defaults as a modules.yaml document, as published in the "modules"
+// metadata of a repository, for instance through RepoWriter.Modules. Streams are written as modulemd version 2
+// documents, whatever version they were parsed from.
+func WriteModulesYAML(w io.Writer, moduleMDs []ModuleMD, defaults []ModuleDefaults) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	for _, moduleMD := range moduleMDs {
+		if err := encoder.Encode(ModuleMD{Document: "modulemd", Version: 2, Data: moduleMD.Data}); err != nil {
+			return fmt.Errorf("error encoding module %v:%v: %w", moduleMD.Data.Name, moduleMD.Data.Stream, err)
+		}
+	}
+	for _, moduleDefaults := range defaults {
+		if err := encoder.Encode(moduleDefaultsDocument{Document: "modulemd-defaults", Version: 1, Data: moduleDefaults}); err != nil {
+			return fmt.Errorf("error encoding defaults of module %v: %w", moduleDefaults.Module, err)
+		}
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,42 @@
+package yum
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteModulesYAML(t *testing.T) {
+	f, err := os.Open("mocks/translations.modules.yaml")
+	require.NoError(t, err)
+	defer f.Close()
+	documents, err := parseModuleDocuments(f)
+	require.NoError(t, err)
+	defaults := []ModuleDefaults{{Module: "nodejs", Stream: "8", Profiles: map[string][]string{"8": {"default"}}}}
+
+	var written bytes.Buffer
+	require.NoError(t, WriteModulesYAML(&written, documents.moduleMDs, defaults))
+	assert.Contains(t, written.String(), "\n  version: 20180801080000\n")
+
+	// streams round trip
+	reparsed, err := parseModuleDocuments(io.NopCloser(bytes.NewReader(written.Bytes())))
+	require.NoError(t, err)
+	assert.Equal(t, documents.moduleMDs, reparsed.moduleMDs)
+
+	decoder := yaml.NewDecoder(strings.NewReader(written.String()))
+	var last map[string]interface{}
+	for decoder.Decode(&last) == nil {
+	}
+	assert.Equal(t, "modulemd-defaults", last["document"])
+	assert.Equal(t, map[string]interface{}{
+		"module":   "nodejs",
+		"stream":   "8",
+		"profiles": map[string]interface{}{"8": []interface{}{"default"}},
+	}, last["data"])
+}
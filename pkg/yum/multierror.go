@@ -0,0 +1,66 @@
+package yum
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetadataError is the failure of an operation on one metadata file of a repository
+type MetadataError struct {
+	Type       string // Metadata type, such as "primary", or "repomd" / "signature"
+	URL        string // URL of the file, empty if unknown
+	StatusCode int    // Status code of the response, 0 if none was received
+	Err        error
+}
+
+func (e *MetadataError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Err)
+}
+
+func (e *MetadataError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError is the failure of an operation on several metadata files, such as FetchAll or Verify, holding
+// the error of every file that failed rather than only the first. errors.Is and errors.As match any of them.
+type MultiError struct {
+	Errors []MetadataError
+}
+
+func (e *MultiError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for i := range e.Errors {
+		messages = append(messages, e.Errors[i].Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for i := range e.Errors {
+		errs = append(errs, &e.Errors[i])
+	}
+	return errs
+}
+
+// metadataURL returns the URL of a type of metadata, empty if repomd.xml is not fetched or does not list it
+func (r *Repository) metadataURL(metadataType string) string {
+	var metadataURL string
+	switch metadataType {
+	case "repomd":
+		metadataURL, _ = r.getRepomdURL()
+	case "signature":
+		metadataURL, _ = r.getSignatureURL()
+	default:
+		if r.repomd == nil {
+			return ""
+		}
+		for _, data := range r.repomd.Data {
+			if data.Type == metadataType {
+				metadataURL, _ = r.getLocationURL(data.Location)
+				break
+			}
+		}
+	}
+	return metadataURL
+}
@@ -0,0 +1,139 @@
+package yum
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// netrcEntry holds the credentials of a machine in a netrc file. The default entry has no machine.
+type netrcEntry struct {
+	machine  string
+	login    string
+	password string
+}
+
+// DefaultNetrcPath returns the path of the netrc file read when YummySettings.NetrcPath is empty: $NETRC if
+// set, as curl does, otherwise .netrc in the home directory
+func DefaultNetrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// parseNetrc parses the machine and default entries of a netrc file. Macro definitions are skipped.
+func parseNetrc(reader io.Reader) ([]netrcEntry, error) {
+	entries := []netrcEntry{}
+	var current *netrcEntry
+	inMacro := false
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			// a macro definition ends at an empty line
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			token := fields[i]
+			switch token {
+			case "default":
+				entries = append(entries, netrcEntry{})
+				current = &entries[len(entries)-1]
+				continue
+			case "macdef":
+				inMacro = true
+				i = len(fields)
+				continue
+			}
+
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("netrc: missing value of %v", token)
+			}
+			value := fields[i+1]
+			i++
+			if token == "machine" {
+				entries = append(entries, netrcEntry{machine: value})
+				current = &entries[len(entries)-1]
+				continue
+			}
+			if current == nil {
+				return nil, fmt.Errorf("netrc: %v outside of a machine entry", token)
+			}
+			switch token {
+			case "login":
+				current.login = value
+			case "password":
+				current.password = value
+			case "account":
+			default:
+				return nil, fmt.Errorf("netrc: unknown token %v", token)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// lookupNetrc returns the entry of the machine host, or the default entry if there is none
+func lookupNetrc(entries []netrcEntry, host string) (netrcEntry, bool) {
+	for _, entry := range entries {
+		if entry.machine != "" && strings.EqualFold(entry.machine, host) {
+			return entry, true
+		}
+	}
+	for _, entry := range entries {
+		if entry.machine == "" {
+			return entry, true
+		}
+	}
+	return netrcEntry{}, false
+}
+
+// setNetrcAuth sets basic auth credentials from the netrc file on a request that has none, if Netrc is
+// enabled. The file is read once; a missing file provides no credentials.
+func (r *Repository) setNetrcAuth(req *http.Request) error {
+	if !r.settings.Netrc || req.URL.User != nil || req.Header.Get("Authorization") != "" {
+		return nil
+	}
+	if r.netrcEntries == nil {
+		path := r.settings.NetrcPath
+		if path == "" {
+			path = DefaultNetrcPath()
+		}
+		entries := []netrcEntry{}
+		if f, err := os.Open(path); err == nil {
+			entries, err = parseNetrc(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("error reading %v: %w", path, err)
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		r.netrcEntries = entries
+	}
+
+	if entry, ok := lookupNetrc(r.netrcEntries, req.URL.Hostname()); ok && entry.login != "" {
+		req.SetBasicAuth(entry.login, entry.password)
+	}
+	return nil
+}
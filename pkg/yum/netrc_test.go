@@ -0,0 +1,84 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetrc(t *testing.T) {
+	netrc := `# private mirrors
+machine mirror.example.com login alice password s3cret
+machine other.example.com
+  login bob
+  password hunter2
+  account ignored
+macdef init
+cd /pub
+machine macro.example.com login nobody
+
+default login anonymous password guest
+`
+	entries, err := parseNetrc(strings.NewReader(netrc))
+	require.NoError(t, err)
+	assert.Equal(t, []netrcEntry{
+		{machine: "mirror.example.com", login: "alice", password: "s3cret"},
+		{machine: "other.example.com", login: "bob", password: "hunter2"},
+		{login: "anonymous", password: "guest"},
+	}, entries)
+
+	entry, ok := lookupNetrc(entries, "MIRROR.example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "alice", entry.login)
+	entry, ok = lookupNetrc(entries, "unknown.example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "anonymous", entry.login)
+	_, ok = lookupNetrc(entries[:2], "unknown.example.com")
+	assert.False(t, ok)
+
+	_, err = parseNetrc(strings.NewReader("login alice"))
+	assert.Error(t, err)
+	_, err = parseNetrc(strings.NewReader("machine example.com login"))
+	assert.Error(t, err)
+}
+
+func TestNetrcAuth(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "alice" || password != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		serveRepomdXML(w, r)
+	}))
+	defer s.Close()
+
+	netrcPath := filepath.Join(t.TempDir(), "netrc")
+	require.NoError(t, os.WriteFile(netrcPath, []byte("machine 127.0.0.1 login alice password s3cret\n"), 0600))
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	_, code, _ := r.Repomd(context.Background())
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, Netrc: true, NetrcPath: netrcPath})
+	_, code, err := r.Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	// explicit credentials in the URL take precedence
+	explicitURL := strings.Replace(s.URL, "http://", "http://mallory:wrong@", 1)
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &explicitURL, Netrc: true, NetrcPath: netrcPath})
+	_, code, _ = r.Repomd(context.Background())
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	missingPath := filepath.Join(t.TempDir(), "missing")
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, Netrc: true, NetrcPath: missingPath})
+	_, code, _ = r.Repomd(context.Background())
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
@@ -0,0 +1,191 @@
+package yum
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NEVRA identifies a package by its name, epoch, version, release and architecture
+type NEVRA struct {
+	Name    string `json:"name"`
+	Epoch   int32  `json:"epoch"`
+	Version string `json:"version"`
+	Release string `json:"release"`
+	Arch    string `json:"arch"`
+}
+
+// ParseNEVRA parses strings such as "nodejs-1:5.3.1-1.module_2011+41787af0.x86_64", as used in module artifacts.
+// The epoch is optional and a trailing ".rpm" is ignored.
+func ParseNEVRA(nevra string) (NEVRA, error) {
+	var result NEVRA
+	rest := strings.TrimSuffix(nevra, ".rpm")
+
+	archIndex := strings.LastIndex(rest, ".")
+	if archIndex == -1 {
+		return NEVRA{}, fmt.Errorf("invalid NEVRA %v: missing architecture", nevra)
+	}
+	result.Arch = rest[archIndex+1:]
+	rest = rest[:archIndex]
+
+	releaseIndex := strings.LastIndex(rest, "-")
+	if releaseIndex == -1 {
+		return NEVRA{}, fmt.Errorf("invalid NEVRA %v: missing release", nevra)
+	}
+	result.Release = rest[releaseIndex+1:]
+	rest = rest[:releaseIndex]
+
+	versionIndex := strings.LastIndex(rest, "-")
+	if versionIndex == -1 {
+		return NEVRA{}, fmt.Errorf("invalid NEVRA %v: missing version", nevra)
+	}
+	result.Name = rest[:versionIndex]
+	result.Version = rest[versionIndex+1:]
+
+	if epoch, version, found := strings.Cut(result.Version, ":"); found {
+		parsed, err := strconv.ParseInt(epoch, 10, 32)
+		if err != nil {
+			return NEVRA{}, fmt.Errorf("invalid NEVRA %v: bad epoch: %w", nevra, err)
+		}
+		result.Epoch = int32(parsed)
+		result.Version = version
+	}
+
+	if result.Name == "" || result.Version == "" || result.Release == "" || result.Arch == "" {
+		return NEVRA{}, fmt.Errorf("invalid NEVRA %v", nevra)
+	}
+	return result, nil
+}
+
+// String formats the NEVRA as name-epoch:version-release.arch
+func (n NEVRA) String() string {
+	return fmt.Sprintf("%v-%d:%v-%v.%v", n.Name, n.Epoch, n.Version, n.Release, n.Arch)
+}
+
+// NEVRA returns the NEVRA of the package
+func (p Package) NEVRA() NEVRA {
+	return NEVRA{
+		Name:    p.Name,
+		Epoch:   p.Version.Epoch,
+		Version: p.Version.Version,
+		Release: p.Version.Release,
+		Arch:    p.Arch,
+	}
+}
+
+// CompareEVR compares the epoch, version and release of a and b the way rpm does, returning -1 if a is
+// older than b, 1 if it is newer and 0 if they are the same. Names and architectures are not compared.
+func CompareEVR(a, b NEVRA) int {
+	if a.Epoch != b.Epoch {
+		if a.Epoch < b.Epoch {
+			return -1
+		}
+		return 1
+	}
+	if c := CompareVersions(a.Version, b.Version); c != 0 {
+		return c
+	}
+	return CompareVersions(a.Release, b.Release)
+}
+
+// CompareVersions compares two versions or releases with rpm's rpmvercmp algorithm, returning -1 if a is
+// older than b, 1 if it is newer and 0 if they are the same. Versions are split into numeric and alphabetic
+// segments, numeric segments are newer than alphabetic ones, "~" sorts before anything, even the end of
+// the version, and "^" sorts after the end of the version but before anything else.
+func CompareVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+	for {
+		a = strings.TrimLeftFunc(a, isVersionSeparator)
+		b = strings.TrimLeftFunc(b, isVersionSeparator)
+
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		if a == "" || b == "" {
+			break
+		}
+
+		numeric := isDigit(rune(a[0]))
+		segmentA, segmentB := versionSegment(a, numeric), versionSegment(b, numeric)
+		a, b = a[len(segmentA):], b[len(segmentB):]
+		if segmentB == "" {
+			// segments of different types, numeric ones are newer
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+		if numeric {
+			segmentA = strings.TrimLeft(segmentA, "0")
+			segmentB = strings.TrimLeft(segmentB, "0")
+			if len(segmentA) != len(segmentB) {
+				if len(segmentA) < len(segmentB) {
+					return -1
+				}
+				return 1
+			}
+		}
+		if c := strings.Compare(segmentA, segmentB); c != 0 {
+			return c
+		}
+	}
+
+	if a == "" && b == "" {
+		return 0
+	}
+	if a == "" {
+		return -1
+	}
+	return 1
+}
+
+// versionSegment returns the leading run of digits, or of letters, of a version
+func versionSegment(version string, numeric bool) string {
+	end := strings.IndexFunc(version, func(r rune) bool {
+		if numeric {
+			return !isDigit(r)
+		}
+		return !isLetter(r)
+	})
+	if end == -1 {
+		return version
+	}
+	return version[:end]
+}
+
+func isVersionSeparator(r rune) bool {
+	return !isDigit(r) && !isLetter(r) && r != '~' && r != '^'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,74 @@
+package yum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNEVRA(t *testing.T) {
+	nevra, err := ParseNEVRA("nodejs-devel-1:5.3.1-1.module_2011+41787af0.x86_64")
+	assert.NoError(t, err)
+	assert.Equal(t, NEVRA{Name: "nodejs-devel", Epoch: 1, Version: "5.3.1", Release: "1.module_2011+41787af0", Arch: "x86_64"}, nevra)
+	assert.Equal(t, "nodejs-devel-1:5.3.1-1.module_2011+41787af0.x86_64", nevra.String())
+
+	nevra, err = ParseNEVRA("tpm-quote-tools-1.0.2-3.el7.x86_64.rpm")
+	assert.NoError(t, err)
+	assert.Equal(t, NEVRA{Name: "tpm-quote-tools", Version: "1.0.2", Release: "3.el7", Arch: "x86_64"}, nevra)
+	assert.Equal(t, "tpm-quote-tools-0:1.0.2-3.el7.x86_64", nevra.String())
+
+	for _, invalid := range []string{"", "noarch", "foo.x86_64", "foo-1.x86_64", "foo-x:1-1.x86_64", "-1-1.x86_64"} {
+		_, err = ParseNEVRA(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	// from the rpmvercmp test suite of rpm
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0", "1.0", 1},
+		{"2.0.1", "2.0.1", 0},
+		{"2.0", "2.0.1", -1},
+		{"2.0.1a", "2.0.1", 1},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"10xyz", "10.1xyz", -1},
+		{"xyz10", "xyz10.1", -1},
+		{"xyz.4", "8", -1},
+		{"8", "xyz.4", 1},
+		{"1.0010", "1.9", 1},
+		{"1.05", "1.5", 0},
+		{"1.0", "1", 1},
+		{"2.50", "2.5", 1},
+		{"fc4", "fc.4", 0},
+		{"FC5", "fc4", -1},
+		{"2a", "2.0", -1},
+		{"1.0a", "1.0", 1},
+		{"1b.fc17", "1.fc17", -1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~rc1~git123", "1.0~rc1", -1},
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.0", 1},
+		{"1.0^git1", "1.01", -1},
+		{"1.0^git1", "1.0^git2", -1},
+		{"1.0^git1~pre", "1.0^git1", -1},
+		{"1.0~rc1^git1", "1.0~rc1", 1},
+		{"1.0^git1", "1.0~rc1", 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, CompareVersions(tt.a, tt.b), "%v %v", tt.a, tt.b)
+		assert.Equal(t, -tt.expected, CompareVersions(tt.b, tt.a), "%v %v", tt.b, tt.a)
+	}
+}
+
+func TestCompareEVR(t *testing.T) {
+	assert.Equal(t, 1, CompareEVR(NEVRA{Epoch: 1, Version: "1.0", Release: "1"}, NEVRA{Version: "2.0", Release: "1"}))
+	assert.Equal(t, -1, CompareEVR(NEVRA{Version: "1.0", Release: "1.el9"}, NEVRA{Version: "1.0", Release: "2.el9"}))
+	assert.Equal(t, 0, CompareEVR(NEVRA{Name: "a", Version: "1.0", Release: "1", Arch: "x86_64"}, NEVRA{Name: "b", Version: "1.0", Release: "1"}))
+}
@@ -0,0 +1,313 @@
+package yum
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+// ociManifestMediaTypes are the media types of the manifests and indexes OCIClient accepts
+var ociManifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+const (
+	// ociTitleAnnotation holds the path of the file of a layer
+	ociTitleAnnotation = "org.opencontainers.image.title"
+	ociRepomdPath      = "repodata/repomd.xml"
+	maxOCIManifestSize = 4 << 20
+)
+
+// OCIClient is an HTTPDoer that serves repositories published to container registries as OCI artifacts, with
+// one layer per file titled with its path in the repository, such as "repodata/repomd.xml", by the
+// org.opencontainers.image.title annotation. Set it as YummySettings.Client with a URL of the form
+// oci://registry/name:tag or oci://registry/name@digest. The reference may be an image manifest or an index,
+// whose manifests' layers are all used. Requests for oci URLs are served from the blobs of the layers, and other
+// requests are sent as they are. The manifest is resolved again whenever repomd.xml is requested, so that
+// refreshing the repository picks up a moved tag. Registries are authenticated with the anonymous or, if
+// Username is set, the credentialed bearer token flow of the distribution spec, or with basic authentication.
+type OCIClient struct {
+	Client    HTTPDoer // Sends the requests, http.DefaultClient if nil
+	Username  string
+	Password  string
+	PlainHTTP bool // Connects to the registry over http rather than https, for local registries
+
+	mu     sync.Mutex
+	layers map[string]map[string]string // Blob digests by file path, by reference
+	tokens map[string]string            // Bearer tokens by registry and repository name
+}
+
+// ociReference is a manifest in a registry, and the path of a file in the repository it holds
+type ociReference struct {
+	registry  string
+	name      string
+	reference string // tag or digest
+	file      string
+}
+
+// parseOCIURL splits an oci://registry/name:tag/file or oci://registry/name@digest/file URL
+func parseOCIURL(u *url.URL) (ociReference, error) {
+	segments := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	for i, segment := range segments {
+		var name, reference string
+		if before, digest, found := strings.Cut(segment, "@"); found {
+			name, reference = before, digest
+		} else if before, tag, found := strings.Cut(segment, ":"); found {
+			name, reference = before, tag
+		} else {
+			continue
+		}
+		if name == "" || reference == "" {
+			break
+		}
+		return ociReference{
+			registry:  u.Host,
+			name:      strings.Join(append(segments[:i:i], name), "/"),
+			reference: reference,
+			file:      strings.Join(segments[i+1:], "/"),
+		}, nil
+	}
+	return ociReference{}, fmt.Errorf("missing tag or digest in OCI URL %v", u.Redacted())
+}
+
+// Do sends req, serving oci:// URLs from the blobs of the manifest they reference
+func (c *OCIClient) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "oci" {
+		return c.client().Do(req)
+	}
+
+	ref, err := parseOCIURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, resp, err := c.resolveLayers(req.Context(), ref, ref.file == ociRepomdPath)
+	if err != nil || resp != nil {
+		return resp, err
+	}
+	digest, ok := layers[ref.file]
+	if !ok {
+		return &http.Response{
+			Status:     "404 Not Found",
+			StatusCode: http.StatusNotFound,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	}
+
+	header := http.Header{}
+	if rangeHeader := req.Header.Get("Range"); rangeHeader != "" {
+		header.Set("Range", rangeHeader)
+	}
+	return c.registryGet(req.Context(), ref, "/blobs/"+digest, header)
+}
+
+func (c *OCIClient) client() HTTPDoer {
+	if isNilClient(c.Client) {
+		return http.DefaultClient
+	}
+	return c.Client
+}
+
+// resolveLayers returns the blob digests of the files of the manifest of ref, fetching the manifest if it was
+// not yet or if refresh is set. A response is returned instead if the registry did not return a manifest.
+func (c *OCIClient) resolveLayers(ctx context.Context, ref ociReference, refresh bool) (map[string]string, *http.Response, error) {
+	key := ref.registry + "/" + ref.name + ":" + ref.reference
+	c.mu.Lock()
+	layers, ok := c.layers[key]
+	c.mu.Unlock()
+	if ok && !refresh {
+		return layers, nil, nil
+	}
+
+	manifest, resp, err := c.fetchManifest(ctx, ref, ref.reference)
+	if err != nil || resp != nil {
+		return nil, resp, err
+	}
+	manifests := []ociManifest{manifest}
+	for _, descriptor := range manifest.Manifests {
+		child, resp, err := c.fetchManifest(ctx, ref, descriptor.Digest)
+		if err != nil || resp != nil {
+			return nil, resp, err
+		}
+		manifests = append(manifests, child)
+	}
+
+	layers = map[string]string{}
+	for _, m := range manifests {
+		for _, layer := range m.Layers {
+			if title := layer.Annotations[ociTitleAnnotation]; title != "" {
+				layers[strings.TrimPrefix(path.Clean("/"+title), "/")] = layer.Digest
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.layers == nil {
+		c.layers = map[string]map[string]string{}
+	}
+	c.layers[key] = layers
+	return layers, nil, nil
+}
+
+// ociManifest is an OCI image manifest or index, or the Docker equivalent
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Manifests []ociDescriptor `json:"manifests"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// fetchManifest fetches and parses a manifest. Responses other than 200 OK are returned for the caller to
+// return as they are.
+func (c *OCIClient) fetchManifest(ctx context.Context, ref ociReference, reference string) (ociManifest, *http.Response, error) {
+	resp, err := c.registryGet(ctx, ref, "/manifests/"+reference, http.Header{"Accept": {strings.Join(ociManifestMediaTypes, ", ")}})
+	if err != nil {
+		return ociManifest{}, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ociManifest{}, resp, nil
+	}
+	defer resp.Body.Close()
+
+	var manifest ociManifest
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxOCIManifestSize)).Decode(&manifest); err != nil {
+		return ociManifest{}, nil, fmt.Errorf("error parsing manifest %v of %v: %w", reference, ref.name, err)
+	}
+	return manifest, nil, nil
+}
+
+// registryGet sends a GET request for a path of the repository of ref, authenticating as the registry asks to
+func (c *OCIClient) registryGet(ctx context.Context, ref ociReference, apiPath string, header http.Header) (*http.Response, error) {
+	scheme := "https"
+	if c.PlainHTTP {
+		scheme = "http"
+	}
+	apiURL := url.URL{Scheme: scheme, Host: ref.registry, Path: "/v2/" + ref.name + apiPath}
+	tokenKey := ref.registry + "/" + ref.name
+
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		c.mu.Lock()
+		token := c.tokens[tokenKey]
+		c.mu.Unlock()
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		} else if c.Username != "" {
+			req.SetBasicAuth(c.Username, c.Password)
+		}
+		return c.client().Do(req)
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := parseBearerChallenge(resp.Header.Get("WWW-Authenticate"))
+	if challenge == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	token, err := c.fetchRegistryToken(ctx, challenge, ref.name)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.tokens == nil {
+		c.tokens = map[string]string{}
+	}
+	c.tokens[tokenKey] = token
+	c.mu.Unlock()
+	return send()
+}
+
+// parseBearerChallenge returns the parameters of a Bearer WWW-Authenticate challenge, or nil for other schemes
+func parseBearerChallenge(header string) map[string]string {
+	scheme, params, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return nil
+	}
+	challenge := map[string]string{}
+	for params != "" {
+		var key, value string
+		key, params, _ = strings.Cut(strings.TrimLeft(params, ", "), "=")
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+		challenge[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return challenge
+}
+
+// fetchRegistryToken requests a pull token for the repository from the token service of a Bearer challenge
+func (c *OCIClient) fetchRegistryToken(ctx context.Context, challenge map[string]string, name string) (string, error) {
+	realm, err := url.Parse(challenge["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm %q", challenge["realm"])
+	}
+	query := realm.Query()
+	if service := challenge["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", cmp.Or(challenge["scope"], "repository:"+name+":pull"))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error fetching registry token from %v: %w", realm.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Cannot fetch registry token from %v: %d", realm.Redacted(), resp.StatusCode)
+	}
+
+	var response struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&response); err != nil {
+		return "", fmt.Errorf("error parsing registry token: %w", err)
+	}
+	token := cmp.Or(response.Token, response.AccessToken)
+	if token == "" {
+		return "", fmt.Errorf("no token in response from %v", realm.Redacted())
+	}
+	return token, nil
+}
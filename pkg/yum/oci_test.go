@@ -0,0 +1,124 @@
+package yum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registryServer serves the mock repository as an OCI artifact tagged mirrors/epel:9, behind an index, to
+// clients with a token from its anonymous token service
+func registryServer(t *testing.T) *httptest.Server {
+	blobs := map[string][]byte{}
+	var layers []ociDescriptor
+	for title, content := range map[string][]byte{
+		"repodata/repomd.xml":      repomdXML,
+		"repodata/primary.xml.gz":  primaryXML,
+		"/repodata/comps.xml":      compsXML,
+		"repodata/module.yaml.zst": moduleYamlZst,
+	} {
+		digest := "sha256:" + sha256Hex(content)
+		blobs[digest] = content
+		layers = append(layers, ociDescriptor{Digest: digest, Size: int64(len(content)), Annotations: map[string]string{ociTitleAnnotation: title}})
+	}
+	manifest, _ := json.Marshal(ociManifest{MediaType: "application/vnd.oci.image.manifest.v1+json", Layers: layers})
+	manifestDigest := "sha256:" + sha256Hex(manifest)
+	index, _ := json.Marshal(ociManifest{MediaType: "application/vnd.oci.image.index.v1+json", Manifests: []ociDescriptor{{Digest: manifestDigest}}})
+
+	var s *httptest.Server
+	s = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "repository:mirrors/epel:pull", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token": "pull-token"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer pull-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%v/token",service="registry",scope="repository:mirrors/epel:pull"`, s.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v2/mirrors/epel/manifests/9":
+			assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json")
+			_, _ = w.Write(index)
+		case r.URL.Path == "/v2/mirrors/epel/manifests/"+manifestDigest:
+			_, _ = w.Write(manifest)
+		case strings.HasPrefix(r.URL.Path, "/v2/mirrors/epel/blobs/"):
+			blob, ok := blobs[strings.TrimPrefix(r.URL.Path, "/v2/mirrors/epel/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestOCIClient(t *testing.T) {
+	registry := registryServer(t)
+	host := strings.TrimPrefix(registry.URL, "http://")
+
+	r, _ := NewRepository(YummySettings{Client: &OCIClient{PlainHTTP: true}, URL: Ptr("oci://" + host + "/mirrors/epel:9")})
+
+	packages, code, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Len(t, packages, 2)
+
+	groups, _, err := r.PackageGroups(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, groups)
+
+	// files that are not layers of the artifact are not found
+	_, code, err = r.Signature(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	r, _ = NewRepository(YummySettings{Client: &OCIClient{PlainHTTP: true}, URL: Ptr("oci://" + host + "/mirrors/epel:10")})
+	_, code, err = r.Repomd(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestParseOCIURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected ociReference
+	}{
+		{"oci://quay.io/mirrors/epel:9/repodata/repomd.xml", ociReference{"quay.io", "mirrors/epel", "9", "repodata/repomd.xml"}},
+		{"oci://localhost:5000/epel:latest", ociReference{"localhost:5000", "epel", "latest", ""}},
+		{"oci://ghcr.io/org/team/epel@sha256:abc/Packages/f/foo.rpm", ociReference{"ghcr.io", "org/team/epel", "sha256:abc", "Packages/f/foo.rpm"}},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		ref, err := parseOCIURL(u)
+		require.NoError(t, err, tt.url)
+		assert.Equal(t, tt.expected, ref)
+	}
+
+	for _, invalid := range []string{"oci://quay.io/mirrors/epel/repodata/repomd.xml", "oci://quay.io/:9/repomd.xml"} {
+		u, _ := url.Parse(invalid)
+		_, err := parseOCIURL(u)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseBearerChallenge(t *testing.T) {
+	assert.Equal(t, map[string]string{"realm": "https://auth.docker.io/token", "service": "registry.docker.io", "scope": "repository:library/fedora:pull"},
+		parseBearerChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/fedora:pull"`))
+	assert.Equal(t, map[string]string{"realm": "https://quay.io/v2/auth", "service": "quay.io"},
+		parseBearerChallenge(`Bearer realm="https://quay.io/v2/auth", service=quay.io`))
+	assert.Nil(t, parseBearerChallenge(`Basic realm="registry"`))
+}
@@ -0,0 +1,47 @@
+package yum
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// ParseError is an error decoding metadata, with where in the decompressed data it occurred, so that a
+// broken file can be inspected at that position
+type ParseError struct {
+	Offset  int64  // Offset of the error in the decompressed data, -1 if the decoder does not report it
+	Line    int    // Line of the error, 0 if the decoder does not report it
+	Element string // XML element, or YAML document, being decoded
+	Item    string // Name of the package, or id of the item, being decoded, or else of the last one decoded
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	var position []string
+	if e.Offset >= 0 {
+		position = append(position, fmt.Sprintf("offset %d", e.Offset))
+	}
+	if e.Line > 0 {
+		position = append(position, fmt.Sprintf("line %d", e.Line))
+	}
+	if e.Element != "" {
+		position = append(position, "in "+e.Element)
+	}
+	if e.Item != "" {
+		position = append(position, "near "+e.Item)
+	}
+	if len(position) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v (%v)", e.Err, strings.Join(position, ", "))
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// xmlParseError returns err with the position of decoder, which failed decoding element near item
+func xmlParseError(decoder *xml.Decoder, element, item string, err error) *ParseError {
+	line, _ := decoder.InputPos()
+	return &ParseError{Offset: decoder.InputOffset(), Line: line, Element: element, Item: item, Err: err}
+}
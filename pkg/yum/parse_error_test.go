@@ -0,0 +1,60 @@
+package yum
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorPackages(t *testing.T) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(primaryXML))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(gzipReader)
+	require.NoError(t, err)
+
+	// cut the second package after its name
+	second := bytes.LastIndex(decompressed, []byte("<package "))
+	end := second + bytes.Index(decompressed[second:], []byte("</name>")) + len("</name>")
+	result, err := ParseCompressedXMLData(bytes.NewReader(decompressed[:end]), DefaultMaxXmlSize)
+	assert.Empty(t, result)
+
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, int64(end), parseErr.Offset)
+	assert.Positive(t, parseErr.Line)
+	assert.Equal(t, "package", parseErr.Element)
+	assert.Equal(t, "tpm-quote-tools", parseErr.Item)
+	assert.ErrorContains(t, err, "in package, near tpm-quote-tools")
+}
+
+func TestParseErrorComps(t *testing.T) {
+	comps := `<comps><group><id>core</id></group><group><id>broken</id><name>Broken</group></comps>`
+	_, err := parseCompsXML(io.NopCloser(strings.NewReader(comps)), DefaultMaxXmlSize)
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "group", parseErr.Element)
+	assert.Equal(t, "broken", parseErr.Item)
+
+	// an error outside of groups is near the last group decoded
+	comps = `<comps><group><id>core</id></group><other></comps>`
+	_, err = parseCompsXML(io.NopCloser(strings.NewReader(comps)), DefaultMaxXmlSize)
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "other", parseErr.Element)
+	assert.Equal(t, "core", parseErr.Item)
+}
+
+func TestParseErrorModules(t *testing.T) {
+	modules := "---\ndocument: modulemd\nversion: 2\ndata:\n  name: nodejs\n  stream: 12\n...\n---\ndocument: modulemd\ndata: [\n"
+	_, err := parseModuleDocuments(io.NopCloser(strings.NewReader(modules)))
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, int64(-1), parseErr.Offset)
+	assert.Equal(t, "document 2", parseErr.Element)
+	assert.Equal(t, "nodejs:12", parseErr.Item)
+	assert.NotContains(t, err.Error(), "offset")
+}
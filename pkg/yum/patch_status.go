@@ -0,0 +1,111 @@
+package yum
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PatchReport compares installed packages against the packages and advisories of a repository
+type PatchReport struct {
+	Upgrades   []Upgrade  `json:"upgrades"`   // Installed packages with a newer version in the repository
+	Missing    []NEVRA    `json:"missing"`    // Installed packages with no package of the same name and arch in the repository
+	Advisories []Advisory `json:"advisories"` // Advisories listing a newer version of an installed package
+}
+
+// Upgrade is an installed package and the latest version of it in the repository
+type Upgrade struct {
+	Installed NEVRA   `json:"installed"`
+	Available Package `json:"available"`
+}
+
+// ParseInstalledNEVRAs parses a list of installed packages with one NEVRA per line, such as the output of
+// rpm -qa. Blank lines and the gpg-pubkey entries of imported keys, which have no architecture, are skipped.
+func ParseInstalledNEVRAs(r io.Reader) ([]NEVRA, error) {
+	installed := []NEVRA{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "gpg-pubkey-") {
+			continue
+		}
+		nevra, err := ParseNEVRA(line)
+		if err != nil {
+			return nil, err
+		}
+		installed = append(installed, nevra)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading installed packages: %w", err)
+	}
+	return installed, nil
+}
+
+// PatchStatus reports the available upgrades of the installed packages, the installed packages that are
+// missing from the repository, and the advisories that apply to the installed packages.
+func (r *Repository) PatchStatus(ctx context.Context, installed []NEVRA) (*PatchReport, int, error) {
+	packages, code, err := r.Packages(ctx)
+	var truncated *TruncatedError
+	if err != nil && !errors.As(err, &truncated) {
+		return nil, code, err
+	}
+	advisories, _, advisoriesErr := r.Advisories(ctx)
+	if advisoriesErr != nil {
+		return nil, code, fmt.Errorf("error getting advisories: %w", advisoriesErr)
+	}
+	return ComparePatchStatus(installed, packages, advisories), code, err
+}
+
+// ComparePatchStatus compares installed packages against packages and advisories. Packages are matched by
+// name and architecture. Advisories apply if they list a newer version of an installed package.
+func ComparePatchStatus(installed []NEVRA, packages []Package, advisories []Advisory) *PatchReport {
+	latest := map[nameArch]Package{}
+	for _, pkg := range packages {
+		key := nameArch{pkg.Name, pkg.Arch}
+		if current, ok := latest[key]; !ok || CompareEVR(pkg.NEVRA(), current.NEVRA()) > 0 {
+			latest[key] = pkg
+		}
+	}
+
+	report := &PatchReport{Upgrades: []Upgrade{}, Missing: []NEVRA{}, Advisories: []Advisory{}}
+	installedByKey := map[nameArch][]NEVRA{}
+	for _, nevra := range installed {
+		key := nameArch{nevra.Name, nevra.Arch}
+		installedByKey[key] = append(installedByKey[key], nevra)
+
+		available, ok := latest[key]
+		if !ok {
+			report.Missing = append(report.Missing, nevra)
+		} else if CompareEVR(available.NEVRA(), nevra) > 0 {
+			report.Upgrades = append(report.Upgrades, Upgrade{Installed: nevra, Available: available})
+		}
+	}
+
+	for _, advisory := range advisories {
+		if advisoryApplies(advisory, installedByKey) {
+			report.Advisories = append(report.Advisories, advisory)
+		}
+	}
+	return report
+}
+
+// nameArch is the name and architecture installed packages are matched by
+type nameArch struct {
+	name string
+	arch string
+}
+
+// advisoryApplies reports whether the advisory lists a newer version of one of the installed packages
+func advisoryApplies(advisory Advisory, installed map[nameArch][]NEVRA) bool {
+	for _, pkg := range advisory.Packages {
+		for _, nevra := range installed[nameArch{pkg.Name, pkg.Arch}] {
+			if CompareEVR(pkg.NEVRA(), nevra) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package yum
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInstalledNEVRAs(t *testing.T) {
+	installed, err := ParseInstalledNEVRAs(strings.NewReader("bash-5.1.8-6.el9.x86_64\n\ngpg-pubkey-fd431d51-4ae0493b\nopenssl-libs-1:3.0.7-27.el9.x86_64\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []NEVRA{
+		{Name: "bash", Version: "5.1.8", Release: "6.el9", Arch: "x86_64"},
+		{Name: "openssl-libs", Epoch: 1, Version: "3.0.7", Release: "27.el9", Arch: "x86_64"},
+	}, installed)
+
+	_, err = ParseInstalledNEVRAs(strings.NewReader("bash\n"))
+	assert.Error(t, err)
+}
+
+func TestComparePatchStatus(t *testing.T) {
+	packages := []Package{
+		{Name: "bash", Arch: "x86_64", Version: Version{Version: "5.1.8", Release: "9.el9"}},
+		{Name: "bash", Arch: "x86_64", Version: Version{Version: "5.1.8", Release: "10.el9"}},
+		{Name: "bash", Arch: "x86_64", Version: Version{Version: "5.1.8", Release: "6.el9"}},
+		{Name: "curl", Arch: "x86_64", Version: Version{Version: "7.76.1", Release: "26.el9"}},
+	}
+	advisories := []Advisory{
+		{ID: "RHSA-1", Packages: []AdvisoryPackage{{Name: "bash", Arch: "x86_64", Version: "5.1.8", Release: "9.el9"}}},
+		{ID: "RHSA-2", Packages: []AdvisoryPackage{{Name: "curl", Arch: "x86_64", Version: "7.76.1", Release: "26.el9"}}},
+		{ID: "RHSA-3", Packages: []AdvisoryPackage{{Name: "bash", Arch: "i686", Version: "5.1.8", Release: "9.el9"}}},
+	}
+	installed := []NEVRA{
+		{Name: "bash", Version: "5.1.8", Release: "6.el9", Arch: "x86_64"},
+		{Name: "curl", Version: "7.76.1", Release: "26.el9", Arch: "x86_64"},
+		{Name: "vim-enhanced", Epoch: 2, Version: "8.2.2637", Release: "20.el9", Arch: "x86_64"},
+	}
+
+	report := ComparePatchStatus(installed, packages, advisories)
+	assert.Equal(t, []Upgrade{{Installed: installed[0], Available: packages[1]}}, report.Upgrades)
+	assert.Equal(t, []NEVRA{installed[2]}, report.Missing)
+	require.Len(t, report.Advisories, 1)
+	assert.Equal(t, "RHSA-1", report.Advisories[0].ID)
+}
+
+func TestPatchStatus(t *testing.T) {
+	s := server()
+	defer s.Close()
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+
+	installed := []NEVRA{
+		{Name: "nss-devel", Version: "3.19.1", Release: "17.el7", Arch: "i686"},
+		{Name: "tpm-quote-tools", Version: "1.0.2", Release: "3.el7", Arch: "x86_64"},
+	}
+	report, _, err := r.PatchStatus(context.Background(), installed)
+	require.NoError(t, err)
+	require.Len(t, report.Upgrades, 1)
+	assert.Equal(t, "nss-devel", report.Upgrades[0].Available.Name)
+	assert.Empty(t, report.Missing)
+	ids := []string{}
+	for _, advisory := range report.Advisories {
+		ids = append(ids, advisory.ID)
+	}
+	assert.Equal(t, []string{"RHSA-2015:1981", "RHBA-2016:0100"}, ids)
+}
@@ -0,0 +1,123 @@
+package yum
+
+import (
+	"io"
+	"sync"
+)
+
+const (
+	// pipelineChunkSize is the size of the chunks a pipelinedReader reads ahead
+	pipelineChunkSize = 256 * 1024
+	// pipelineDepth is the number of chunks a pipelinedReader reads ahead of its consumer
+	pipelineDepth = 4
+)
+
+// pipelinedReader reads ahead from a source reader in a goroutine, handing chunks over to its consumer, so
+// that producing the data, such as decompressing it, overlaps with consuming it, such as decoding XML.
+// It must be closed to stop the goroutine if it is not read to the end.
+type pipelinedReader struct {
+	chunks  chan []byte // Chunks read from the source, closed after err is set
+	free    chan []byte // Buffers consumed, to be filled again
+	done    chan struct{}
+	err     error  // Error that stopped reading the source, io.EOF at its end
+	buffer  []byte // Chunk being consumed
+	current []byte // Unread part of buffer
+	close   sync.Once
+}
+
+// newPipelinedReader starts reading ahead from src
+func newPipelinedReader(src io.Reader) *pipelinedReader {
+	p := &pipelinedReader{
+		chunks: make(chan []byte, pipelineDepth),
+		// one buffer may be filled and one consumed while the channel of chunks is full
+		free: make(chan []byte, pipelineDepth+2),
+		done: make(chan struct{}),
+	}
+	for i := 0; i < pipelineDepth+2; i++ {
+		p.free <- *pipelineBuffers.Get().(*[]byte)
+	}
+	go p.produce(src)
+	return p
+}
+
+func (p *pipelinedReader) produce(src io.Reader) {
+	defer close(p.chunks)
+	for {
+		var buffer []byte
+		select {
+		case buffer = <-p.free:
+		case <-p.done:
+			return
+		}
+
+		n, err := io.ReadFull(src, buffer[:cap(buffer)])
+		if n > 0 {
+			select {
+			case p.chunks <- buffer[:n]:
+			case <-p.done:
+				releasePipelineBuffer(buffer)
+				return
+			}
+		} else {
+			releasePipelineBuffer(buffer)
+		}
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		if err != nil {
+			p.err = err
+			return
+		}
+	}
+}
+
+func (p *pipelinedReader) Read(b []byte) (int, error) {
+	for len(p.current) == 0 {
+		if p.buffer != nil {
+			p.free <- p.buffer
+			p.buffer = nil
+		}
+		chunk, ok := <-p.chunks
+		if !ok {
+			return 0, p.err
+		}
+		p.buffer, p.current = chunk, chunk
+	}
+	n := copy(b, p.current)
+	p.current = p.current[n:]
+	return n, nil
+}
+
+// Close stops reading ahead, releasing the buffers that are not in use. The goroutine returns once a
+// pending read of the source returns.
+func (p *pipelinedReader) Close() error {
+	p.close.Do(func() {
+		close(p.done)
+		if p.buffer != nil {
+			releasePipelineBuffer(p.buffer)
+			p.buffer, p.current = nil, nil
+		}
+		// buffers received after done is closed are not used by the goroutine anymore
+		chunks := p.chunks
+		for {
+			select {
+			case buffer := <-p.free:
+				releasePipelineBuffer(buffer)
+			case buffer, ok := <-chunks:
+				if !ok {
+					chunks = nil
+					continue
+				}
+				releasePipelineBuffer(buffer)
+			default:
+				return
+			}
+		}
+	})
+	return nil
+}
+
+func releasePipelineBuffer(buffer []byte) {
+	buffer = buffer[:cap(buffer)]
+	pipelineBuffers.Put(&buffer)
+}
@@ -0,0 +1,97 @@
+package yum
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelinedReader(t *testing.T) {
+	data := make([]byte, 3*pipelineChunkSize+123)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	p := newPipelinedReader(bytes.NewReader(data))
+	defer p.Close()
+	assert.NoError(t, iotest.TestReader(p, data))
+
+	p = newPipelinedReader(iotest.HalfReader(bytes.NewReader(data)))
+	defer p.Close()
+	read, err := io.ReadAll(p)
+	assert.NoError(t, err)
+	assert.Equal(t, data, read)
+}
+
+func TestPipelinedReaderError(t *testing.T) {
+	errSource := errors.New("source failed")
+	p := newPipelinedReader(io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(errSource)))
+	defer p.Close()
+
+	read, err := io.ReadAll(p)
+	assert.ErrorIs(t, err, errSource)
+	assert.Equal(t, "partial", string(read))
+}
+
+func TestPipelinedReaderClose(t *testing.T) {
+	p := newPipelinedReader(iotest.OneByteReader(bytes.NewReader(make([]byte, 100*pipelineChunkSize))))
+	buf := make([]byte, 10)
+	_, err := p.Read(buf)
+	require.NoError(t, err)
+	require.NoError(t, p.Close())
+	require.NoError(t, p.Close())
+
+	// the producer stops, closing the channel of chunks well before the source is read
+	chunks := 0
+	for range p.chunks {
+		chunks++
+	}
+	assert.LessOrEqual(t, chunks, pipelineDepth)
+}
+
+// benchmarkPrimaryXML returns a zstd compressed primary.xml of count packages
+func benchmarkPrimaryXML(b testing.TB, count int) []byte {
+	var primary bytes.Buffer
+	fmt.Fprintf(&primary, `<?xml version="1.0" encoding="UTF-8"?>
+<metadata xmlns="http://linux.duke.edu/metadata/common" xmlns:rpm="http://linux.duke.edu/metadata/rpm" packages="%d">`, count)
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&primary, `<package type="rpm"><name>package-%d</name><arch>x86_64</arch>
+<version epoch="0" ver="1.%d" rel="1.el9"/><checksum type="sha256" pkgid="YES">%064x</checksum>
+<summary>Package number %d</summary><description>A package for benchmarking the parsing of primary.xml</description>
+<packager>Builder</packager><url>https://example.com/</url><time file="1700000000" build="1700000000"/>
+<size package="12345" installed="54321" archive="55000"/><location href="Packages/p/package-%d-1.%d-1.el9.x86_64.rpm"/>
+<format><rpm:license>MIT</rpm:license><rpm:sourcerpm>package-%d-1.%d-1.el9.src.rpm</rpm:sourcerpm>
+<rpm:provides><rpm:entry name="package-%d" flags="EQ" epoch="0" ver="1.%d" rel="1.el9"/></rpm:provides>
+<rpm:requires><rpm:entry name="glibc"/><rpm:entry name="/bin/sh"/></rpm:requires><file>/usr/bin/package-%d</file></format></package>
+`, i, i, i, i, i, i, i, i, i, i, i)
+	}
+	primary.WriteString("</metadata>\n")
+
+	var compressed bytes.Buffer
+	encoder, err := zstd.NewWriter(&compressed)
+	require.NoError(b, err)
+	_, err = encoder.Write(primary.Bytes())
+	require.NoError(b, err)
+	require.NoError(b, encoder.Close())
+	return compressed.Bytes()
+}
+
+func BenchmarkParseCompressedXMLData(b *testing.B) {
+	data := benchmarkPrimaryXML(b, 20000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		packages, err := ParseCompressedXMLData(bytes.NewReader(data), DefaultMaxXmlSize)
+		if err != nil || len(packages) != 20000 {
+			b.Fatal(err, len(packages))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package yum
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdDecoders pools zstd decoders, whose buffers are costly to allocate for every metadata file
+var zstdDecoders sync.Pool
+
+// pipelineBuffers pools the chunk buffers of pipelinedReaders
+var pipelineBuffers = sync.Pool{
+	New: func() any {
+		buffer := make([]byte, pipelineChunkSize)
+		return &buffer
+	},
+}
+
+// newZstdReader returns a reader decompressing src with a pooled decoder. The decoder returns to the pool
+// once the reader has been read to its end.
+func newZstdReader(src io.Reader) (io.Reader, error) {
+	if decoder, ok := zstdDecoders.Get().(*zstd.Decoder); ok {
+		if err := decoder.Reset(src); err != nil {
+			return nil, err
+		}
+		return &pooledZstdReader{decoder: decoder}, nil
+	}
+	decoder, err := zstd.NewReader(src)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledZstdReader{decoder: decoder}, nil
+}
+
+// pooledZstdReader reads from a pooled decoder until it returns it to the pool at the end of the stream
+type pooledZstdReader struct {
+	decoder *zstd.Decoder
+}
+
+func (r *pooledZstdReader) Read(p []byte) (int, error) {
+	if r.decoder == nil {
+		return 0, io.EOF
+	}
+	n, err := r.decoder.Read(p)
+	if err == io.EOF {
+		// release the source before pooling the decoder
+		if r.decoder.Reset(nil) == nil {
+			zstdDecoders.Put(r.decoder)
+		}
+		r.decoder = nil
+	}
+	return n, err
+}
+
+// stringInterner deduplicates strings that repeat across the packages of a metadata file, such as
+// architectures, checksum types and licenses, so that the parsed packages share a single copy of each
+type stringInterner map[string]string
+
+func (interner stringInterner) intern(s string) string {
+	if interned, ok := interner[s]; ok {
+		return interned
+	}
+	interner[s] = s
+	return s
+}
+
+// internPackage interns the fields of pkg that are shared by many packages
+func (interner stringInterner) internPackage(pkg *Package) {
+	pkg.Type = interner.intern(pkg.Type)
+	pkg.Arch = interner.intern(pkg.Arch)
+	pkg.Version.Release = interner.intern(pkg.Version.Release)
+	pkg.Checksum.Type = interner.intern(pkg.Checksum.Type)
+	pkg.License = interner.intern(pkg.License)
+	pkg.SourceRPM = interner.intern(pkg.SourceRPM)
+}
@@ -0,0 +1,67 @@
+package yum
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringInterner(t *testing.T) {
+	interner := stringInterner{}
+	first := interner.intern(string([]byte("x86_64")))
+	second := interner.intern(string([]byte("x86_64")))
+	assert.Equal(t, "x86_64", second)
+	assert.Same(t, unsafe.StringData(first), unsafe.StringData(second))
+
+	packages, err := ParseCompressedXMLData(bytes.NewReader(benchmarkPrimaryXML(t, 3)), DefaultMaxXmlSize)
+	require.NoError(t, err)
+	require.Len(t, packages, 3)
+	assert.Equal(t, "x86_64", packages[2].Arch)
+	assert.Same(t, unsafe.StringData(packages[0].Arch), unsafe.StringData(packages[2].Arch))
+	assert.Same(t, unsafe.StringData(packages[0].License), unsafe.StringData(packages[2].License))
+	assert.Same(t, unsafe.StringData(packages[0].Checksum.Type), unsafe.StringData(packages[2].Checksum.Type))
+}
+
+func TestZstdDecoderReuse(t *testing.T) {
+	data, err := os.ReadFile("mocks/primary.xml.zst")
+	require.NoError(t, err)
+
+	// the decoder released by the first parse is reset for the second one
+	for i := 0; i < 2; i++ {
+		packages, err := ParseCompressedXMLData(bytes.NewReader(data), DefaultMaxXmlSize)
+		require.NoError(t, err)
+		assert.NotEmpty(t, packages)
+	}
+}
+
+// BenchmarkParseCompressedXMLDataRetained reports the heap retained by the packages parsed from a primary.xml,
+// which interning keeps from growing with the fields that repeat across packages
+func BenchmarkParseCompressedXMLDataRetained(b *testing.B) {
+	data := benchmarkPrimaryXML(b, 20000)
+	var retained uint64
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		collectPools()
+		runtime.ReadMemStats(&before)
+		packages, err := ParseCompressedXMLData(bytes.NewReader(data), DefaultMaxXmlSize)
+		if err != nil {
+			b.Fatal(err)
+		}
+		collectPools()
+		runtime.ReadMemStats(&after)
+		retained += after.HeapAlloc - before.HeapAlloc
+		runtime.KeepAlive(packages)
+	}
+	b.ReportMetric(float64(retained)/float64(b.N)/20000, "retained-B/package")
+}
+
+// collectPools collects garbage twice, as pooled objects survive the first collection
+func collectPools() {
+	runtime.GC()
+	runtime.GC()
+}
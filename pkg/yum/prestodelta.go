@@ -0,0 +1,155 @@
+package yum
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DeltaPackage is a package from the prestodelta metadata, listing the delta RPMs that
+// can be used to update older versions of the package to this one
+type DeltaPackage struct {
+	Name    string  `xml:"name,attr" json:"name"`
+	Epoch   int32   `xml:"epoch,attr" json:"epoch"`
+	Version string  `xml:"version,attr" json:"version"`
+	Release string  `xml:"release,attr" json:"release"`
+	Arch    string  `xml:"arch,attr" json:"arch"`
+	Deltas  []Delta `xml:"delta" json:"deltas,omitempty"`
+}
+
+// Delta is a delta RPM that updates the package from the old EVR to the new one
+type Delta struct {
+	OldEpoch   int32    `xml:"oldepoch,attr" json:"oldEpoch"`
+	OldVersion string   `xml:"oldversion,attr" json:"oldVersion"`
+	OldRelease string   `xml:"oldrelease,attr" json:"oldRelease"`
+	Filename   string   `xml:"filename" json:"filename"`
+	Sequence   string   `xml:"sequence" json:"sequence"`
+	Size       int64    `xml:"size" json:"size"`
+	Checksum   Checksum `xml:"checksum" json:"checksum"`
+}
+
+// NEVRA returns the NEVRA of the new package
+func (p DeltaPackage) NEVRA() NEVRA {
+	return NEVRA{
+		Name:    p.Name,
+		Epoch:   p.Epoch,
+		Version: p.Version,
+		Release: p.Release,
+		Arch:    p.Arch,
+	}
+}
+
+// DeltaPackages populates r.DeltaPackages with the delta RPMs from the prestodelta metadata of the repository.
+// Returns response code and error. Repositories without prestodelta metadata return no packages.
+func (r *Repository) DeltaPackages(ctx context.Context) ([]DeltaPackage, int, error) {
+	var err error
+	var prestoDeltaURL *string
+	var resp *http.Response
+	var deltaPackages []DeltaPackage
+
+	r.cacheRequest(ctx, "prestodelta", r.deltaPackages != nil)
+	if r.deltaPackages != nil {
+		return r.deltaPackages, 200, nil
+	}
+
+	if _, _, err = r.Repomd(ctx); err != nil {
+		return nil, 0, fmt.Errorf("error parsing repomd.xml: %w", err)
+	}
+
+	if prestoDeltaURL, err = r.getPrestoDeltaURL(); err != nil {
+		return nil, 0, fmt.Errorf("error parsing prestodelta URL: %w", err)
+	}
+
+	if prestoDeltaURL == nil {
+		return []DeltaPackage{}, 200, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *prestoDeltaURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	if resp, err = r.do(req, "prestodelta"); err != nil {
+		return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", *prestoDeltaURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("Cannot fetch %v: %d", *prestoDeltaURL, resp.StatusCode)
+	}
+
+	if deltaPackages, err = parsePrestoDeltaXML(r.decompressedBody(resp, "prestodelta"), *r.settings.MaxXmlSize); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("error parsing prestodelta.xml: %w", err)
+	}
+	r.parsed("prestodelta", len(deltaPackages))
+	r.deltaPackages = deltaPackages
+
+	return deltaPackages, resp.StatusCode, nil
+}
+
+func (r *Repository) getPrestoDeltaURL() (*string, error) {
+	var prestoDeltaLocation Location
+
+	for _, data := range r.repomd.Data {
+		if data.Type == "prestodelta" || data.Type == "deltainfo" {
+			prestoDeltaLocation = data.Location
+		}
+	}
+
+	if prestoDeltaLocation.Href == "" {
+		return nil, nil
+	}
+
+	prestoDeltaURL, err := r.getLocationURL(prestoDeltaLocation)
+	if err != nil {
+		return nil, err
+	}
+	return &prestoDeltaURL, nil
+}
+
+// ParsePrestoDeltaXML creates a DeltaPackage array from a compressed or uncompressed prestodelta.xml,
+// reading at most DefaultMaxXmlSize bytes of it
+func ParsePrestoDeltaXML(body io.ReadCloser) ([]DeltaPackage, error) {
+	return parsePrestoDeltaXML(body, DefaultMaxXmlSize)
+}
+
+// parsePrestoDeltaXML parses a compressed or uncompressed prestodelta.xml, reading at most maxSize bytes
+func parsePrestoDeltaXML(body io.ReadCloser, maxSize int64) ([]DeltaPackage, error) {
+	deltaPackages := []DeltaPackage{}
+
+	reader, err := ExtractIfCompressed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxSize))
+	var element, item string
+	for {
+		t, decodeError := decoder.Token()
+
+		if decodeError == io.EOF {
+			break
+		} else if decodeError != nil {
+			return nil, fmt.Errorf("error decoding token: %w", xmlParseError(decoder, element, item, decodeError))
+		} else if t == nil {
+			break
+		}
+
+		elType, ok := t.(xml.StartElement)
+		if ok && elType.Name.Local == "newpackage" {
+			var deltaPackage DeltaPackage
+			if decodeElementError := decoder.DecodeElement(&deltaPackage, &elType); decodeElementError != nil {
+				return nil, xmlParseError(decoder, elType.Name.Local, cmp.Or(deltaPackage.Name, item), decodeElementError)
+			}
+			item = deltaPackage.Name
+			deltaPackages = append(deltaPackages, deltaPackage)
+		} else if ok {
+			element = elType.Name.Local
+		}
+	}
+
+	return deltaPackages, nil
+}
@@ -0,0 +1,75 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrestoDeltaXML(t *testing.T) {
+	f, err := os.Open("mocks/prestodelta.xml.gz")
+	require.NoError(t, err)
+	defer f.Close()
+
+	deltaPackages, err := ParsePrestoDeltaXML(f)
+	require.NoError(t, err)
+	require.Len(t, deltaPackages, 2)
+
+	nss := deltaPackages[0]
+	assert.Equal(t, "nss-devel-0:3.19.1-18.el7.i686", nss.NEVRA().String())
+	require.Len(t, nss.Deltas, 2)
+	assert.Equal(t, Delta{
+		OldEpoch:   0,
+		OldVersion: "3.19.1",
+		OldRelease: "14.el7",
+		Filename:   "drpms/nss-devel-3.19.1-14.el7_3.19.1-18.el7.i686.drpm",
+		Sequence:   "nss-devel-3.19.1-14.el7-3d2f4c1a",
+		Size:       45012,
+		Checksum:   Checksum{Type: "sha256", Value: "8d3fb8d0ae4d6d3a6d6f9d1e0e6c8c3a3e1f2a1b0c9d8e7f6a5b4c3d2e1f0a9b"},
+	}, nss.Deltas[0])
+}
+
+func TestParsePrestoDeltaXMLMaxSize(t *testing.T) {
+	f, err := os.Open("mocks/prestodelta.xml.gz")
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = parsePrestoDeltaXML(f, 100)
+	assert.ErrorContains(t, err, "unexpected EOF")
+}
+
+func TestFetchDeltaPackages(t *testing.T) {
+	prestoDelta, err := os.ReadFile("mocks/prestodelta.xml.gz")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<repomd><data type="prestodelta"><location href="repodata/prestodelta.xml.gz"/></data></repomd>`))
+	})
+	mux.HandleFunc("/repodata/prestodelta.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(prestoDelta)
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	deltaPackages, code, err := r.DeltaPackages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Len(t, deltaPackages, 2)
+	assert.Equal(t, deltaPackages, r.deltaPackages)
+
+	// the mock repository has no prestodelta
+	s2 := server()
+	defer s2.Close()
+	r, _ = NewRepository(YummySettings{Client: s2.Client(), URL: &s2.URL})
+	deltaPackages, code, err = r.DeltaPackages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Empty(t, deltaPackages)
+}
@@ -0,0 +1,57 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// probeSize is the number of bytes of repomd.xml requested when the server does not support HEAD requests,
+// enough for the XML declaration and the root element
+const probeSize = 512
+
+// Probe checks whether the URL is a yum repository without downloading its metadata, by sending a HEAD
+// request for repodata/repomd.xml. If the server does not support HEAD, the first bytes of repomd.xml are
+// requested and checked to contain a repomd element. Returns whether the repository exists, the response
+// code, and an error if no response was received.
+func (r *Repository) Probe(ctx context.Context) (bool, int, error) {
+	repomdURL, err := r.getRepomdURL()
+	if err != nil {
+		return false, 0, fmt.Errorf("Error parsing Repomd URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, repomdURL, nil)
+	if err != nil {
+		return false, 0, fmt.Errorf("error creating request: %w", err)
+	}
+	resp, err := r.do(req, "probe")
+	if err != nil {
+		return false, erroredStatusCode(resp), fmt.Errorf("HEAD error for file %v: %w", repomdURL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotImplemented {
+		return resp.StatusCode == http.StatusOK, resp.StatusCode, nil
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, repomdURL, nil)
+	if err != nil {
+		return false, 0, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", probeSize-1))
+	if resp, err = r.do(req, "probe"); err != nil {
+		return false, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", repomdURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return false, resp.StatusCode, nil
+	}
+
+	// a server ignoring the range sends the whole file, of which only the beginning is read
+	start, err := io.ReadAll(io.LimitReader(resp.Body, probeSize))
+	if err != nil {
+		return false, resp.StatusCode, fmt.Errorf("error reading %v: %w", repomdURL, err)
+	}
+	return bytes.Contains(start, []byte("<repomd")), resp.StatusCode, nil
+}
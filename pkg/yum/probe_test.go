@@ -0,0 +1,63 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbe(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	exists, code, err := r.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, r.repomd, "nothing is parsed")
+
+	notFound := s.URL + "/missing"
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &notFound})
+	exists, code, err = r.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	url := "http://127.0.0.1:0"
+	r, _ = NewRepository(YummySettings{URL: &url})
+	exists, _, err = r.Probe(context.Background())
+	assert.Error(t, err)
+	assert.False(t, exists)
+}
+
+func TestProbeWithoutHead(t *testing.T) {
+	body := repomdXML
+	ranges := []string{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		_, _ = w.Write(body)
+	}))
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	exists, code, err := r.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"bytes=0-511"}, ranges)
+
+	// a page that is not a repomd.xml
+	body = []byte("<!DOCTYPE html><html><body>Welcome</body></html>")
+	exists, code, err = r.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,167 @@
+package yum
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// redHatProductOID is the prefix of the certificate extensions describing products, followed by
+// the product id and the field number
+var redHatProductOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 2312, 9, 1}
+
+// Product fields of the product certificate extensions
+const (
+	productFieldName         = 1
+	productFieldVersion      = 2
+	productFieldArch         = 3
+	productFieldProvidedTags = 4
+)
+
+// Product is a product identified by the productid certificate of a repository
+type Product struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Version      string   `json:"version"`
+	Arches       []string `json:"arches,omitempty"`
+	ProvidedTags []string `json:"providedTags,omitempty"`
+}
+
+// Products fetches the productid certificate of the repository and returns the products it identifies.
+// Returns response code and error. Repositories without a productid return no products.
+func (r *Repository) Products(ctx context.Context) ([]Product, int, error) {
+	var err error
+	var productIDURL *string
+	var resp *http.Response
+	var products []Product
+
+	if r.products != nil {
+		return r.products, 200, nil
+	}
+
+	if _, _, err = r.Repomd(ctx); err != nil {
+		return nil, 0, fmt.Errorf("error parsing repomd.xml: %w", err)
+	}
+
+	if productIDURL, err = r.getProductIDURL(); err != nil {
+		return nil, 0, fmt.Errorf("error parsing productid URL: %w", err)
+	}
+
+	if productIDURL == nil {
+		return []Product{}, 200, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *productIDURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	if resp, err = r.do(req, "productid"); err != nil {
+		return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", *productIDURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("Cannot fetch %v: %d", *productIDURL, resp.StatusCode)
+	}
+
+	reader, err := ExtractIfCompressed(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("error extracting productid: %w", err)
+	}
+	certificate, err := io.ReadAll(io.LimitReader(reader, *r.settings.MaxXmlSize+1))
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("error reading productid: %w", err)
+	}
+	if int64(len(certificate)) > *r.settings.MaxXmlSize {
+		return nil, resp.StatusCode, fmt.Errorf("productid larger than %d bytes", *r.settings.MaxXmlSize)
+	}
+
+	if products, err = ParseProductCertificate(certificate); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("error parsing productid: %w", err)
+	}
+	r.products = products
+
+	return products, resp.StatusCode, nil
+}
+
+func (r *Repository) getProductIDURL() (*string, error) {
+	var productIDLocation Location
+
+	for _, data := range r.repomd.Data {
+		if data.Type == "productid" {
+			productIDLocation = data.Location
+		}
+	}
+
+	if productIDLocation.Href == "" {
+		return nil, nil
+	}
+
+	productIDURL, err := r.getLocationURL(productIDLocation)
+	if err != nil {
+		return nil, err
+	}
+	return &productIDURL, nil
+}
+
+// ParseProductCertificate parses a PEM encoded product certificate, returning the products
+// described by its Red Hat product extensions
+func ParseProductCertificate(certificate []byte) ([]Product, error) {
+	block, _ := pem.Decode(certificate)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	products := []Product{}
+	productIndex := map[string]int{}
+	for _, extension := range cert.Extensions {
+		id := extension.Id
+		if len(id) != len(redHatProductOID)+2 || !id[:len(redHatProductOID)].Equal(redHatProductOID) {
+			continue
+		}
+
+		productID := strconv.Itoa(id[len(redHatProductOID)])
+		index, ok := productIndex[productID]
+		if !ok {
+			index = len(products)
+			productIndex[productID] = index
+			products = append(products, Product{ID: productID})
+		}
+
+		value := extensionString(extension.Value)
+		switch id[len(redHatProductOID)+1] {
+		case productFieldName:
+			products[index].Name = value
+		case productFieldVersion:
+			products[index].Version = value
+		case productFieldArch:
+			products[index].Arches = splitList(value)
+		case productFieldProvidedTags:
+			products[index].ProvidedTags = splitList(value)
+		}
+	}
+
+	if len(products) == 0 {
+		return nil, fmt.Errorf("certificate does not describe any products")
+	}
+	return products, nil
+}
+
+// extensionString decodes an extension value stored as an ASN.1 string, falling back to the raw bytes
+func extensionString(value []byte) string {
+	var decoded string
+	if rest, err := asn1.Unmarshal(value, &decoded); err == nil && len(rest) == 0 {
+		return decoded
+	}
+	return string(value)
+}
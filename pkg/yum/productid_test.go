@@ -0,0 +1,100 @@
+package yum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func productCertificate(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	extension := func(field int, value string) pkix.Extension {
+		encoded, err := asn1.MarshalWithParams(value, "utf8")
+		require.NoError(t, err)
+		return pkix.Extension{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 2312, 9, 1, 479, field}, Value: encoded}
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Red Hat Product ID"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtraExtensions: []pkix.Extension{
+			extension(productFieldName, "Red Hat Enterprise Linux for x86_64"),
+			extension(productFieldVersion, "9.4"),
+			extension(productFieldArch, "x86_64"),
+			extension(productFieldProvidedTags, "rhel-9,rhel-9-x86_64"),
+		},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestParseProductCertificate(t *testing.T) {
+	products, err := ParseProductCertificate(productCertificate(t))
+	require.NoError(t, err)
+	assert.Equal(t, []Product{{
+		ID:           "479",
+		Name:         "Red Hat Enterprise Linux for x86_64",
+		Version:      "9.4",
+		Arches:       []string{"x86_64"},
+		ProvidedTags: []string{"rhel-9", "rhel-9-x86_64"},
+	}}, products)
+
+	_, err = ParseProductCertificate([]byte("not a certificate"))
+	assert.Error(t, err)
+}
+
+func TestFetchProducts(t *testing.T) {
+	certificate := productCertificate(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<repomd xmlns="http://linux.duke.edu/metadata/repo">
+<data type="productid">
+<location href="repodata/productid"/>
+</data>
+</repomd>`))
+	})
+	mux.HandleFunc("/repodata/productid", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(certificate)
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	products, code, err := r.Products(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	require.Len(t, products, 1)
+	assert.Equal(t, "479", products[0].ID)
+	assert.Equal(t, "9.4", products[0].Version)
+
+	// the certificate is read up to MaxXmlSize
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, MaxXmlSize: Ptr(int64(len(certificate) - 1))})
+	_, _, err = r.Products(context.Background())
+	assert.ErrorContains(t, err, "productid larger than")
+
+	// the mock repository has no productid
+	s2 := server()
+	defer s2.Close()
+	r, _ = NewRepository(YummySettings{Client: s2.Client(), URL: &s2.URL})
+	products, code, err = r.Products(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Empty(t, products)
+}
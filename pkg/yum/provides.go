@@ -0,0 +1,56 @@
+package yum
+
+import (
+	"context"
+	"errors"
+)
+
+// Capability is an entry of the provides of a package, such as a package name, a soname or a
+// pkgconfig module, with an optional version
+type Capability struct {
+	Name    string `xml:"name,attr" json:"name"`
+	Flags   string `xml:"flags,attr,omitempty" json:"flags,omitempty"` // "EQ", "LT", "LE", "GT" or "GE", empty if unversioned
+	Epoch   int32  `xml:"epoch,attr,omitempty" json:"epoch,omitempty"`
+	Version string `xml:"ver,attr,omitempty" json:"version,omitempty"`
+	Release string `xml:"rel,attr,omitempty" json:"release,omitempty"`
+}
+
+// WhatProvides returns the packages providing a capability, matched by name, or a file, matched by path
+// against the files listed in primary.xml. Lookups use an index over the packages that is built once, on first use.
+func (r *Repository) WhatProvides(ctx context.Context, capability string) ([]Package, int, error) {
+	packages, code, err := r.Packages(ctx)
+	var truncated *TruncatedError
+	if err != nil && !errors.As(err, &truncated) {
+		return nil, code, err
+	}
+
+	if r.providesIndex == nil {
+		r.providesIndex = indexProvides(packages)
+	}
+
+	result := []Package{}
+	for _, i := range r.providesIndex[capability] {
+		result = append(result, packages[i])
+	}
+	return result, code, err
+}
+
+// indexProvides maps each capability name and file path to the indexes of the packages providing it
+func indexProvides(packages []Package) map[string][]int {
+	index := map[string][]int{}
+	add := func(key string, i int) {
+		if indexes := index[key]; len(indexes) > 0 && indexes[len(indexes)-1] == i {
+			return
+		}
+		index[key] = append(index[key], i)
+	}
+	for i, pkg := range packages {
+		for _, provide := range pkg.Provides {
+			add(provide.Name, i)
+		}
+		for _, file := range pkg.Files {
+			add(file.Path, i)
+		}
+	}
+	return index
+}
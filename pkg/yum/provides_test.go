@@ -0,0 +1,68 @@
+package yum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProvides(t *testing.T) {
+	s := server()
+	defer s.Close()
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+
+	packages, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	require.Len(t, packages, 2)
+	assert.Len(t, packages[0].Provides, 4)
+	assert.Equal(t, Capability{Name: "nss-devel", Flags: "EQ", Version: "3.19.1", Release: "18.el7"}, packages[0].Provides[0])
+	assert.Equal(t, Capability{Name: "pkgconfig(nss)", Flags: "EQ", Version: "3.19.1"}, packages[0].Provides[3])
+	assert.Equal(t, []PackageFile{{Path: "/usr/bin/nss-config"}}, packages[0].Files)
+	assert.Len(t, packages[1].Files, 8)
+}
+
+func TestWhatProvides(t *testing.T) {
+	s := server()
+	defer s.Close()
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+
+	tests := []struct {
+		capability string
+		expected   []string
+	}{
+		{"nss-devel", []string{"nss-devel"}},
+		{"pkgconfig(nss)", []string{"nss-devel"}},
+		{"tpm-quote-tools(x86-64)", []string{"tpm-quote-tools"}},
+		{"/usr/bin/tpm_getquote", []string{"tpm-quote-tools"}},
+		{"/bin/sh", []string{}},
+		{"nspr-devel", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.capability, func(t *testing.T) {
+			packages, _, err := r.WhatProvides(context.Background(), tt.capability)
+			require.NoError(t, err)
+			names := []string{}
+			for _, pkg := range packages {
+				names = append(names, pkg.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+	assert.NotNil(t, r.providesIndex)
+
+	r.Clear()
+	assert.Nil(t, r.providesIndex)
+}
+
+func TestIndexProvides(t *testing.T) {
+	packages := []Package{
+		{Name: "foo", Provides: []Capability{{Name: "foo"}, {Name: "foo"}, {Name: "bar"}}},
+		{Name: "bar", Provides: []Capability{{Name: "bar"}}, Files: []PackageFile{{Path: "/usr/bin/bar"}}},
+	}
+	index := indexProvides(packages)
+	assert.Equal(t, []int{0}, index["foo"])
+	assert.Equal(t, []int{0, 1}, index["bar"])
+	assert.Equal(t, []int{1}, index["/usr/bin/bar"])
+}
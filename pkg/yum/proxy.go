@@ -0,0 +1,35 @@
+package yum
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// cloneTransport returns a copy of the transport of client, the default transport if it has none, so that it
+// can be configured without affecting other users of the client. client must be an *http.Client.
+func cloneTransport(doer HTTPDoer) (*http.Transport, error) {
+	client, ok := doer.(*http.Client)
+	if !ok {
+		return nil, fmt.Errorf("cannot configure the transport of a %T client", doer)
+	}
+	switch t := client.Transport.(type) {
+	case nil:
+		return http.DefaultTransport.(*http.Transport).Clone(), nil
+	case *http.Transport:
+		return t.Clone(), nil
+	default:
+		return nil, fmt.Errorf("cannot configure a %T transport", client.Transport)
+	}
+}
+
+// directClient returns a copy of client that connects directly, without a proxy, whatever the environment
+func directClient(client HTTPDoer) (HTTPDoer, error) {
+	transport, err := cloneTransport(client)
+	if err != nil {
+		return nil, fmt.Errorf("error disabling proxy: %w", err)
+	}
+	transport.Proxy = nil
+	direct := *client.(*http.Client)
+	direct.Transport = transport
+	return &direct, nil
+}
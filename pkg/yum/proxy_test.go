@@ -0,0 +1,57 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisableProxy(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	proxied := 0
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	r, _ := NewRepository(YummySettings{Client: client, URL: &s.URL})
+	_, code, _ := r.Repomd(context.Background())
+	assert.Equal(t, http.StatusBadGateway, code)
+	assert.Equal(t, 1, proxied)
+
+	r, err := NewRepository(YummySettings{Client: client, URL: &s.URL, DisableProxy: true})
+	require.NoError(t, err)
+	_, code, err = r.Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, proxied)
+	assert.NotNil(t, client.Transport.(*http.Transport).Proxy, "the given client is not modified")
+
+	// a client configured later connects directly too
+	r.Configure(YummySettings{Client: client})
+	_, code, _ = r.Repomd(context.Background())
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, proxied)
+}
+
+type customTransport struct{}
+
+func (customTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, http.ErrNotSupported
+}
+
+func TestDisableProxyCustomTransport(t *testing.T) {
+	url := "http://example.com"
+	_, err := NewRepository(YummySettings{Client: &http.Client{Transport: customTransport{}}, URL: &url, DisableProxy: true})
+	assert.ErrorContains(t, err, "error disabling proxy")
+}
@@ -0,0 +1,148 @@
+package yum
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PackageURL is the package URL (purl) of an RPM, such as
+// pkg:rpm/fedora/curl@7.50.3-1.fc25?arch=i386&epoch=1, used to identify packages in vulnerability databases
+type PackageURL struct {
+	Distro        string // namespace of the purl, the vendor of the package such as "fedora" or "redhat"
+	NEVRA         NEVRA
+	RepositoryURL string // repository_url qualifier, empty if unknown
+}
+
+// PURL returns the canonical package URL of the package, with distro as namespace
+func (p Package) PURL(distro string) string {
+	return PackageURL{Distro: distro, NEVRA: p.NEVRA()}.String()
+}
+
+// String formats the package URL canonically: the namespace is lower case, qualifiers are sorted and
+// only set if known, and a zero epoch is omitted
+func (u PackageURL) String() string {
+	var purl strings.Builder
+	purl.WriteString("pkg:rpm/")
+	if u.Distro != "" {
+		purl.WriteString(purlEscape(strings.ToLower(u.Distro)))
+		purl.WriteString("/")
+	}
+	purl.WriteString(purlEscape(u.NEVRA.Name))
+	if u.NEVRA.Version != "" {
+		purl.WriteString("@")
+		purl.WriteString(purlEscape(u.NEVRA.Version + "-" + u.NEVRA.Release))
+	}
+
+	qualifiers := map[string]string{}
+	if u.NEVRA.Arch != "" {
+		qualifiers["arch"] = u.NEVRA.Arch
+	}
+	if u.NEVRA.Epoch != 0 {
+		qualifiers["epoch"] = strconv.FormatInt(int64(u.NEVRA.Epoch), 10)
+	}
+	if u.RepositoryURL != "" {
+		qualifiers["repository_url"] = u.RepositoryURL
+	}
+	keys := make([]string, 0, len(qualifiers))
+	for key := range qualifiers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i == 0 {
+			purl.WriteString("?")
+		} else {
+			purl.WriteString("&")
+		}
+		purl.WriteString(key + "=" + purlEscape(qualifiers[key]))
+	}
+	return purl.String()
+}
+
+// ParsePURL parses an rpm package URL, such as pkg:rpm/redhat/openssl@1.0.1e-30.el6_6.5?arch=x86_64&epoch=1.
+// The version is split into version and release at its last dash. Subpaths and unknown qualifiers are ignored.
+func ParsePURL(purl string) (PackageURL, error) {
+	var result PackageURL
+
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return result, fmt.Errorf("invalid purl %v: missing pkg scheme", purl)
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, rawQualifiers, _ := strings.Cut(rest, "?")
+	rest = strings.Trim(rest, "/")
+
+	purlType, rest, _ := strings.Cut(rest, "/")
+	if !strings.EqualFold(purlType, "rpm") {
+		return result, fmt.Errorf("invalid purl %v: not an rpm purl", purl)
+	}
+
+	var nameVersion string
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		distro, err := url.PathUnescape(rest[:i])
+		if err != nil {
+			return result, fmt.Errorf("invalid purl %v: %w", purl, err)
+		}
+		result.Distro = strings.ToLower(distro)
+		nameVersion = rest[i+1:]
+	} else {
+		nameVersion = rest
+	}
+
+	rawName, rawVersion, hasVersion := strings.Cut(nameVersion, "@")
+	name, err := url.PathUnescape(rawName)
+	if err != nil || name == "" {
+		return result, fmt.Errorf("invalid purl %v: bad name", purl)
+	}
+	result.NEVRA.Name = name
+	if hasVersion {
+		version, err := url.PathUnescape(rawVersion)
+		if err != nil {
+			return result, fmt.Errorf("invalid purl %v: %w", purl, err)
+		}
+		if i := strings.LastIndex(version, "-"); i >= 0 {
+			result.NEVRA.Version, result.NEVRA.Release = version[:i], version[i+1:]
+		} else {
+			result.NEVRA.Version = version
+		}
+	}
+
+	if rawQualifiers != "" {
+		for _, qualifier := range strings.Split(rawQualifiers, "&") {
+			key, rawValue, _ := strings.Cut(qualifier, "=")
+			value, err := url.PathUnescape(rawValue)
+			if err != nil {
+				return result, fmt.Errorf("invalid purl %v: %w", purl, err)
+			}
+			switch strings.ToLower(key) {
+			case "arch":
+				result.NEVRA.Arch = value
+			case "epoch":
+				epoch, err := strconv.ParseInt(value, 10, 32)
+				if err != nil {
+					return result, fmt.Errorf("invalid purl %v: bad epoch: %w", purl, err)
+				}
+				result.NEVRA.Epoch = int32(epoch)
+			case "repository_url":
+				result.RepositoryURL = value
+			}
+		}
+	}
+	return result, nil
+}
+
+// purlEscape percent-encodes all but the unreserved characters of s
+func purlEscape(s string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(s) {
+		if 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || strings.IndexByte("-._~", b) >= 0 {
+			escaped.WriteByte(b)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
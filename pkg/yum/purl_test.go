@@ -0,0 +1,43 @@
+package yum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPURL(t *testing.T) {
+	pkg := Package{Name: "nodejs", Arch: "x86_64", Version: Version{Epoch: 1, Version: "18.14.2", Release: "2.module+el8.7.0+18113+f1e9e2f0"}}
+	assert.Equal(t, "pkg:rpm/redhat/nodejs@18.14.2-2.module%2Bel8.7.0%2B18113%2Bf1e9e2f0?arch=x86_64&epoch=1", pkg.PURL("RedHat"))
+
+	pkg.Version.Epoch = 0
+	assert.Equal(t, "pkg:rpm/nodejs@18.14.2-2.module%2Bel8.7.0%2B18113%2Bf1e9e2f0?arch=x86_64", pkg.PURL(""))
+
+	purl := PackageURL{Distro: "fedora", NEVRA: NEVRA{Name: "curl", Version: "7.50.3", Release: "1.fc25", Arch: "i386"}, RepositoryURL: "https://example.com/repo/"}
+	assert.Equal(t, "pkg:rpm/fedora/curl@7.50.3-1.fc25?arch=i386&repository_url=https%3A%2F%2Fexample.com%2Frepo%2F", purl.String())
+}
+
+func TestParsePURL(t *testing.T) {
+	purl, err := ParsePURL("pkg:rpm/redhat/nodejs@18.14.2-2.module%2Bel8.7.0%2B18113%2Bf1e9e2f0?epoch=1&arch=x86_64")
+	require.NoError(t, err)
+	assert.Equal(t, PackageURL{
+		Distro: "redhat",
+		NEVRA:  NEVRA{Name: "nodejs", Epoch: 1, Version: "18.14.2", Release: "2.module+el8.7.0+18113+f1e9e2f0", Arch: "x86_64"},
+	}, purl)
+
+	// purls round trip
+	expected := PackageURL{Distro: "fedora", NEVRA: NEVRA{Name: "curl", Version: "7.50.3", Release: "1.fc25", Arch: "i386"}, RepositoryURL: "https://example.com/repo/"}
+	purl, err = ParsePURL(expected.String())
+	require.NoError(t, err)
+	assert.Equal(t, expected, purl)
+
+	purl, err = ParsePURL("pkg:RPM/curl#usr/bin")
+	require.NoError(t, err)
+	assert.Equal(t, PackageURL{NEVRA: NEVRA{Name: "curl"}}, purl)
+
+	for _, invalid := range []string{"", "rpm/curl", "pkg:deb/debian/curl@7.50.3-1", "pkg:rpm/", "pkg:rpm/curl@1-1?epoch=x"} {
+		_, err = ParsePURL(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,43 @@
+package yum
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// HostRateLimiters hands out one rate limiter per host, so that the repositories on a host share its limit
+// when their YummySettings.RateLimiter is taken from the same HostRateLimiters
+type HostRateLimiters struct {
+	limit    rate.Limit
+	burst    int
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewHostRateLimiters returns rate limiters allowing limit requests per second, in bursts of up to burst
+// requests, to each host
+func NewHostRateLimiters(limit rate.Limit, burst int) *HostRateLimiters {
+	return &HostRateLimiters{limit: limit, burst: burst, limiters: map[string]*rate.Limiter{}}
+}
+
+// ForURL returns the rate limiter of the host of repositoryURL
+func (h *HostRateLimiters) ForURL(repositoryURL string) (*rate.Limiter, error) {
+	u, err := url.Parse(repositoryURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing repository URL: %w", err)
+	}
+	host := strings.ToLower(u.Host)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	limiter, ok := h.limiters[host]
+	if !ok {
+		limiter = rate.NewLimiter(h.limit, h.burst)
+		h.limiters[host] = limiter
+	}
+	return limiter, nil
+}
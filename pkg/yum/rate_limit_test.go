@@ -0,0 +1,53 @@
+package yum
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimiter(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	limiter := rate.NewLimiter(rate.Every(50*time.Millisecond), 1)
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, RateLimiter: limiter})
+
+	start := time.Now()
+	_, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	r.Clear()
+	_, _, err = r.Repomd(context.Background())
+	require.NoError(t, err)
+	// the burst allows the first request, the other two wait for the limiter
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Clear()
+	_, _, err = r.Repomd(ctx)
+	assert.ErrorContains(t, err, "rate limiter")
+}
+
+func TestHostRateLimiters(t *testing.T) {
+	limiters := NewHostRateLimiters(10, 2)
+
+	first, err := limiters.ForURL("https://cdn.example.com/content/baseos/")
+	require.NoError(t, err)
+	second, err := limiters.ForURL("https://CDN.example.com/content/appstream/")
+	require.NoError(t, err)
+	other, err := limiters.ForURL("https://mirror.example.com/")
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.NotSame(t, first, other)
+	assert.Equal(t, rate.Limit(10), first.Limit())
+	assert.Equal(t, 2, first.Burst())
+
+	_, err = limiters.ForURL("://bad")
+	assert.Error(t, err)
+}
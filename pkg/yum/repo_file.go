@@ -0,0 +1,316 @@
+package yum
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// RepoConfig is a repository defined in a section of a dnf or yum .repo file
+type RepoConfig struct {
+	ID            string   // Section name of the repository
+	Name          string   // Human readable name
+	BaseURLs      []string // Base URLs of the repository, in order of preference
+	MirrorList    string   // URL of a list of base URLs, used when there are no BaseURLs
+	Metalink      string   // URL of a metalink listing repomd.xml URLs, used when there are no BaseURLs
+	GPGKeys       []string // URLs of the keys trusted to sign the repository, file:// URLs for local keys
+	GPGCheck      bool
+	RepoGPGCheck  bool
+	SSLClientCert string // Path of the PEM client certificate presented to the repository
+	SSLClientKey  string // Path of the PEM key of SSLClientCert
+	SSLCACert     string // Path of the PEM CA certificates the repository's certificate is verified against
+	SSLVerify     bool
+	Enabled       bool
+}
+
+// ParseRepoFile parses the repositories defined in a .repo file. Keys are given as written, without
+// substituting yum variables. A [main] section, as in dnf.conf, is not a repository and is skipped.
+func ParseRepoFile(reader io.Reader) ([]RepoConfig, error) {
+	sections, err := parseINI(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing repo file: %w", err)
+	}
+
+	configs := []RepoConfig{}
+	for _, section := range sections {
+		if section.Name == "main" {
+			continue
+		}
+		config := RepoConfig{
+			ID:            section.Name,
+			Name:          section.Keys["name"],
+			BaseURLs:      splitRepoList(section.Keys["baseurl"]),
+			MirrorList:    section.Keys["mirrorlist"],
+			Metalink:      section.Keys["metalink"],
+			GPGKeys:       splitRepoList(section.Keys["gpgkey"]),
+			SSLClientCert: section.Keys["sslclientcert"],
+			SSLClientKey:  section.Keys["sslclientkey"],
+			SSLCACert:     section.Keys["sslcacert"],
+		}
+		if config.GPGCheck, err = parseRepoBool(section.Keys, "gpgcheck", false); err != nil {
+			return nil, fmt.Errorf("repository %v: %w", section.Name, err)
+		}
+		if config.RepoGPGCheck, err = parseRepoBool(section.Keys, "repo_gpgcheck", false); err != nil {
+			return nil, fmt.Errorf("repository %v: %w", section.Name, err)
+		}
+		if config.SSLVerify, err = parseRepoBool(section.Keys, "sslverify", true); err != nil {
+			return nil, fmt.Errorf("repository %v: %w", section.Name, err)
+		}
+		if config.Enabled, err = parseRepoBool(section.Keys, "enabled", true); err != nil {
+			return nil, fmt.Errorf("repository %v: %w", section.Name, err)
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// LoadRepoFile parses the .repo file at path and returns its enabled repositories, configured as described
+// by RepoConfig.Repository
+func LoadRepoFile(ctx context.Context, path string, settings YummySettings) ([]Repository, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	configs, err := ParseRepoFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %v: %w", path, err)
+	}
+
+	repos := []Repository{}
+	for _, config := range configs {
+		if !config.Enabled {
+			continue
+		}
+		repo, err := config.Repository(ctx, settings)
+		if err != nil {
+			return nil, fmt.Errorf("error loading %v: repository %v: %w", path, config.ID, err)
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// Repository creates the repository of the config, with settings for everything the config does not set.
+// The URL is the first base URL or, without one, the first mirror of the mirror list or metalink, with the
+// yum variables of settings.Vars substituted in either. When the config sets SSL certificates, the client's transport
+// is cloned to present and verify them, in addition to settings.TLSConfig. The keys of GPGKeys are read, or fetched, and trusted as well as
+// settings.GPGKeys.
+func (c RepoConfig) Repository(ctx context.Context, settings YummySettings) (Repository, error) {
+	if isNilClient(settings.Client) {
+		settings.Client = http.DefaultClient
+	}
+	client, err := dialerClient(settings.Client, settings)
+	if err != nil {
+		return Repository{}, err
+	}
+	if client, err = c.client(client); err != nil {
+		return Repository{}, err
+	}
+	settings.Client = client
+	// the SSL options are added to the TLS config of the client's transport, which must not be replaced
+	settings.TLSConfig = nil
+
+	repoURL, err := c.baseURL(ctx, client, settings.Vars)
+	if err != nil {
+		return Repository{}, err
+	}
+	settings.URL = &repoURL
+
+	keys := append([]string{}, settings.GPGKeys...)
+	for _, keyURL := range c.GPGKeys {
+		key, err := readRepoGPGKey(ctx, SubstituteVars(keyURL, settings.Vars), client)
+		if err != nil {
+			return Repository{}, fmt.Errorf("error reading gpgkey %v: %w", keyURL, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) > 0 {
+		settings.GPGKeys = keys
+	}
+
+	return NewRepository(settings)
+}
+
+// baseURL returns the URL of the repository, resolving the mirror list or metalink if there are no base URLs
+func (c RepoConfig) baseURL(ctx context.Context, client HTTPDoer, vars map[string]string) (string, error) {
+	if len(c.BaseURLs) > 0 {
+		// substituted by the repository, so that Configure can change the vars
+		return c.BaseURLs[0], nil
+	}
+	if c.MirrorList == "" && c.Metalink == "" {
+		return "", fmt.Errorf("no baseurl, mirrorlist or metalink")
+	}
+
+	listURL := SubstituteVars(c.Metalink, vars)
+	if listURL == "" {
+		listURL = SubstituteVars(c.MirrorList, vars)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("GET error for file %v: %w", listURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Cannot fetch %v: %d", listURL, resp.StatusCode)
+	}
+
+	var mirrors []string
+	if c.Metalink != "" {
+		mirrors, err = parseMetalinkMirrors(resp.Body)
+	} else {
+		mirrors, err = parseMirrorList(resp.Body)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error parsing %v: %w", listURL, err)
+	}
+	if len(mirrors) == 0 {
+		return "", fmt.Errorf("no mirrors in %v", listURL)
+	}
+	return mirrors[0], nil
+}
+
+// client returns base, or a copy of it with a transport presenting and verifying the SSL certificates of the
+// config if it sets any
+func (c RepoConfig) client(base HTTPDoer) (HTTPDoer, error) {
+	if c.SSLClientCert == "" && c.SSLCACert == "" && c.SSLVerify {
+		return base, nil
+	}
+
+	transport, err := cloneTransport(base)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring SSL options: %w", err)
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	tlsConfig := transport.TLSClientConfig
+
+	if c.SSLClientCert != "" {
+		keyPath := c.SSLClientKey
+		if keyPath == "" {
+			keyPath = c.SSLClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(c.SSLClientCert, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("error loading sslclientcert: %w", err)
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+	if c.SSLCACert != "" {
+		pem, err := os.ReadFile(c.SSLCACert)
+		if err != nil {
+			return nil, fmt.Errorf("error loading sslcacert: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("error loading sslcacert: no certificates in %v", c.SSLCACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	tlsConfig.InsecureSkipVerify = !c.SSLVerify
+
+	client := *base.(*http.Client)
+	client.Transport = transport
+	return &client, nil
+}
+
+// readRepoGPGKey reads the armored key at a file:// URL, or fetches it from any other URL
+func readRepoGPGKey(ctx context.Context, keyURL string, client HTTPDoer) (string, error) {
+	u, err := url.Parse(keyURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "file" {
+		key, err := os.ReadFile(u.Path)
+		if err != nil {
+			return "", err
+		}
+		if err := checkGPGKey(string(key)); err != nil {
+			return "", err
+		}
+		return string(key), nil
+	}
+	key, _, err := FetchGPGKey(ctx, keyURL, client)
+	if err != nil {
+		return "", err
+	}
+	return *key, nil
+}
+
+// parseMirrorList parses a mirror list, one base URL per line with # comments
+func parseMirrorList(reader io.Reader) ([]string, error) {
+	mirrors := []string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		mirrors = append(mirrors, line)
+	}
+	return mirrors, scanner.Err()
+}
+
+// parseMetalinkMirrors parses the http and https URLs of the repomd.xml of a metalink, in order of preference,
+// returning the base URLs of the repository they belong to
+func parseMetalinkMirrors(reader io.Reader) ([]string, error) {
+	var metalink struct {
+		URLs []struct {
+			Protocol   string `xml:"protocol,attr"`
+			Preference int    `xml:"preference,attr"`
+			URL        string `xml:",chardata"`
+		} `xml:"files>file>resources>url"`
+	}
+	if err := xml.NewDecoder(reader).Decode(&metalink); err != nil {
+		return nil, err
+	}
+
+	urls := metalink.URLs
+	sort.SliceStable(urls, func(i, j int) bool {
+		return urls[i].Preference > urls[j].Preference
+	})
+	mirrors := []string{}
+	for _, u := range urls {
+		if u.Protocol == "http" || u.Protocol == "https" {
+			mirrors = append(mirrors, strings.TrimSuffix(strings.TrimSpace(u.URL), "repodata/repomd.xml"))
+		}
+	}
+	return mirrors, nil
+}
+
+// splitRepoList splits a .repo value listing several URLs, separated by whitespace, newlines or commas
+func splitRepoList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// parseRepoBool parses a boolean .repo key, such as enabled=1, returning def if it is not set
+func parseRepoBool(keys map[string]string, key string, def bool) (bool, error) {
+	value, ok := keys[key]
+	if !ok || value == "" {
+		return def, nil
+	}
+	switch strings.ToLower(value) {
+	case "1", "yes", "true", "on":
+		return true, nil
+	case "0", "no", "false", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %v = %v", key, value)
+}
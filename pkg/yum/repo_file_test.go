@@ -0,0 +1,175 @@
+package yum
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRepoFile = `[main]
+gpgcheck=1
+
+[baseos]
+name=BaseOS $releasever - $basearch
+baseurl=https://mirror.example.com/$releasever/BaseOS/$basearch/os/
+        https://backup.example.com/$releasever/BaseOS/$basearch/os/
+gpgcheck=1
+gpgkey=file:///etc/pki/rpm-gpg/RPM-GPG-KEY-example, https://example.com/key.asc
+sslclientcert=/etc/pki/entitlement/cert.pem
+sslclientkey=/etc/pki/entitlement/key.pem
+enabled=1
+
+[appstream]
+name=AppStream
+mirrorlist=https://mirrors.example.com/?repo=appstream-$releasever
+enabled=0
+sslverify=false
+`
+
+func TestParseRepoFile(t *testing.T) {
+	configs, err := ParseRepoFile(strings.NewReader(testRepoFile))
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+
+	assert.Equal(t, RepoConfig{
+		ID:   "baseos",
+		Name: "BaseOS $releasever - $basearch",
+		BaseURLs: []string{
+			"https://mirror.example.com/$releasever/BaseOS/$basearch/os/",
+			"https://backup.example.com/$releasever/BaseOS/$basearch/os/",
+		},
+		GPGKeys:       []string{"file:///etc/pki/rpm-gpg/RPM-GPG-KEY-example", "https://example.com/key.asc"},
+		GPGCheck:      true,
+		SSLClientCert: "/etc/pki/entitlement/cert.pem",
+		SSLClientKey:  "/etc/pki/entitlement/key.pem",
+		SSLVerify:     true,
+		Enabled:       true,
+	}, configs[0])
+
+	assert.Equal(t, "appstream", configs[1].ID)
+	assert.Equal(t, "https://mirrors.example.com/?repo=appstream-$releasever", configs[1].MirrorList)
+	assert.False(t, configs[1].Enabled)
+	assert.False(t, configs[1].SSLVerify)
+
+	_, err = ParseRepoFile(strings.NewReader("[bad]\nenabled=maybe\n"))
+	assert.ErrorContains(t, err, "invalid boolean enabled = maybe")
+}
+
+func TestLoadRepoFile(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "RPM-GPG-KEY-test")
+	require.NoError(t, os.WriteFile(keyPath, gpgKey, 0600))
+	repoPath := filepath.Join(dir, "test.repo")
+	repoFile := fmt.Sprintf(`[test]
+name=Test
+baseurl=%v/$dir
+gpgkey=file://%v
+[disabled]
+baseurl=%v
+enabled=0
+`, s.URL, keyPath, s.URL)
+	require.NoError(t, os.WriteFile(repoPath, []byte(repoFile), 0600))
+
+	repos, err := LoadRepoFile(context.Background(), repoPath, YummySettings{Client: s.Client(), Vars: map[string]string{"dir": ""}})
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	assert.Equal(t, []string{string(gpgKey)}, repos[0].settings.GPGKeys)
+
+	repomd, code, err := repos[0].Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.NotNil(t, repomd)
+
+	// files that are not keys are rejected, whether read or fetched
+	badKeyPath := filepath.Join(dir, "RPM-GPG-KEY-bad")
+	require.NoError(t, os.WriteFile(badKeyPath, []byte("<html>not a key</html>"), 0600))
+	for _, keyURL := range []string{"file://" + badKeyPath, s.URL + "/repodata/repomd.xml"} {
+		repoFile = fmt.Sprintf("[test]\nbaseurl=%v\ngpgkey=%v\n", s.URL, keyURL)
+		require.NoError(t, os.WriteFile(repoPath, []byte(repoFile), 0600))
+		_, err = LoadRepoFile(context.Background(), repoPath, YummySettings{Client: s.Client()})
+		assert.ErrorContains(t, err, "error reading gpg key", keyURL)
+	}
+}
+
+func TestRepoConfigMirrors(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	lists := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mirrorlist":
+			fmt.Fprintf(w, "# mirrors of %v\n\n%v/\nhttp://unused.example.com/\n", r.URL.Query().Get("repo"), s.URL)
+		case "/metalink":
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?>
+<metalink version="3.0" xmlns="http://www.metalinker.org/">
+ <files>
+  <file name="repomd.xml">
+   <resources maxconnections="1">
+    <url protocol="rsync" type="rsync" preference="100">rsync://unused.example.com/repodata/repomd.xml</url>
+    <url protocol="http" type="http" preference="90">http://unused.example.com/repodata/repomd.xml</url>
+    <url protocol="http" type="http" preference="99">%v/repodata/repomd.xml</url>
+   </resources>
+  </file>
+ </files>
+</metalink>`, s.URL)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer lists.Close()
+
+	settings := YummySettings{Client: s.Client(), Vars: map[string]string{"releasever": "9"}}
+	repo, err := RepoConfig{MirrorList: lists.URL + "/mirrorlist?repo=baseos-$releasever"}.Repository(context.Background(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, s.URL+"/", *repo.settings.URL)
+
+	repo, err = RepoConfig{Metalink: lists.URL + "/metalink"}.Repository(context.Background(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, s.URL+"/", *repo.settings.URL)
+	_, code, err := repo.Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 200, code)
+
+	_, err = RepoConfig{MirrorList: lists.URL + "/missing"}.Repository(context.Background(), settings)
+	assert.Error(t, err)
+	_, err = RepoConfig{ID: "empty"}.Repository(context.Background(), settings)
+	assert.ErrorContains(t, err, "no baseurl, mirrorlist or metalink")
+}
+
+func TestRepoConfigSSL(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(serveRepomdXML))
+	defer s.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caPath, caPEM, 0600))
+
+	// not trusted by the default client
+	repo, err := RepoConfig{BaseURLs: []string{s.URL}, SSLVerify: true}.Repository(context.Background(), YummySettings{})
+	require.NoError(t, err)
+	_, _, err = repo.Repomd(context.Background())
+	assert.Error(t, err)
+
+	repo, err = RepoConfig{BaseURLs: []string{s.URL}, SSLCACert: caPath, SSLVerify: true}.Repository(context.Background(), YummySettings{})
+	require.NoError(t, err)
+	_, code, err := repo.Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 200, code)
+
+	repo, err = RepoConfig{BaseURLs: []string{s.URL}, SSLVerify: false}.Repository(context.Background(), YummySettings{})
+	require.NoError(t, err)
+	_, _, err = repo.Repomd(context.Background())
+	assert.NoError(t, err)
+}
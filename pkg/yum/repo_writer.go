@@ -0,0 +1,337 @@
+package yum
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// Namespaces of the metadata documents written by RepoWriter
+const (
+	repoNamespace      = "http://linux.duke.edu/metadata/repo"
+	commonNamespace    = "http://linux.duke.edu/metadata/common"
+	rpmNamespace       = "http://linux.duke.edu/metadata/rpm"
+	filelistsNamespace = "http://linux.duke.edu/metadata/filelists"
+	otherNamespace     = "http://linux.duke.edu/metadata/other"
+)
+
+// RepoWriter generates the metadata of a repository, as createrepo does, so that repositories can be
+// built and not just read. Package locations are relative to the directory the metadata is written to.
+type RepoWriter struct {
+	Packages []Package
+	// Comps is an optional comps.xml document, published as the "group" and "group_gz" metadata
+	Comps []byte
+	// Modules is an optional modules.yaml document, published compressed as the "modules" metadata
+	Modules []byte
+	// ChecksumType is the checksum used for metadata files, sha256 if empty
+	ChecksumType string
+	// Revision of the repository, the current unix time if empty
+	Revision string
+	// SigningKey is an optional armored private key. When set, repomd.xml is signed into repomd.xml.asc
+	// and the public key is exported to repomd.xml.key.
+	SigningKey string
+	// SigningKeyPassphrase decrypts SigningKey if it is encrypted
+	SigningKeyPassphrase []byte
+}
+
+// Write writes repodata/repomd.xml and the metadata files it lists into dir, returning the repomd written.
+// Metadata files are named after their checksum, so that mirrors never serve a repomd.xml with
+// metadata from another revision.
+func (w RepoWriter) Write(dir string) (*Repomd, error) {
+	checksumType := w.ChecksumType
+	if checksumType == "" {
+		checksumType = "sha256"
+	}
+	revision := w.Revision
+	if revision == "" {
+		revision = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	repodata := filepath.Join(dir, "repodata")
+	if err := os.MkdirAll(repodata, 0755); err != nil {
+		return nil, err
+	}
+
+	primary, err := marshalMetadata(primaryDocument(w.Packages))
+	if err != nil {
+		return nil, fmt.Errorf("error writing primary.xml: %w", err)
+	}
+	filelists, err := marshalMetadata(filelistsDocument(w.Packages))
+	if err != nil {
+		return nil, fmt.Errorf("error writing filelists.xml: %w", err)
+	}
+	other, err := marshalMetadata(otherDocument(w.Packages))
+	if err != nil {
+		return nil, fmt.Errorf("error writing other.xml: %w", err)
+	}
+
+	repomd := Repomd{Revision: revision}
+	files := []metadataFile{
+		{dataType: "primary", name: "primary.xml", content: primary, compress: true},
+		{dataType: "filelists", name: "filelists.xml", content: filelists, compress: true},
+		{dataType: "other", name: "other.xml", content: other, compress: true},
+	}
+	if w.Comps != nil {
+		files = append(files,
+			metadataFile{dataType: "group", name: "comps.xml", content: w.Comps},
+			metadataFile{dataType: "group_gz", name: "comps.xml", content: w.Comps, compress: true},
+		)
+	}
+	if w.Modules != nil {
+		files = append(files, metadataFile{dataType: "modules", name: "modules.yaml", content: w.Modules, compress: true})
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	for _, file := range files {
+		data, err := writeMetadataFile(repodata, file, checksumType)
+		if err != nil {
+			return nil, fmt.Errorf("error writing %v: %w", file.name, err)
+		}
+		data.Timestamp = timestamp
+		repomd.Data = append(repomd.Data, data)
+	}
+
+	repomdXML, err := xml.MarshalIndent(repomd, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error writing repomd.xml: %w", err)
+	}
+	repomdXML = append([]byte(xml.Header), repomdXML...)
+	if err = os.WriteFile(filepath.Join(repodata, "repomd.xml"), repomdXML, 0644); err != nil {
+		return nil, err
+	}
+	repomd.RepomdString = Ptr(string(repomdXML))
+
+	if w.SigningKey != "" {
+		if err = w.sign(repodata, repomdXML); err != nil {
+			return nil, fmt.Errorf("error signing repomd.xml: %w", err)
+		}
+	}
+	return &repomd, nil
+}
+
+// sign writes the detached signature of repomd.xml to repomd.xml.asc and the public signing key to
+// repomd.xml.key
+func (w RepoWriter) sign(repodata string, repomdXML []byte) error {
+	keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(w.SigningKey))
+	if err != nil {
+		return fmt.Errorf("error reading signing key: %w", err)
+	}
+	var signer *openpgp.Entity
+	for _, entity := range keyRing {
+		if entity.PrivateKey != nil {
+			signer = entity
+			break
+		}
+	}
+	if signer == nil {
+		return fmt.Errorf("signing key has no private key")
+	}
+	if signer.PrivateKey.Encrypted {
+		if err = signer.DecryptPrivateKeys(w.SigningKeyPassphrase); err != nil {
+			return fmt.Errorf("error decrypting signing key: %w", err)
+		}
+	}
+
+	var sig bytes.Buffer
+	if err = openpgp.ArmoredDetachSign(&sig, signer, bytes.NewReader(repomdXML), nil); err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(repodata, "repomd.xml.asc"), sig.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	var publicKey bytes.Buffer
+	armorWriter, err := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return err
+	}
+	if err = signer.Serialize(armorWriter); err != nil {
+		return err
+	}
+	if err = armorWriter.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(repodata, "repomd.xml.key"), publicKey.Bytes(), 0644)
+}
+
+// metadataFile is a metadata file to write and list in repomd.xml
+type metadataFile struct {
+	dataType string
+	name     string
+	content  []byte
+	compress bool // gzip the file, appending .gz to its name
+}
+
+// writeMetadataFile writes a metadata file into repodata and returns its repomd entry
+func writeMetadataFile(repodata string, file metadataFile, checksumType string) (Data, error) {
+	data := Data{Type: file.dataType}
+	name := file.name
+	content := file.content
+
+	written := content
+	if file.compress {
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		if _, err := gz.Write(content); err != nil {
+			return data, err
+		}
+		if err := gz.Close(); err != nil {
+			return data, err
+		}
+		written = compressed.Bytes()
+		name += ".gz"
+
+		openChecksum, err := checksumOf(content, checksumType)
+		if err != nil {
+			return data, err
+		}
+		data.OpenChecksum = openChecksum
+		data.OpenSize = int64(len(content))
+	}
+
+	checksum, err := checksumOf(written, checksumType)
+	if err != nil {
+		return data, err
+	}
+	data.Checksum = checksum
+	data.Size = int64(len(written))
+
+	fileName := checksum.Value + "-" + name
+	data.Location = Location{Href: "repodata/" + fileName}
+	return data, os.WriteFile(filepath.Join(repodata, fileName), written, 0644)
+}
+
+func checksumOf(content []byte, checksumType string) (Checksum, error) {
+	hash, err := newChecksumHash(checksumType)
+	if err != nil {
+		return Checksum{}, err
+	}
+	hash.Write(content)
+	return Checksum{Type: checksumType, Value: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+func marshalMetadata(document interface{}) ([]byte, error) {
+	content, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+type primaryMetadata struct {
+	XMLName  xml.Name         `xml:"metadata"`
+	Xmlns    string           `xml:"xmlns,attr"`
+	XmlnsRpm string           `xml:"xmlns:rpm,attr"`
+	Count    int              `xml:"packages,attr"`
+	Packages []primaryPackage `xml:"package"`
+}
+
+type primaryPackage struct {
+	Type     string          `xml:"type,attr"`
+	Name     string          `xml:"name"`
+	Arch     string          `xml:"arch"`
+	Version  Version         `xml:"version"`
+	Checksum primaryChecksum `xml:"checksum"`
+	Summary  string          `xml:"summary"`
+	Size     PackageSize     `xml:"size"`
+	Location Location        `xml:"location"`
+	Format   primaryFormat   `xml:"format"`
+}
+
+type primaryFormat struct {
+	License     string        `xml:"rpm:license"`
+	SourceRPM   string        `xml:"rpm:sourcerpm"`
+	HeaderRange *HeaderRange  `xml:"rpm:header-range,omitempty"`
+	Provides    []Capability  `xml:"rpm:provides>rpm:entry,omitempty"`
+	Files       []PackageFile `xml:"file"`
+}
+
+type primaryChecksum struct {
+	Value string `xml:",chardata"`
+	Type  string `xml:"type,attr"`
+	PkgID string `xml:"pkgid,attr"`
+}
+
+func primaryDocument(packages []Package) primaryMetadata {
+	document := primaryMetadata{Xmlns: commonNamespace, XmlnsRpm: rpmNamespace, Count: len(packages), Packages: []primaryPackage{}}
+	for _, pkg := range packages {
+		pkgType := pkg.Type
+		if pkgType == "" {
+			pkgType = "rpm"
+		}
+		document.Packages = append(document.Packages, primaryPackage{
+			Type:     pkgType,
+			Name:     pkg.Name,
+			Arch:     pkg.Arch,
+			Version:  pkg.Version,
+			Checksum: primaryChecksum{Value: pkg.Checksum.Value, Type: pkg.Checksum.Type, PkgID: "YES"},
+			Summary:  pkg.Summary,
+			Size:     pkg.Size,
+			Location: pkg.Location,
+			Format: primaryFormat{
+				License:     pkg.License,
+				SourceRPM:   pkg.SourceRPM,
+				HeaderRange: pkg.HeaderRange,
+				Provides:    pkg.Provides,
+				Files:       pkg.Files,
+			},
+		})
+	}
+	return document
+}
+
+// pkgidPackage is a package entry of filelists.xml and other.xml, which only identify packages
+type pkgidPackage struct {
+	PkgID   string  `xml:"pkgid,attr"`
+	Name    string  `xml:"name,attr"`
+	Arch    string  `xml:"arch,attr"`
+	Version Version `xml:"version"`
+}
+
+type filelistsMetadata struct {
+	XMLName  xml.Name       `xml:"filelists"`
+	Xmlns    string         `xml:"xmlns,attr"`
+	Count    int            `xml:"packages,attr"`
+	Packages []PackageFiles `xml:"package"`
+}
+
+type otherMetadata struct {
+	XMLName  xml.Name       `xml:"otherdata"`
+	Xmlns    string         `xml:"xmlns,attr"`
+	Count    int            `xml:"packages,attr"`
+	Packages []pkgidPackage `xml:"package"`
+}
+
+func pkgidPackages(packages []Package) []pkgidPackage {
+	result := make([]pkgidPackage, 0, len(packages))
+	for _, pkg := range packages {
+		result = append(result, pkgidPackage{PkgID: pkg.Checksum.Value, Name: pkg.Name, Arch: pkg.Arch, Version: pkg.Version})
+	}
+	return result
+}
+
+// filelistsDocument returns the filelists of the packages, listing the Files of each package. These are the
+// files primary.xml lists, so the file lists are only complete if the packages carry all their files.
+func filelistsDocument(packages []Package) filelistsMetadata {
+	filelists := make([]PackageFiles, 0, len(packages))
+	for _, pkg := range packages {
+		filelists = append(filelists, PackageFiles{PkgID: pkg.Checksum.Value, Name: pkg.Name, Arch: pkg.Arch, Version: pkg.Version, Files: pkg.Files})
+	}
+	return filelistsMetadata{Xmlns: filelistsNamespace, Count: len(packages), Packages: filelists}
+}
+
+// otherDocument returns the other metadata of the packages. Package does not carry changelogs, so the
+// packages are listed without changelogs.
+func otherDocument(packages []Package) otherMetadata {
+	return otherMetadata{Xmlns: otherNamespace, Count: len(packages), Packages: pkgidPackages(packages)}
+}
@@ -0,0 +1,92 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoWriter(t *testing.T) {
+	dir := t.TempDir()
+	packages := []Package{{
+		Type:        "rpm",
+		Name:        "foo",
+		Arch:        "x86_64",
+		Version:     Version{Version: "1.0", Release: "1", Epoch: 0},
+		Checksum:    Checksum{Type: "sha256", Value: "abc"},
+		Summary:     "Foo tool",
+		Size:        PackageSize{Package: 10},
+		Location:    Location{Href: "Packages/foo-1.0-1.x86_64.rpm"},
+		License:     "MIT",
+		SourceRPM:   "foo-1.0-1.src.rpm",
+		HeaderRange: &HeaderRange{Start: 4504, End: 9268},
+		Provides:    []Capability{{Name: "foo", Flags: "EQ", Version: "1.0", Release: "1"}, {Name: "libfoo.so.1()(64bit)"}},
+		Files:       []PackageFile{{Path: "/usr/bin/foo"}, {Path: "/etc/foo", Type: "dir"}},
+	}}
+
+	repomd, err := RepoWriter{Packages: packages, Comps: compsXML, Revision: "42"}.Write(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "42", repomd.Revision)
+	assert.Len(t, repomd.Data, 5)
+
+	s := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer s.Close()
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+
+	// the written metadata can be read back and matches repomd.xml
+	report := r.Verify(context.Background(), nil)
+	assert.True(t, report.OK(), report.Err())
+
+	read, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, packages, read)
+
+	filelists, _, err := r.Filelists(context.Background())
+	require.NoError(t, err)
+	require.Len(t, filelists, 1)
+	assert.Equal(t, "abc", filelists[0].PkgID)
+	assert.Equal(t, packages[0].Files, filelists[0].Files)
+
+	groups, _, err := r.PackageGroups(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, groups)
+
+	fetched, _, err := r.Repomd(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, repomd.Data, fetched.Data)
+}
+
+func TestRepoWriterSigning(t *testing.T) {
+	entity, err := openpgp.NewEntity("yummy", "test", "yummy@example.com", nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.PrivateKey.Encrypt([]byte("secret")))
+	var privateKey bytes.Buffer
+	armorWriter, err := armor.Encode(&privateKey, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivateWithoutSigning(armorWriter, nil))
+	require.NoError(t, armorWriter.Close())
+
+	dir := t.TempDir()
+	_, err = RepoWriter{SigningKey: privateKey.String(), SigningKeyPassphrase: []byte("secret")}.Write(dir)
+	require.NoError(t, err)
+
+	s := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer s.Close()
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+
+	// the exported key verifies the signature
+	gpgKey, _, err := r.GPGKey(context.Background())
+	require.NoError(t, err)
+	report := r.Verify(context.Background(), gpgKey)
+	assert.True(t, report.OK(), report.Err())
+
+	_, err = RepoWriter{SigningKey: privateKey.String()}.Write(t.TempDir())
+	assert.Error(t, err)
+}
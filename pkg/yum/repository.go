@@ -2,92 +2,312 @@ package yum
 
 import (
 	"bufio"
-	"compress/gzip"
+	"cmp"
 	"context"
+	"crypto/tls"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"path"
-
-	"github.com/h2non/filetype"
-	"github.com/h2non/filetype/matchers"
-	"github.com/klauspost/compress/zstd"
-	"github.com/ulikunitz/xz"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 // Max uncompressed XML file supported
 const DefaultMaxXmlSize = int64(512 * 1024 * 1024) // 512 MB
 
+// TruncatedError is returned, with the packages decoded so far, when a primary.xml or filelists.xml has more
+// packages than YummySettings.MaxPackages
+type TruncatedError struct {
+	Limit int // Number of packages returned
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("truncated to the first %d packages", e.Limit)
+}
+
 // Package metadata of a given package
 type Package struct {
-	Type     string   `xml:"type,attr"`
-	Name     string   `xml:"name"`
-	Arch     string   `xml:"arch"`
-	Version  Version  `xml:"version"`
-	Checksum Checksum `xml:"checksum"`
-	Summary  string   `xml:"summary"`
+	Type      string      `xml:"type,attr" json:"type"`
+	Name      string      `xml:"name" json:"name"`
+	Arch      string      `xml:"arch" json:"arch"`
+	Version   Version     `xml:"version" json:"version"`
+	Checksum  Checksum    `xml:"checksum" json:"checksum"`
+	Summary   string      `xml:"summary" json:"summary"`
+	Size      PackageSize `xml:"size" json:"size"`
+	Location  Location    `xml:"location" json:"location"`
+	License   string      `xml:"format>license" json:"license"`
+	SourceRPM string      `xml:"format>sourcerpm" json:"sourceRpm"` // empty for source packages
+	// HeaderRange is the byte range of the main header in the RPM file, nil if primary.xml does not list it
+	HeaderRange *HeaderRange `xml:"format>header-range" json:"headerRange,omitempty"`
+	// Provides lists the capabilities the package provides
+	Provides []Capability `xml:"format>provides>entry" json:"provides,omitempty"`
+	// Files lists the files of the package that primary.xml includes, usually only those in bin and etc
+	// directories. Filelists lists all of them.
+	Files []PackageFile `xml:"format>file" json:"files,omitempty"`
+}
+
+// HeaderRange is the byte range of the main header of an RPM file, as in the header-range of primary.xml.
+// The bytes 0 to End of the file hold its lead, signature and main header, which is enough for ReadRPMHeader
+// to check the signature without fetching the whole package.
+type HeaderRange struct {
+	Start int64 `xml:"start,attr" json:"start"`
+	End   int64 `xml:"end,attr" json:"end"`
+}
+
+// PackageSize holds the sizes of a package in bytes: of the RPM file, of its payload archive
+// and once installed
+type PackageSize struct {
+	Package   int64 `xml:"package,attr" json:"package"`
+	Installed int64 `xml:"installed,attr" json:"installed"`
+	Archive   int64 `xml:"archive,attr" json:"archive"`
 }
 
 type Version struct {
-	Version string `xml:"ver,attr"`
-	Release string `xml:"rel,attr"`
-	Epoch   int32  `xml:"epoch,attr"`
+	Version string `xml:"ver,attr" json:"version"`
+	Release string `xml:"rel,attr" json:"release"`
+	Epoch   int32  `xml:"epoch,attr" json:"epoch"`
 }
 
 type Checksum struct {
-	Value string `xml:",chardata"`
-	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata" json:"value"`
+	Type  string `xml:"type,attr" json:"type"`
 }
 
 // Repomd metadata of the repomd of a repository
 type Repomd struct {
-	XMLName      xml.Name `xml:"repomd"`
-	Data         []Data   `xml:"data"`
-	Revision     string   `xml:"revision"`
-	RepomdString *string  `xml:"-"`
+	XMLName      xml.Name `xml:"repomd" json:"-"`
+	Data         []Data   `xml:"data" json:"data,omitempty"`
+	Revision     string   `xml:"revision" json:"revision"` // Raw revision, see RevisionTime and CompareRevisions
+	RepomdString *string  `xml:"-" json:"-"`
 }
 
 type Data struct {
-	Type     string   `xml:"type,attr"`
-	Location Location `xml:"location"`
+	Type         string   `xml:"type,attr" json:"type"`
+	Location     Location `xml:"location" json:"location"`
+	Checksum     Checksum `xml:"checksum" json:"checksum"`
+	OpenChecksum Checksum `xml:"open-checksum" json:"openChecksum"`
+	Size         int64    `xml:"size" json:"size"`
+	OpenSize     int64    `xml:"open-size" json:"openSize"`
+	Timestamp    string   `xml:"timestamp" json:"timestamp"` // Unix time the file was generated, see Time
+	Base         string   `xml:"http://www.w3.org/XML/1998/namespace base,attr,omitempty" json:"base,omitempty"`
 }
 
 type Location struct {
-	Href string `xml:"href,attr"`
+	Href string `xml:"href,attr" json:"href"`
+	// Base is the xml:base of the location, or of the data element of repomd.xml holding it. Href is
+	// relative to Base instead of the repository URL if it is set.
+	Base string `xml:"http://www.w3.org/XML/1998/namespace base,attr,omitempty" json:"base,omitempty"`
+}
+
+// PackageSummary is a cheap overview of a repository's size, see Repository.PackageSummary
+type PackageSummary struct {
+	PackageCount int   `json:"packageCount"` // Number of packages advertised by primary.xml
+	MetadataSize int64 `json:"metadataSize"` // Total size of all metadata files listed in repomd.xml
+}
+
+// HTTPDoer sends HTTP requests, as *http.Client does. Any implementation can be set as the client of a
+// repository, such as a client wrapped with instrumentation, a recorder or a fake.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// isNilClient reports whether client is nil, including a nil *http.Client
+func isNilClient(client HTTPDoer) bool {
+	c, ok := client.(*http.Client)
+	return client == nil || ok && c == nil
 }
 
 type YummySettings struct {
-	Client     *http.Client
+	// Client sends the requests of the repository. Defaults to http.DefaultClient when nil. DisableProxy and
+	// the SSL options of a RepoConfig need an *http.Client, to configure its transport.
+	Client     HTTPDoer
 	URL        *string
 	MaxXmlSize *int64
+	// EnabledModuleStreams enables modular filtering of Packages() when not nil. It maps module names
+	// to their enabled stream; modular packages of any other stream are hidden, as dnf does.
+	EnabledModuleStreams map[string]string
+	// GPGKeys is a keyring of armored keys trusted to sign repomd.xml. Verify accepts a signature
+	// made by any of them.
+	GPGKeys []string
+	// GPGKeyPaths lists the paths, relative to the repository URL, that GPGKey tries in order.
+	// Defaults to DefaultGPGKeyPaths when nil.
+	GPGKeyPaths []string
+	// MetricsRegisterer enables Prometheus metrics of fetches, downloaded and decompressed bytes, parsed
+	// items, cache hits and HTTP status codes when not nil. Repositories can share a registerer.
+	MetricsRegisterer prometheus.Registerer
+	// TracerProvider provides the tracer of the OpenTelemetry spans of fetching and parsing metadata.
+	// Defaults to the global tracer provider when nil.
+	TracerProvider trace.TracerProvider
+	// Logger logs fetches at debug level, failed fetches and retries at warn or info level, cache hits at debug
+	// level and parse warnings at warn level. The level logged is configured by its handler. Nothing is
+	// logged when nil.
+	Logger *slog.Logger
+	// Vars are the values of the yum variables in URL, such as "releasever" and "basearch" for
+	// $releasever and $basearch, see SubstituteVars.
+	Vars map[string]string
+	// Netrc enables basic auth credentials from a netrc file for requests that carry none, neither in the URL
+	// nor in an Authorization header, as curl and dnf do for private mirrors.
+	Netrc bool
+	// NetrcPath is the netrc file read when Netrc is enabled. Defaults to DefaultNetrcPath when empty.
+	NetrcPath string
+	// DisableProxy connects to the repository directly, ignoring HTTP_PROXY, HTTPS_PROXY and any other proxy
+	// of the client's transport, for mirrors that must be reached without the proxy of the process. The
+	// client is copied with a copy of its transport, which must then be an *http.Transport.
+	DisableProxy bool
+	// CircuitBreaker, when not nil, refuses requests with ErrCircuitOpen after repeated failures, see
+	// NewCircuitBreaker
+	CircuitBreaker *CircuitBreaker
+	// RateLimiter, when not nil, limits the rate of all requests of the repository, waiting for it to allow
+	// each request. Repositories given the same limiter share its rate, see HostRateLimiters to share it by host.
+	RateLimiter *rate.Limiter
+	// HostSemaphore, when not nil, caps the number of concurrent requests to each host, waiting for a slot
+	// before each request and releasing it when the response body is closed, see NewHostSemaphore
+	HostSemaphore *HostSemaphore
+	// MaxPackages, when positive, caps the number of packages decoded from primary.xml and filelists.xml.
+	// Packages and Filelists return the first MaxPackages packages with a *TruncatedError if the repository
+	// has more.
+	MaxPackages int
+	// SignURL, when not nil, is called with a copy of the URL of every request before it is sent, including
+	// the URLs of metadata and packages found in repomd.xml, to sign it for CDNs that authenticate each path
+	// with a token. Logs and spans show the URL before it is signed.
+	SignURL func(ctx context.Context, u *url.URL) error
+	// DialContext, when not nil, opens the connections of the repository, for instance to a local proxy over a
+	// unix socket. The client is copied with a copy of its transport, as for DisableProxy.
+	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+	// Resolver, when not nil, resolves the host names of the repository, for split-horizon DNS. It is used by
+	// the default dialer, and ignored when DialContext is set.
+	Resolver *net.Resolver
+	// TLSConfig, when not nil, replaces the TLS configuration of the client's transport, to trust other CAs or
+	// present a client certificate
+	TLSConfig *tls.Config
+	// MaxIdleConnsPerHost, when positive, is the number of idle connections the client's transport keeps open
+	// to each host, 2 by default, so that concurrent fetches reuse connections rather than opening new ones.
+	// The client is copied with a copy of its transport, as for DisableProxy; see NewHTTPClient to share one
+	// pool of connections across many repositories.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout, when positive, is how long idle connections are kept open, 90 seconds by default
+	IdleConnTimeout time.Duration
+	// ForceHTTP2 negotiates HTTP/2 with the servers that support it even with a custom DialContext or
+	// TLSConfig, which otherwise disable it, so that requests to a host share one connection
+	ForceHTTP2 bool
+	// ChecksumPolicy flags or rejects metadata files and packages with md5 or sha1 checksums, for
+	// FIPS-compliant environments. Defaults to ChecksumPolicyAllow.
+	ChecksumPolicy ChecksumPolicy
+}
+
+// DefaultGPGKeyPaths are the conventional locations of a repository's signing key
+var DefaultGPGKeyPaths = []string{"repodata/repomd.xml.key"}
+
+// LocalizedString is a comps element that may be translated, such as a group name or description.
+// Value holds the untranslated text and Translations the localized variants keyed by locale.
+type LocalizedString struct {
+	Value        string            `json:"value"`
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// ForLocale returns the translation for locale, falling back to the language without its
+// territory (e.g. "pt" for "pt_BR") and then to the untranslated value.
+func (ls LocalizedString) ForLocale(locale string) string {
+	if t, ok := forLocale(ls.Translations, locale); ok {
+		return t
+	}
+	return ls.Value
+}
+
+func (ls LocalizedString) String() string {
+	return ls.Value
 }
 
 type PackageGroup struct {
-	ID          string                  `xml:"id"`
-	Name        PackageGroupName        `xml:"name"`
-	Description PackageGroupDescription `xml:"description"`
-	PackageList []string                `xml:"packagelist>packagereq"`
+	ID           string                  `xml:"id" json:"id"`
+	Name         PackageGroupName        `xml:"name" json:"name"`
+	Description  PackageGroupDescription `xml:"description" json:"description"`
+	Default      bool                    `xml:"default" json:"default"`         // Whether the group is selected by default
+	UserVisible  bool                    `xml:"uservisible" json:"userVisible"` // Whether the group should be shown to users, true if unset
+	DisplayOrder int                     `xml:"display_order,omitempty" json:"displayOrder"`
+	BiarchOnly   bool                    `xml:"biarchonly,omitempty" json:"biarchOnly"`
+	PackageList  []PackageReq            `xml:"packagelist>packagereq" json:"packageList,omitempty"`
+}
+
+// Types of a PackageReq
+const (
+	PackageReqMandatory   = "mandatory"
+	PackageReqDefault     = "default"
+	PackageReqOptional    = "optional"
+	PackageReqConditional = "conditional"
+)
+
+// PackageReq is a package listed in a comps group. Type is one of "mandatory", "default", "optional"
+// or "conditional"; conditional packages are only installed if the package named by Requires is.
+type PackageReq struct {
+	Name     string `xml:",chardata" json:"name"`
+	Type     string `xml:"type,attr" json:"type"`
+	Requires string `xml:"requires,attr,omitempty" json:"requires"`
 }
 
-type PackageGroupName string
+type PackageGroupName = LocalizedString
 
-type PackageGroupDescription string
+type PackageGroupDescription = LocalizedString
 
 type Environment struct {
-	ID          string                 `xml:"id"`
-	Name        EnvironmentName        `xml:"name"`
-	Description EnvironmentDescription `xml:"description"`
+	ID           string                 `xml:"id" json:"id"`
+	Name         EnvironmentName        `xml:"name" json:"name"`
+	Description  EnvironmentDescription `xml:"description" json:"description"`
+	DisplayOrder int                    `xml:"display_order,omitempty" json:"displayOrder"`
+	GroupList    []string               `xml:"grouplist>groupid" json:"groupList,omitempty"`
+	OptionList   []EnvironmentOption    `xml:"optionlist>groupid" json:"optionList,omitempty"`
 }
 
-type EnvironmentName string
+// EnvironmentOption is an optional group of an environment, selected by default if Default is set
+type EnvironmentOption struct {
+	GroupID string `xml:",chardata" json:"groupId"`
+	Default bool   `xml:"default,attr,omitempty" json:"default"`
+}
 
-type EnvironmentDescription string
+type EnvironmentName = LocalizedString
+
+type EnvironmentDescription = LocalizedString
+
+type Category struct {
+	ID           string              `xml:"id" json:"id"`
+	Name         CategoryName        `xml:"name" json:"name"`
+	Description  CategoryDescription `xml:"description" json:"description"`
+	DisplayOrder int                 `xml:"display_order,omitempty" json:"displayOrder"`
+	GroupList    []string            `xml:"grouplist>groupid" json:"groupList,omitempty"`
+}
+
+type CategoryName = LocalizedString
+
+type CategoryDescription = LocalizedString
+
+// Langpack maps a package to the pattern of its language packs, where %s stands for the language code
+type Langpack struct {
+	Name    string `xml:"name,attr" json:"name"`
+	Install string `xml:"install,attr" json:"install"`
+}
+
+// PackageForLang returns the name of the language pack package for lang, e.g. "firefox-langpack-de"
+func (l Langpack) PackageForLang(lang string) string {
+	return strings.ReplaceAll(l.Install, "%s", lang)
+}
 
 type Comps struct {
-	PackageGroups []PackageGroup
-	Environments  []Environment
+	PackageGroups []PackageGroup `json:"packageGroups,omitempty"`
+	Environments  []Environment  `json:"environments,omitempty"`
+	Categories    []Category     `json:"categories,omitempty"`
+	Langpacks     []Langpack     `json:"langpacks,omitempty"`
 }
 
 //go:generate mockery --name YumRepository --filename yum_repository_mock.go --inpackage
@@ -96,24 +316,74 @@ type YumRepository interface {
 	Packages(ctx context.Context) (packages []Package, statusCode int, err error)
 	Repomd(ctx context.Context) (repomd *Repomd, statusCode int, err error)
 	Signature(ctx context.Context) (repomdSignature *string, statusCode int, err error)
+	GPGKey(ctx context.Context) (gpgKey *string, statusCode int, err error)
 	ModuleMDs(ctx context.Context) ([]ModuleMD, int, error)
+	ModuleStreams(ctx context.Context) ([]ModuleStream, int, error)
+	ModuleTranslations(ctx context.Context) (ModuleTranslations, int, error)
 	Comps(ctx context.Context) (comps *Comps, statusCode int, err error)
 	PackageGroups(ctx context.Context) (packageGroups []PackageGroup, statusCode int, err error)
 	Environments(ctx context.Context) (environments []Environment, statusCode int, err error)
+	Categories(ctx context.Context) (categories []Category, statusCode int, err error)
+	ResolveGroup(ctx context.Context, groupID string, includeOptional bool) (packages []Package, statusCode int, err error)
+	ResolveEnvironment(ctx context.Context, envID string) (resolution *EnvironmentResolution, statusCode int, err error)
+	ResolveModuleProfile(ctx context.Context, module, stream, profile string) (packages []Package, statusCode int, err error)
+	Advisories(ctx context.Context, opts ...AdvisoryOption) (advisories []Advisory, statusCode int, err error)
+	AdvisoriesForPackage(ctx context.Context, nevra NEVRA) (advisories []Advisory, statusCode int, err error)
+	PatchStatus(ctx context.Context, installed []NEVRA) (report *PatchReport, statusCode int, err error)
+	WhatProvides(ctx context.Context, capability string) (packages []Package, statusCode int, err error)
+	DeltaPackages(ctx context.Context) (deltaPackages []DeltaPackage, statusCode int, err error)
+	Applications(ctx context.Context) (applications []Application, statusCode int, err error)
+	DownloadPackage(ctx context.Context, pkg Package, dest io.Writer) (written int64, statusCode int, err error)
+	DownloadPackageToFile(ctx context.Context, pkg Package, path string) (written int64, statusCode int, err error)
+	Sync(ctx context.Context, dir string, opts SyncOptions) (result SyncResult, err error)
+	MirrorMetadata(ctx context.Context, dir string) (result SyncResult, err error)
+	CloneTo(ctx context.Context, dir string, opts SyncOptions) (report CloneReport, err error)
+	Export(ctx context.Context, w io.Writer) error
+	Treeinfo(ctx context.Context) (treeinfo *Treeinfo, statusCode int, err error)
+	Products(ctx context.Context) (products []Product, statusCode int, err error)
+	Verify(ctx context.Context, gpgKey *string) VerifyReport
+	PackageSummary(ctx context.Context) (summary *PackageSummary, statusCode int, err error)
+	Filelists(ctx context.Context) (filelists []PackageFiles, statusCode int, err error)
+	FetchAll(ctx context.Context) (results map[string]FetchResult, err error)
+	SpillPackages(ctx context.Context, dir string) (spilled *SpilledPackages, statusCode int, err error)
+	Stats() Stats
+	ResponseHeaders(metadataType string) (headers ResponseHeaders, ok bool)
+	Checksums(metadataType string) (checksums MetadataChecksums, ok bool)
+	Probe(ctx context.Context) (exists bool, statusCode int, err error)
+	IsStale(ctx context.Context, maxAge time.Duration) (stale bool, statusCode int, err error)
+	WeakChecksums(ctx context.Context) (weak []WeakChecksum, statusCode int, err error)
 	Clear()
 }
 
 type Repository struct {
-	settings        YummySettings
-	packages        []Package  // Packages repository contains
-	repomdSignature *string    // Signature of the repository
-	repomd          *Repomd    // Repomd of the repository
-	comps           *Comps     // Comps of the repository
-	moduleMDs       []ModuleMD // Module md documents of the repository, used to compute moduleStreams
+	settings           YummySettings
+	packages           []Package          // Packages repository contains
+	packagesTruncated  error              // *TruncatedError if packages were truncated to MaxPackages
+	packageCount       *int               // Package count advertised by primary.xml, see PackageSummary
+	repomdSignature    *string            // Signature of the repository
+	gpgKey             *string            // Signing key published with the repository
+	repomd             *Repomd            // Repomd of the repository
+	comps              *Comps             // Comps of the repository
+	moduleMDs          []ModuleMD         // Module md documents of the repository, used to compute moduleStreams
+	moduleTranslations ModuleTranslations // Module md translation documents of the repository
+	advisories         []Advisory         // Advisories from the updateinfo of the repository
+	advisoryIndex      map[NEVRA][]int    // Indexes of advisories by the NEVRAs of their packages
+	providesIndex      map[string][]int   // Indexes of packages by the capabilities and files they provide
+	deltaPackages      []DeltaPackage     // Delta RPMs from the prestodelta of the repository
+	applications       []Application      // Applications from the AppStream metadata of the repository
+	treeinfo           *Treeinfo          // Treeinfo of the installable tree at the repository URL
+	products           []Product          // Products from the productid certificate of the repository
+	filelists          []PackageFiles     // File lists of the packages of the repository
+	filelistsTruncated error              // *TruncatedError if file lists were truncated to MaxPackages
+	metrics            *metrics           // Collectors of the MetricsRegisterer, nil if metrics are disabled
+	stats              *stats             // Statistics of the requests, see Stats
+	headers            *responseHeaders   // Headers of the last successful responses, see ResponseHeaders
+	checksums          *streamChecksums   // Checksums of the last fetched files, see Checksums
+	netrcEntries       []netrcEntry       // Entries of the netrc file, nil until read
 }
 
 func NewRepository(settings YummySettings) (Repository, error) {
-	if settings.Client == nil {
+	if isNilClient(settings.Client) {
 		settings.Client = http.DefaultClient
 	}
 	if settings.URL == nil {
@@ -122,19 +392,118 @@ func NewRepository(settings YummySettings) (Repository, error) {
 	if settings.MaxXmlSize == nil {
 		settings.MaxXmlSize = Ptr(DefaultMaxXmlSize)
 	}
-	return Repository{settings: settings}, nil
+	client, err := configureClient(settings.Client, settings)
+	if err != nil {
+		return Repository{}, err
+	}
+	settings.Client = client
+	repo := Repository{settings: settings, stats: newStats(), headers: newResponseHeaders(), checksums: newStreamChecksums()}
+	if settings.MetricsRegisterer != nil {
+		m, err := newMetrics(settings.MetricsRegisterer)
+		if err != nil {
+			return Repository{}, fmt.Errorf("error registering metrics: %w", err)
+		}
+		repo.metrics = m
+	}
+	return repo, nil
 }
 
 func (r *Repository) Configure(settings YummySettings) {
-	if settings.Client != nil {
+	if !isNilClient(settings.Client) {
 		r.settings.Client = settings.Client
 	}
-	if r.settings.Client == nil {
+	if isNilClient(r.settings.Client) {
 		r.settings.Client = http.DefaultClient
 	}
 	if settings.URL != nil {
 		r.settings.URL = settings.URL
 	}
+	if settings.EnabledModuleStreams != nil {
+		r.settings.EnabledModuleStreams = settings.EnabledModuleStreams
+		r.providesIndex = nil
+	}
+	if settings.GPGKeys != nil {
+		r.settings.GPGKeys = settings.GPGKeys
+	}
+	if settings.GPGKeyPaths != nil {
+		r.settings.GPGKeyPaths = settings.GPGKeyPaths
+	}
+	if settings.MetricsRegisterer != nil {
+		// Configure cannot report errors, metrics stay disabled if they cannot be registered
+		if m, err := newMetrics(settings.MetricsRegisterer); err == nil {
+			r.settings.MetricsRegisterer = settings.MetricsRegisterer
+			r.metrics = m
+		}
+	}
+	if settings.TracerProvider != nil {
+		r.settings.TracerProvider = settings.TracerProvider
+	}
+	if settings.Logger != nil {
+		r.settings.Logger = settings.Logger
+	}
+	if settings.Vars != nil {
+		r.settings.Vars = settings.Vars
+	}
+	if settings.Netrc {
+		r.settings.Netrc = true
+		r.netrcEntries = nil
+	}
+	if settings.NetrcPath != "" {
+		r.settings.NetrcPath = settings.NetrcPath
+		r.netrcEntries = nil
+	}
+	if settings.CircuitBreaker != nil {
+		r.settings.CircuitBreaker = settings.CircuitBreaker
+	}
+	if settings.MaxPackages != 0 {
+		r.settings.MaxPackages = settings.MaxPackages
+	}
+	if settings.RateLimiter != nil {
+		r.settings.RateLimiter = settings.RateLimiter
+	}
+	if settings.HostSemaphore != nil {
+		r.settings.HostSemaphore = settings.HostSemaphore
+	}
+	if settings.SignURL != nil {
+		r.settings.SignURL = settings.SignURL
+	}
+	if settings.ChecksumPolicy != ChecksumPolicyAllow {
+		r.settings.ChecksumPolicy = settings.ChecksumPolicy
+	}
+	if settings.DisableProxy {
+		r.settings.DisableProxy = true
+	}
+	if r.settings.DisableProxy && (settings.DisableProxy || !isNilClient(settings.Client)) {
+		// Configure cannot report errors, the client is kept as is if its transport cannot be configured
+		if client, err := directClient(r.settings.Client); err == nil {
+			r.settings.Client = client
+		}
+	}
+	if settings.DialContext != nil {
+		r.settings.DialContext = settings.DialContext
+	}
+	if settings.Resolver != nil {
+		r.settings.Resolver = settings.Resolver
+	}
+	if settings.TLSConfig != nil {
+		r.settings.TLSConfig = settings.TLSConfig
+	}
+	if settings.MaxIdleConnsPerHost > 0 {
+		r.settings.MaxIdleConnsPerHost = settings.MaxIdleConnsPerHost
+	}
+	if settings.IdleConnTimeout > 0 {
+		r.settings.IdleConnTimeout = settings.IdleConnTimeout
+	}
+	if settings.ForceHTTP2 {
+		r.settings.ForceHTTP2 = true
+	}
+	if settings.DialContext != nil || settings.Resolver != nil || settings.TLSConfig != nil || settings.MaxIdleConnsPerHost > 0 ||
+		settings.IdleConnTimeout > 0 || settings.ForceHTTP2 || !isNilClient(settings.Client) {
+		// Configure cannot report errors, the client is kept as is if its transport cannot be configured
+		if client, err := dialerClient(r.settings.Client, r.settings); err == nil {
+			r.settings.Client = client
+		}
+	}
 	r.Clear()
 }
 
@@ -142,19 +511,40 @@ func (r *Repository) Configure(settings YummySettings) {
 func (r *Repository) Clear() {
 	r.repomd = nil
 	r.packages = nil
+	r.packagesTruncated = nil
+	r.packageCount = nil
 	r.repomdSignature = nil
+	r.gpgKey = nil
 	r.comps = nil
+	r.moduleMDs = nil
+	r.moduleTranslations = nil
+	r.advisories = nil
+	r.advisoryIndex = nil
+	r.providesIndex = nil
+	r.deltaPackages = nil
+	r.applications = nil
+	r.treeinfo = nil
+	r.products = nil
+	r.filelists = nil
+	r.filelistsTruncated = nil
 }
 
 // Repomd populates r.Repomd with repository's repomd.xml metadata. Returns Repomd, response code, and error.
 // If the repomd was successfully fetched previously, will return cached repomd.
 func (r *Repository) Repomd(ctx context.Context) (*Repomd, int, error) {
+	return traced(ctx, r, "Repomd", r.fetchRepomd)
+}
+
+// fetchRepomd fetches and caches the repomd.xml of the repository
+func (r *Repository) fetchRepomd(ctx context.Context) (*Repomd, int, error) {
 	var result Repomd
 	var err error
 	var resp *http.Response
 	var repomdURL string
 
+	r.cacheRequest(ctx, "repomd", r.repomd != nil)
 	if r.repomd != nil {
+		spanCacheHit(ctx)
 		return r.repomd, 0, nil
 	}
 	if repomdURL, err = r.getRepomdURL(); err != nil {
@@ -166,7 +556,7 @@ func (r *Repository) Repomd(ctx context.Context) (*Repomd, int, error) {
 		return nil, 0, fmt.Errorf("error creating request: %w", err)
 	}
 
-	if resp, err = r.settings.Client.Do(req); err != nil {
+	if resp, err = r.do(req, "repomd"); err != nil {
 		return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", repomdURL, err)
 	}
 	defer resp.Body.Close()
@@ -174,14 +564,65 @@ func (r *Repository) Repomd(ctx context.Context) (*Repomd, int, error) {
 	if resp.StatusCode != http.StatusOK {
 		return nil, resp.StatusCode, fmt.Errorf("Cannot fetch %v: %v", repomdURL, resp.StatusCode)
 	}
-	if result, err = ParseRepomdXML(resp.Body); err != nil {
+	if result, err = parseRepomdXML(resp.Body, *r.settings.MaxXmlSize, true); err != nil {
 		return nil, resp.StatusCode, fmt.Errorf("Error parsing repomd.xml: %w", err)
 	}
+	if err = r.enforceChecksumPolicy(ctx, "repomd", weakRepomdChecksums(result)); err != nil {
+		return nil, resp.StatusCode, err
+	}
 
+	spanItems(ctx, len(result.Data))
 	r.repomd = &result
 	return r.repomd, resp.StatusCode, nil
 }
 
+// do sends a request for a file of the given metadata type, such as "primary", with netrc credentials if
+// enabled, once the rate limiter allows it and the host semaphore has a slot for it, and unless the circuit
+// breaker is open. The request is reported to the observers, traced in a span and logged.
+func (r *Repository) do(req *http.Request, metadataType string) (*http.Response, error) {
+	signed, err := r.signRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.setNetrcAuth(signed); err != nil {
+		return nil, err
+	}
+	if limiter := r.settings.RateLimiter; limiter != nil {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("error waiting for rate limiter: %w", err)
+		}
+	}
+	release := func() {}
+	if semaphore := r.settings.HostSemaphore; semaphore != nil {
+		if release, err = semaphore.acquire(req.Context(), signed.URL.Host); err != nil {
+			return nil, fmt.Errorf("error waiting for host semaphore: %w", err)
+		}
+	}
+	if breaker := r.settings.CircuitBreaker; breaker != nil {
+		if err := breaker.allow(); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	span := r.startRequestSpan(req, metadataType)
+	start := time.Now()
+	resp, err := r.settings.Client.Do(signed)
+	if breaker := r.settings.CircuitBreaker; breaker != nil {
+		breaker.record(resp, err)
+	}
+	r.observers().observeResponse(metadataType, start, resp, err)
+	r.headers.record(metadataType, resp, err)
+	r.checksums.observeResponse(metadataType, resp, err)
+	endRequestSpan(span, resp, err)
+	r.logResponse(req, metadataType, time.Since(start), resp, err)
+	if resp != nil && resp.Body != nil {
+		resp.Body = releasingBody{ReadCloser: resp.Body, release: release}
+	} else {
+		release()
+	}
+	return resp, err
+}
+
 func erroredStatusCode(response *http.Response) int {
 	if response == nil {
 		return 0
@@ -191,12 +632,19 @@ func erroredStatusCode(response *http.Response) int {
 }
 
 func (r *Repository) Comps(ctx context.Context) (*Comps, int, error) {
+	return traced(ctx, r, "Comps", r.fetchComps)
+}
+
+// fetchComps fetches and caches the comps.xml of the repository
+func (r *Repository) fetchComps(ctx context.Context) (*Comps, int, error) {
 	var err error
 	var compsURL *string
 	var resp *http.Response
 	var comps Comps
 
+	r.cacheRequest(ctx, "group", r.comps != nil)
 	if r.comps != nil {
+		spanCacheHit(ctx)
 		return r.comps, 200, nil
 	}
 
@@ -214,15 +662,17 @@ func (r *Repository) Comps(ctx context.Context) (*Comps, int, error) {
 			return nil, 0, fmt.Errorf("error creating request: %w", err)
 		}
 
-		if resp, err = r.settings.Client.Do(req); err != nil {
+		if resp, err = r.do(req, "group"); err != nil {
 			return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", compsURL, err)
 		}
 
 		defer resp.Body.Close()
 
-		if comps, err = ParseCompsXML(resp.Body, compsURL); err != nil {
+		if comps, err = parseCompsXML(r.decompressedBody(resp, "group"), *r.settings.MaxXmlSize); err != nil {
 			return nil, resp.StatusCode, fmt.Errorf("error parsing comps.xml: %w", err)
 		}
+		r.parsed("group", len(comps.PackageGroups)+len(comps.Environments)+len(comps.Categories))
+		spanItems(ctx, len(comps.PackageGroups)+len(comps.Environments)+len(comps.Categories))
 
 		r.comps = &comps
 
@@ -234,25 +684,129 @@ func (r *Repository) Comps(ctx context.Context) (*Comps, int, error) {
 
 // Packages populates r.Packages with metadata of each package in repository. Returns response code and error.
 // If the packages were successfully fetched previously, will return cached packages.
+// If EnabledModuleStreams is set, modular packages of streams that are not enabled are filtered out.
 func (r *Repository) Packages(ctx context.Context) ([]Package, int, error) {
+	return traced(ctx, r, "Packages", r.fetchFilteredPackages)
+}
+
+// fetchFilteredPackages fetches and caches all packages of the repository, filtering out the modular
+// packages of streams that are not enabled
+func (r *Repository) fetchFilteredPackages(ctx context.Context) ([]Package, int, error) {
+	packages, code, err := r.fetchPackages(ctx)
+	var truncated *TruncatedError
+	if (err != nil && !errors.As(err, &truncated)) || r.settings.EnabledModuleStreams == nil {
+		return packages, code, err
+	}
+
+	moduleMDs, _, moduleErr := r.ModuleMDs(ctx)
+	if moduleErr != nil {
+		return nil, code, fmt.Errorf("error getting module streams for modular filtering: %w", moduleErr)
+	}
+	return FilterModularPackages(packages, moduleMDs, r.settings.EnabledModuleStreams), code, err
+}
+
+// fetchPackages fetches and caches all packages of the repository, without modular filtering
+func (r *Repository) fetchPackages(ctx context.Context) ([]Package, int, error) {
+	var packages []Package
+
+	r.cacheRequest(ctx, "primary", r.packages != nil)
+	if r.packages != nil {
+		spanCacheHit(ctx)
+		return r.packages, 0, r.packagesTruncated
+	}
+
+	reader, resp, err := r.getPrimary(ctx)
+	if err != nil {
+		return nil, erroredStatusCode(resp), err
+	}
+	defer resp.Body.Close()
+
+	var truncated *TruncatedError
+	if packages, err = parsePackagesXML(reader, *r.settings.MaxXmlSize, r.settings.MaxPackages); err != nil {
+		if !errors.As(err, &truncated) {
+			return nil, resp.StatusCode, err
+		}
+		r.logger().WarnContext(ctx, "truncated packages", "url", redactURL(r.repositoryURL()), "limit", truncated.Limit)
+	}
+	if policyErr := r.enforceChecksumPolicy(ctx, "primary", weakPackageChecksums(packages)); policyErr != nil {
+		return nil, resp.StatusCode, policyErr
+	}
+	r.parsed("primary", len(packages))
+	spanItems(ctx, len(packages))
+	r.packages = packages
+	r.packagesTruncated = err
+
+	return packages, resp.StatusCode, err
+}
+
+// getPrimary requests primary.xml, returning its decompressed content. The response is returned if one was
+// received; its body must be closed once the content is read.
+func (r *Repository) getPrimary(ctx context.Context) (io.Reader, *http.Response, error) {
 	var err error
 	var primaryURL string
 	var resp *http.Response
-	var packages []Package
 
-	if r.packages != nil {
-		return r.packages, 0, nil
+	if _, _, err = r.Repomd(ctx); err != nil {
+		return nil, nil, fmt.Errorf("error parsing repomd.xml: %w", err)
+	}
+
+	if primaryURL, err = r.getPrimaryURL(ctx); err != nil {
+		return nil, nil, fmt.Errorf("Error getting primary URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, primaryURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating request: %w", err)
 	}
 
+	if resp, err = r.do(req, "primary"); err != nil {
+		return nil, resp, fmt.Errorf("GET error for file %v: %w", primaryURL, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, resp, fmt.Errorf("Cannot fetch %v: %d", primaryURL, resp.StatusCode)
+	}
+
+	reader, err := ParseCompressedData(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, resp, fmt.Errorf("error unzipping response body: %w", err)
+	}
+	return r.decompressed("primary", reader), resp, nil
+}
+
+// PackageSummary returns the package count and total metadata size of the repository without parsing
+// the packages in primary.xml. Only the packages attribute of the root element is read, so this is fast
+// even for very large repositories, and cached until Clear. Returns response code and error.
+func (r *Repository) PackageSummary(ctx context.Context) (*PackageSummary, int, error) {
+	var err error
+	var primaryURL string
+	var resp *http.Response
+	summary := PackageSummary{}
+
 	if _, _, err = r.Repomd(ctx); err != nil {
 		return nil, 0, fmt.Errorf("error parsing repomd.xml: %w", err)
 	}
+	for _, data := range r.repomd.Data {
+		summary.MetadataSize += data.Size
+	}
+
+	if r.packageCount != nil {
+		summary.PackageCount = *r.packageCount
+		return &summary, 200, nil
+	}
 
 	if primaryURL, err = r.getPrimaryURL(ctx); err != nil {
 		return nil, 0, fmt.Errorf("Error getting primary URL: %w", err)
 	}
 
-	if resp, err = r.settings.Client.Get(primaryURL); err != nil {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, primaryURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	if resp, err = r.do(req, "primary"); err != nil {
 		return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", primaryURL, err)
 	}
 	defer resp.Body.Close()
@@ -261,12 +815,12 @@ func (r *Repository) Packages(ctx context.Context) ([]Package, int, error) {
 		return nil, resp.StatusCode, fmt.Errorf("Cannot fetch %v: %d", primaryURL, resp.StatusCode)
 	}
 
-	if packages, err = ParseCompressedXMLData(io.NopCloser(resp.Body), *r.settings.MaxXmlSize); err != nil {
+	if summary.PackageCount, err = ParsePackageCount(resp.Body); err != nil {
 		return nil, resp.StatusCode, err
 	}
-	r.packages = packages
+	r.packageCount = &summary.PackageCount
 
-	return packages, resp.StatusCode, nil
+	return &summary, resp.StatusCode, nil
 }
 
 // PackageGroups populates r.PackageGroups with the package groups of a repository. Returns response code and error.
@@ -313,6 +867,28 @@ func (r *Repository) Environments(ctx context.Context) ([]Environment, int, erro
 	return nil, status, nil
 }
 
+// Categories populates r.Categories with the categories of a repository. Returns response code and error.
+func (r *Repository) Categories(ctx context.Context) ([]Category, int, error) {
+	var err error
+	var status int
+	var comps *Comps
+
+	if r.comps != nil && r.comps.Categories != nil {
+		return r.comps.Categories, 200, nil
+	}
+
+	if comps, status, err = r.Comps(ctx); err != nil {
+		return nil, 0, fmt.Errorf("error getting comps: %w", err)
+	}
+
+	if compsURL, _ := r.getCompsURL(); compsURL != nil {
+		r.comps.Categories = comps.Categories
+		return r.comps.Categories, status, nil
+	}
+
+	return nil, status, nil
+}
+
 // Signature fetches the yum metadata signature and returns any error and HTTP code encountered.
 // If the signature was successfully fetched previously, will return cached signature.
 func (r *Repository) Signature(ctx context.Context) (*string, int, error) {
@@ -327,88 +903,197 @@ func (r *Repository) Signature(ctx context.Context) (*string, int, error) {
 		return nil, 0, err
 	}
 
-	resp, err := r.settings.Client.Get(sigUrl)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sigUrl, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := r.do(req, "signature")
 	if err != nil {
 		return nil, erroredStatusCode(resp), err
-	} else if resp.StatusCode < 200 || resp.StatusCode > 299 {
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		return nil, resp.StatusCode, fmt.Errorf("received http %d", resp.StatusCode)
 	}
 
 	if sig, err = responseBodyToString(resp.Body); err != nil {
 		return nil, resp.StatusCode, err
 	}
-	resp.Body.Close()
 
 	r.repomdSignature = sig
 	return sig, resp.StatusCode, err
 }
 
+// GPGKey fetches the repository's signing key from the first of the configured GPGKeyPaths, or
+// DefaultGPGKeyPaths, that serves a valid armored key. Returns response code and error of the last
+// location tried if none does.
+func (r *Repository) GPGKey(ctx context.Context) (*string, int, error) {
+	var err error
+	var code int
+	var gpgKey *string
+
+	if r.gpgKey != nil {
+		return r.gpgKey, 200, nil
+	}
+
+	keyPaths := r.settings.GPGKeyPaths
+	if keyPaths == nil {
+		keyPaths = DefaultGPGKeyPaths
+	}
+	if len(keyPaths) == 0 {
+		return nil, 0, fmt.Errorf("no gpg key paths configured")
+	}
+
+	for _, keyPath := range keyPaths {
+		keyURL, urlErr := r.getLocationURL(Location{Href: keyPath})
+		if urlErr != nil {
+			return nil, 0, fmt.Errorf("error parsing gpg key URL: %w", urlErr)
+		}
+		if gpgKey, code, err = r.fetchGPGKey(ctx, keyURL); err == nil {
+			r.gpgKey = gpgKey
+			return gpgKey, code, nil
+		}
+		err = fmt.Errorf("GET error for file %v: %w", keyURL, err)
+	}
+	return nil, code, err
+}
+
+// fetchGPGKey fetches the armored key at keyURL, sent with do as any other file of the repository
+func (r *Repository) fetchGPGKey(ctx context.Context, keyURL string) (*string, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, keyURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := r.do(req, "gpgkey")
+	if err != nil {
+		return nil, erroredStatusCode(resp), err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, resp.StatusCode, fmt.Errorf("received http %d", resp.StatusCode)
+	}
+
+	body, err := readGPGKeyBody(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	if err = checkGPGKey(string(body)); err != nil {
+		return nil, resp.StatusCode, err
+	}
+	return Ptr(string(body)), resp.StatusCode, nil
+}
+
+// repositoryURL returns the URL of the repository, with the yum variables in it substituted
+func (r *Repository) repositoryURL() string {
+	return SubstituteVars(*r.settings.URL, r.settings.Vars)
+}
+
 func (r *Repository) getRepomdURL() (string, error) {
-	u, err := url.Parse(*r.settings.URL)
+	return joinURL(r.repositoryURL(), "repodata/repomd.xml")
+}
+
+// joinURL joins href onto the path of base. The query strings of both are kept, as signed URLs such as
+// CloudFront or SAS URLs carry their token in the query string of the repository URL. Unlike
+// url.ResolveReference, href is relative to base even if base does not end with a slash. An href that is
+// an absolute URL is returned as is.
+func joinURL(base string, href string) (string, error) {
+	URL, err := url.Parse(base)
 	if err != nil {
 		return "", err
 	}
-	u.Path = path.Join(u.Path, "/repodata/repomd.xml")
-	return u.String(), nil
+	ref, err := url.Parse(href)
+	if err != nil {
+		// a plain path, such as a file name with a percent sign
+		ref = &url.URL{Path: href}
+	}
+	if ref.IsAbs() {
+		return ref.String(), nil
+	}
+
+	URL.Path = path.Join(URL.Path, ref.Path)
+	URL.RawPath = ""
+	if ref.RawQuery != "" {
+		if URL.RawQuery != "" {
+			URL.RawQuery += "&"
+		}
+		URL.RawQuery += ref.RawQuery
+	}
+	if ref.Fragment != "" {
+		URL.Fragment = ref.Fragment
+	}
+	return URL.String(), nil
 }
 
+// compsTypes lists the repomd types comps can be published as, in order of preference.
+// Smaller downloads are preferred; group_zck is not listed as zchunk is not supported.
+var compsTypes = []string{"group_zst", "group_xz", "group_gz", "group"}
+
 func (r *Repository) getCompsURL() (*string, error) {
-	var compsLocation string
+	var compsLocation Location
 
-	for _, data := range r.repomd.Data {
-		if data.Type == "group_gz" {
-			compsLocation = data.Location.Href
-		} else if data.Type == "group" {
-			compsLocation = data.Location.Href
+	for _, compsType := range compsTypes {
+		for _, data := range r.repomd.Data {
+			if data.Type == compsType {
+				compsLocation = data.Location
+				break
+			}
+		}
+		if compsLocation.Href != "" {
+			break
 		}
 	}
 
-	if compsLocation == "" {
+	if compsLocation.Href == "" {
 		return nil, nil
 	}
 
-	url, err := url.Parse(*r.settings.URL)
+	compsURL, err := r.getLocationURL(compsLocation)
 	if err != nil {
 		return nil, err
 	}
-	url.Path = path.Join(url.Path, compsLocation)
-	return Ptr(url.String()), nil
+	return &compsURL, nil
 }
 
 func (r *Repository) getModulesURL() (*string, error) {
-	var compsLocation string
+	var compsLocation Location
 
 	for _, data := range r.repomd.Data {
 		if data.Type == "modules_gz" {
-			compsLocation = data.Location.Href
+			compsLocation = data.Location
 		} else if data.Type == "modules" {
-			compsLocation = data.Location.Href
+			compsLocation = data.Location
 		}
 	}
 
-	if compsLocation == "" {
+	if compsLocation.Href == "" {
 		return nil, nil
 	}
 
-	URL, err := url.Parse(*r.settings.URL)
+	modulesURL, err := r.getLocationURL(compsLocation)
 	if err != nil {
 		return nil, err
 	}
-	URL.Path = path.Join(URL.Path, compsLocation)
-	return Ptr(URL.String()), nil
+	return &modulesURL, nil
 }
 
-func (r *Repository) getSignatureURL() (string, error) {
-	url, err := r.getRepomdURL()
-	if err == nil {
-		return url + ".asc", nil
-	} else {
-		return "", err
+// getLocationURL joins the href of a location onto its xml:base if it has one, otherwise onto the
+// repository URL. An href that is an absolute URL is used as is.
+func (r *Repository) getLocationURL(location Location) (string, error) {
+	base := location.Base
+	if base == "" {
+		base = r.repositoryURL()
 	}
+	return joinURL(base, location.Href)
+}
+
+func (r *Repository) getSignatureURL() (string, error) {
+	return joinURL(r.repositoryURL(), "repodata/repomd.xml.asc")
 }
 
 func (r *Repository) getPrimaryURL(ctx context.Context) (string, error) {
-	var primaryLocation string
+	var primaryLocation Location
 
 	if _, _, err := r.Repomd(ctx); err != nil {
 		return "", fmt.Errorf("error fetching Repomd: %w", err)
@@ -416,19 +1101,14 @@ func (r *Repository) getPrimaryURL(ctx context.Context) (string, error) {
 
 	for _, data := range r.repomd.Data {
 		if data.Type == "primary" {
-			primaryLocation = data.Location.Href
+			primaryLocation = data.Location
 		}
 	}
 
-	if primaryLocation == "" {
+	if primaryLocation.Href == "" {
 		return "", fmt.Errorf("GET error: Unable to parse 'primary' location in repomd.xml")
 	}
-	url, err := url.Parse(*r.settings.URL)
-	if err != nil {
-		return "", err
-	}
-	url.Path = path.Join(url.Path, primaryLocation)
-	return url.String(), nil
+	return r.getLocationURL(primaryLocation)
 }
 
 func responseBodyToString(body io.ReadCloser) (*string, error) {
@@ -440,31 +1120,62 @@ func responseBodyToString(body io.ReadCloser) (*string, error) {
 	return &asString, nil
 }
 
-// ParseRepomdXML creates Repomd from repomd.xml body response
+// ParseRepomdXML creates Repomd from repomd.xml body response, keeping the document in RepomdString
 func ParseRepomdXML(body io.ReadCloser) (Repomd, error) {
+	return parseRepomdXML(body, DefaultMaxXmlSize, true)
+}
+
+// DecodeRepomdXML creates Repomd from a repomd.xml document like ParseRepomdXML, without keeping a copy of
+// the document in RepomdString
+func DecodeRepomdXML(body io.Reader) (Repomd, error) {
+	return parseRepomdXML(body, DefaultMaxXmlSize, false)
+}
+
+// parseRepomdXML decodes at most maxSize bytes of a repomd.xml as they are read. If keepRaw is set, the
+// document is captured in RepomdString, which the signature is checked against.
+func parseRepomdXML(body io.Reader, maxSize int64, keepRaw bool) (Repomd, error) {
 	var result Repomd
+	var raw strings.Builder
 
-	byteValue, err := io.ReadAll(body)
-	if err != nil {
-		return Repomd{}, fmt.Errorf("io.reader read failure: %w", err)
+	reader := io.LimitReader(body, maxSize)
+	if keepRaw {
+		reader = io.TeeReader(reader, &raw)
 	}
-
-	err = xml.Unmarshal(byteValue, &result)
-	if err != nil {
-		return Repomd{}, fmt.Errorf("xml.Unmarshal failure: %w", err)
+	if err := xml.NewDecoder(reader).Decode(&result); err != nil {
+		return Repomd{}, fmt.Errorf("xml decoding failure: %w", err)
+	}
+	// the xml:base of a data element applies to its location
+	for i, data := range result.Data {
+		if data.Location.Base == "" {
+			result.Data[i].Location.Base = data.Base
+		}
+	}
+	if keepRaw {
+		// capture what follows the root element too, such as the final newline
+		if _, err := io.Copy(io.Discard, reader); err != nil {
+			return Repomd{}, fmt.Errorf("io.reader read failure: %w", err)
+		}
+		repomdString := raw.String()
+		result.RepomdString = &repomdString
 	}
-	repomdString := string(byteValue)
-	result.RepomdString = &repomdString
 
-	return result, err
+	return result, nil
 }
 
-// ParseCompsXML creates PackageGroup array and Environment array from comps.xml body response
+// ParseCompsXML creates PackageGroup, Environment, Category and Langpack arrays from comps.xml body response
 func ParseCompsXML(body io.ReadCloser, url *string) (Comps, error) {
+	return parseCompsXML(body, DefaultMaxXmlSize)
+}
+
+// parseCompsXML decodes the elements of a comps.xml one at a time, reading at most maxSize bytes once
+// decompressed
+func parseCompsXML(body io.ReadCloser, maxSize int64) (Comps, error) {
 	var reader io.Reader
 	var comps Comps
 	packageGroups := []PackageGroup{}
 	environments := []Environment{}
+	categories := []Category{}
+	langpacks := []Langpack{}
 
 	// determine the file type from the header
 	reader, err := ExtractIfCompressed(body)
@@ -472,7 +1183,8 @@ func ParseCompsXML(body io.ReadCloser, url *string) (Comps, error) {
 		return comps, err
 	}
 
-	decoder := xml.NewDecoder(reader)
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxSize))
+	var element, item string
 
 	for {
 		t, decodeError := decoder.Token()
@@ -480,7 +1192,7 @@ func ParseCompsXML(body io.ReadCloser, url *string) (Comps, error) {
 		if decodeError == io.EOF {
 			break
 		} else if decodeError != nil {
-			return comps, fmt.Errorf("error decoding token: %w", decodeError)
+			return comps, fmt.Errorf("error decoding token: %w", xmlParseError(decoder, element, item, decodeError))
 		} else if t == nil {
 			break
 		}
@@ -488,66 +1200,139 @@ func ParseCompsXML(body io.ReadCloser, url *string) (Comps, error) {
 		switch elType := t.(type) {
 		case xml.StartElement:
 			if elType.Name.Local == "group" {
-				var packageGroup PackageGroup
+				packageGroup := PackageGroup{UserVisible: true}
 				if decodeElementError := decoder.DecodeElement(&packageGroup, &elType); decodeElementError != nil {
-					return comps, decodeElementError
+					return comps, xmlParseError(decoder, "group", cmp.Or(packageGroup.ID, item), decodeElementError)
 				}
+				item = packageGroup.ID
 				packageGroups = append(packageGroups, packageGroup)
 			} else if elType.Name.Local == "environment" {
 				var environment Environment
 				if decodeElementError := decoder.DecodeElement(&environment, &elType); decodeElementError != nil {
-					return comps, decodeElementError
+					return comps, xmlParseError(decoder, "environment", cmp.Or(environment.ID, item), decodeElementError)
 				}
+				item = environment.ID
 				environments = append(environments, environment)
+			} else if elType.Name.Local == "category" {
+				var category Category
+				if decodeElementError := decoder.DecodeElement(&category, &elType); decodeElementError != nil {
+					return comps, xmlParseError(decoder, "category", cmp.Or(category.ID, item), decodeElementError)
+				}
+				item = category.ID
+				categories = append(categories, category)
+			} else if elType.Name.Local == "langpacks" {
+				var matches struct {
+					Match []Langpack `xml:"match"`
+				}
+				if decodeElementError := decoder.DecodeElement(&matches, &elType); decodeElementError != nil {
+					return comps, xmlParseError(decoder, "langpacks", item, decodeElementError)
+				}
+				langpacks = append(langpacks, matches.Match...)
+			} else {
+				element = elType.Name.Local
 			}
 		}
 	}
 
-	return Comps{packageGroups, environments}, err
+	return Comps{packageGroups, environments, categories, langpacks}, err
 }
 
-// Custom unmarshal methods for localized elements
-func (pn *PackageGroupName) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var t string
-	if err := d.DecodeElement(&t, &start); err != nil {
+// MarshalXML writes the untranslated value followed by an element per translation, ordered by locale
+func (ls LocalizedString) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeElement(ls.Value, start); err != nil {
 		return err
 	}
-	if len(start.Attr) == 0 {
-		*pn = PackageGroupName(t)
+	locales := make([]string, 0, len(ls.Translations))
+	for locale := range ls.Translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		translated := xml.StartElement{Name: start.Name, Attr: []xml.Attr{{Name: xml.Name{Space: xmlNamespace, Local: "lang"}, Value: locale}}}
+		if err := e.EncodeElement(ls.Translations[locale], translated); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (pd *PackageGroupDescription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var t string
-	if err := d.DecodeElement(&t, &start); err != nil {
-		return err
+// xmlNamespace is the namespace of the xml: prefix, as in xml:lang
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// compsDocument is the layout of comps.xml used to marshal Comps
+type compsDocument struct {
+	XMLName       xml.Name       `xml:"comps"`
+	PackageGroups []PackageGroup `xml:"group"`
+	Environments  []Environment  `xml:"environment"`
+	Categories    []Category     `xml:"category"`
+	Langpacks     *langpacksXML  `xml:"langpacks,omitempty"`
+}
+
+type langpacksXML struct {
+	Match []Langpack `xml:"match"`
+}
+
+// MarshalXML writes comps as a comps.xml document, so that parsed comps can be modified and written back
+func (c Comps) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	document := compsDocument{
+		PackageGroups: c.PackageGroups,
+		Environments:  c.Environments,
+		Categories:    c.Categories,
 	}
-	if len(start.Attr) == 0 {
-		*pd = PackageGroupDescription(t)
+	if len(c.Langpacks) > 0 {
+		document.Langpacks = &langpacksXML{Match: c.Langpacks}
 	}
-	return nil
+	return e.Encode(document)
 }
 
-func (en *EnvironmentName) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var t string
-	if err := d.DecodeElement(&t, &start); err != nil {
-		return err
-	}
-	if len(start.Attr) == 0 {
-		*en = EnvironmentName(t)
+// repomdDocument is the layout of repomd.xml used to marshal Repomd
+type repomdDocument struct {
+	XMLName  xml.Name     `xml:"repomd"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	XmlnsRpm string       `xml:"xmlns:rpm,attr"`
+	Revision string       `xml:"revision,omitempty"`
+	Data     []repomdData `xml:"data"`
+}
+
+type repomdData struct {
+	Type         string    `xml:"type,attr"`
+	Checksum     Checksum  `xml:"checksum"`
+	OpenChecksum *Checksum `xml:"open-checksum,omitempty"`
+	Location     Location  `xml:"location"`
+	Size         int64     `xml:"size,omitempty"`
+	OpenSize     int64     `xml:"open-size,omitempty"`
+	Timestamp    string    `xml:"timestamp,omitempty"`
+}
+
+// MarshalXML writes repomd as a repomd.xml document. RepomdString is not used, so a modified Repomd can be written.
+func (r Repomd) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	document := repomdDocument{Xmlns: repoNamespace, XmlnsRpm: rpmNamespace, Revision: r.Revision}
+	for _, data := range r.Data {
+		entry := repomdData{Type: data.Type, Checksum: data.Checksum, Location: data.Location, Size: data.Size, OpenSize: data.OpenSize, Timestamp: data.Timestamp}
+		if data.OpenChecksum.Value != "" {
+			entry.OpenChecksum = &data.OpenChecksum
+		}
+		document.Data = append(document.Data, entry)
 	}
-	return nil
+	return e.Encode(document)
 }
 
-func (ed *EnvironmentDescription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+// UnmarshalXML collects each localized variant of an element into Translations, keyed by xml:lang
+func (ls *LocalizedString) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var t string
 	if err := d.DecodeElement(&t, &start); err != nil {
 		return err
 	}
-	if len(start.Attr) == 0 {
-		*ed = EnvironmentDescription(t)
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "lang" {
+			if ls.Translations == nil {
+				ls.Translations = map[string]string{}
+			}
+			ls.Translations[attr.Value] = t
+			return nil
+		}
 	}
+	ls.Value = t
 	return nil
 }
 
@@ -557,17 +1342,48 @@ func (ed *EnvironmentDescription) UnmarshalXML(d *xml.Decoder, start xml.StartEl
 //
 // Returns an array of package data
 func ParseCompressedXMLData(body io.Reader, maxSize int64) ([]Package, error) {
-	var reader io.Reader
-	var err error
-	result := []Package{}
-
-	reader, err = ParseCompressedData(body)
+	reader, err := ParseCompressedData(body)
 	if err != nil {
 		return []Package{}, fmt.Errorf("error unzipping response body: %w", err)
 	}
+	return parsePackagesXML(reader, maxSize, 0)
+}
+
+// maxPreallocatedPackages bounds the capacity preallocated from the package count of a primary.xml
+const maxPreallocatedPackages = 100000
+
+// parsePackagesXML parses the packages of an uncompressed primary.xml, reading at most maxSize bytes.
+// The reader is read ahead in a pipeline, so that decompressing primary.xml overlaps with decoding it.
+func parsePackagesXML(reader io.Reader, maxSize int64, maxPackages int) ([]Package, error) {
+	result := []Package{}
+	interner := stringInterner{}
+	err := decodePackagesXML(reader, maxSize, maxPackages, func(count int) {
+		// Size the result from the package count, bounded in case it is bogus
+		result = make([]Package, 0, min(count, maxPreallocatedPackages))
+	}, func(pkg Package) error {
+		interner.internPackage(&pkg)
+		result = append(result, pkg)
+		return nil
+	})
+	var truncated *TruncatedError
+	if err != nil && !errors.As(err, &truncated) {
+		return []Package{}, err
+	}
+	return result, err
+}
+
+// decodePackagesXML decodes the rpm packages of an uncompressed primary.xml one at a time, reading at most
+// maxSize bytes. counted is called with the package count of the <metadata> element if it has one, and
+// add with every package, stopping at the first error it returns. If maxPackages is positive, decoding
+// stops with a *TruncatedError at the rpm package following the first maxPackages.
+func decodePackagesXML(reader io.Reader, maxSize int64, maxPackages int, counted func(int), add func(Package) error) error {
+	added := 0
 
-	limitedReader := io.LimitReader(reader, maxSize)
+	pipelined := newPipelinedReader(reader)
+	defer pipelined.Close()
+	limitedReader := io.LimitReader(pipelined, maxSize)
 	decoder := xml.NewDecoder(limitedReader)
+	var element, item string
 
 	for {
 		// Read tokens from the XML document in a stream.
@@ -577,7 +1393,7 @@ func ParseCompressedXMLData(body io.Reader, maxSize int64) ([]Package, error) {
 		if decodeError == io.EOF {
 			break
 		} else if decodeError != nil {
-			return []Package{}, fmt.Errorf("error decoding token: %w", decodeError)
+			return fmt.Errorf("error decoding token: %w", xmlParseError(decoder, element, item, decodeError))
 		} else if t == nil {
 			break
 		}
@@ -585,54 +1401,88 @@ func ParseCompressedXMLData(body io.Reader, maxSize int64) ([]Package, error) {
 		// Here, we inspect the token
 		switch elType := t.(type) {
 		case xml.StartElement:
+			if elType.Name.Local != "package" {
+				element = elType.Name.Local
+			}
 			switch elType.Name.Local {
+			case "metadata":
+				for _, attr := range elType.Attr {
+					if count, err := strconv.Atoi(attr.Value); attr.Name.Local == "packages" && err == nil && count > 0 {
+						if maxPackages > 0 {
+							count = min(count, maxPackages)
+						}
+						counted(count)
+					}
+				}
 			// Found an item, so we process it
 			case "package":
 				var pkg Package
 				if decodeElementError := decoder.DecodeElement(&pkg, &elType); decodeElementError != nil {
-					return result, decodeElementError
+					return xmlParseError(decoder, "package", cmp.Or(pkg.Name, item), decodeElementError)
 				}
+				item = pkg.Name
 				// Ensure that the type is "rpm" before pushing our array
 				if pkg.Type != "rpm" {
 					break
 				}
-				result = append(result, pkg)
+				if maxPackages > 0 && added == maxPackages {
+					return &TruncatedError{Limit: maxPackages}
+				}
+				if err := add(pkg); err != nil {
+					return err
+				}
+				added++
 			}
 		}
 	}
 
-	return result, nil
+	return nil
 }
 
-func ParseCompressedData(body io.Reader) (io.Reader, error) {
-	var reader io.Reader
+// ParsePackageCount reads the packages attribute of the <metadata> element of a compressed primary.xml,
+// stopping as soon as it is found instead of decoding the package list.
+func ParsePackageCount(body io.Reader) (int, error) {
+	reader, err := ParseCompressedData(body)
+	if err != nil {
+		return 0, fmt.Errorf("error unzipping response body: %w", err)
+	}
 
-	bufferedReader := bufio.NewReader(body)
+	decoder := xml.NewDecoder(reader)
+	for {
+		t, decodeError := decoder.Token()
+		if decodeError != nil {
+			return 0, fmt.Errorf("error decoding token: %w", decodeError)
+		}
 
-	// peek at the first bytes to determine the type
-	header, err := bufferedReader.Peek(20)
-	if err != nil {
-		return nil, err
+		if elType, ok := t.(xml.StartElement); ok {
+			if elType.Name.Local != "metadata" {
+				return 0, fmt.Errorf("unexpected root element %v", elType.Name.Local)
+			}
+			for _, attr := range elType.Attr {
+				if attr.Name.Local == "packages" {
+					count, err := strconv.Atoi(attr.Value)
+					if err != nil {
+						return 0, fmt.Errorf("invalid packages attribute: %w", err)
+					}
+					return count, nil
+				}
+			}
+			return 0, fmt.Errorf("packages attribute not found")
+		}
 	}
+}
 
-	fileType, err := filetype.Match(header)
+// ParseCompressedData returns a reader decompressing body, which holds gzip, xz, zstd or bzip2 compressed or
+// uncompressed XML. See DecompressingReader for other kinds of files.
+func ParseCompressedData(body io.Reader) (io.Reader, error) {
+	bufferedReader := bufio.NewReader(body)
+	header, err := peekHeader(bufferedReader)
 	if err != nil {
 		return nil, err
 	}
-
-	switch fileType {
-	case matchers.TypeGz:
-		reader, err = gzip.NewReader(bufferedReader)
-	case matchers.TypeZstd:
-		reader, err = zstd.NewReader(bufferedReader)
-	case matchers.TypeXz:
-		reader, err = xz.NewReader(bufferedReader)
-	default:
-		return nil, fmt.Errorf("invalid file type: must be gzip, xz, or zstd")
-	}
-	if err != nil {
-		return nil, fmt.Errorf("error unzipping response body: %w", err)
+	if detectFormat(header) == FormatPlain && !looksLikeXML(header) {
+		return nil, fmt.Errorf("invalid file type: must be gzip, xz, zstd, bzip2 or XML")
 	}
-
+	reader, _, err := DecompressingReader(bufferedReader)
 	return reader, err
 }
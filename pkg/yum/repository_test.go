@@ -1,9 +1,13 @@
 package yum
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	_ "embed"
+	"encoding/json"
 	"encoding/xml"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -11,7 +15,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
 )
 
 //go:embed "mocks/repomd.xml"
@@ -49,8 +55,46 @@ func TestConfigure(t *testing.T) {
 	}
 	r.Configure(secondSettings)
 	assert.Equal(t, secondURL, *r.settings.URL)
-	assert.Equal(t, secondClient.Timeout, r.settings.Client.Timeout)
-	assert.NotEqual(t, firstClient.Timeout, r.settings.Client.Timeout)
+	assert.Equal(t, secondClient.Timeout, r.settings.Client.(*http.Client).Timeout)
+	assert.NotEqual(t, firstClient.Timeout, r.settings.Client.(*http.Client).Timeout)
+}
+
+// handlerDoer serves requests with a handler, without a network connection
+type handlerDoer struct {
+	handler  http.Handler
+	requests int
+}
+
+func (d *handlerDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requests++
+	recorder := httptest.NewRecorder()
+	d.handler.ServeHTTP(recorder, req)
+	return recorder.Result(), nil
+}
+
+func TestHTTPDoer(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	doer := &handlerDoer{handler: s.Config.Handler}
+	url := "http://repo.example.com/"
+	r, err := NewRepository(YummySettings{Client: doer, URL: &url})
+	assert.NoError(t, err)
+	packages, code, err := r.Packages(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, packages, 2)
+	assert.Equal(t, 2, doer.requests)
+
+	// the transport of a client that is not an *http.Client cannot be configured
+	_, err = NewRepository(YummySettings{Client: doer, URL: &url, DisableProxy: true})
+	assert.ErrorContains(t, err, "error disabling proxy")
+
+	// a nil *http.Client is the default client
+	var client *http.Client
+	r, err = NewRepository(YummySettings{Client: client, URL: &url})
+	assert.NoError(t, err)
+	assert.Equal(t, http.DefaultClient, r.settings.Client)
 }
 
 func TestClear(t *testing.T) {
@@ -98,6 +142,123 @@ func TestGetPrimaryURL(t *testing.T) {
 	assert.Equal(t, "http://foo.example.com/repo/repodata/primary.xml.gz", primary)
 }
 
+func TestXMLBase(t *testing.T) {
+	repomdWithBase := `<repomd xmlns="http://linux.duke.edu/metadata/repo">
+<data type="primary"><location xml:base="http://cdn.example.com/content/" href="repodata/primary.xml.gz"/></data>
+<data type="group" xml:base="http://groups.example.com/"><location href="repodata/comps.xml"/></data>
+<data type="updateinfo"><location href="repodata/updateinfo.xml.gz"/></data>
+</repomd>`
+	repomd, err := ParseRepomdXML(io.NopCloser(bytes.NewReader([]byte(repomdWithBase))))
+	assert.NoError(t, err)
+	r, _ := NewRepository(YummySettings{URL: Ptr("http://foo.example.com/repo/")})
+	r.repomd = &repomd
+
+	primary, err := r.getPrimaryURL(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "http://cdn.example.com/content/repodata/primary.xml.gz", primary)
+	comps, err := r.getCompsURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://groups.example.com/repodata/comps.xml", *comps)
+	updateInfo, err := r.getUpdateInfoURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://foo.example.com/repo/repodata/updateinfo.xml.gz", *updateInfo)
+	// the base is written back when marshaling
+	marshaled, err := xml.Marshal(repomd)
+	assert.NoError(t, err)
+	assert.Contains(t, string(marshaled), `xml:base="http://cdn.example.com/content/"`)
+	roundTripped, err := ParseRepomdXML(io.NopCloser(bytes.NewReader(marshaled)))
+	assert.NoError(t, err)
+	assert.Equal(t, "http://groups.example.com/", roundTripped.Data[1].Location.Base)
+}
+
+func TestAbsoluteHref(t *testing.T) {
+	repomdWithAbsoluteHrefs := `<repomd xmlns="http://linux.duke.edu/metadata/repo">
+<data type="primary"><location href="https://cdn.example.com/repodata/primary.xml.gz"/></data>
+<data type="group"><location xml:base="http://groups.example.com/" href="http://other.example.com/comps.xml"/></data>
+</repomd>`
+	repomd, err := ParseRepomdXML(io.NopCloser(bytes.NewReader([]byte(repomdWithAbsoluteHrefs))))
+	assert.NoError(t, err)
+	r, _ := NewRepository(YummySettings{URL: Ptr("http://foo.example.com/repo/")})
+	r.repomd = &repomd
+
+	primary, err := r.getPrimaryURL(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/repodata/primary.xml.gz", primary)
+	comps, err := r.getCompsURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://other.example.com/comps.xml", *comps)
+}
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		base, href, expected string
+	}{
+		{"http://example.com/repo", "repodata/repomd.xml", "http://example.com/repo/repodata/repomd.xml"},
+		{"http://example.com/repo/", "/repodata/repomd.xml", "http://example.com/repo/repodata/repomd.xml"},
+		{"https://cdn.example.com:8443/repo/?Signature=abc&Expires=1", "repodata/primary.xml.gz", "https://cdn.example.com:8443/repo/repodata/primary.xml.gz?Signature=abc&Expires=1"},
+		{"https://account.blob.core.windows.net/repo?sv=2022&sig=a%2Fb", "repodata/repomd.xml.asc", "https://account.blob.core.windows.net/repo/repodata/repomd.xml.asc?sv=2022&sig=a%2Fb"},
+		{"http://example.com/repo?token=1", "Packages/foo.rpm?arch=x86_64", "http://example.com/repo/Packages/foo.rpm?token=1&arch=x86_64"},
+		{"http://example.com/repo#top", "repodata/repomd.xml", "http://example.com/repo/repodata/repomd.xml#top"},
+		{"http://example.com/repo", "Packages/c++-100%.rpm", "http://example.com/repo/Packages/c++-100%25.rpm"},
+		{"http://example.com/repo?token=1", "https://mirror.example.com/foo.rpm", "https://mirror.example.com/foo.rpm"},
+	}
+	for _, test := range tests {
+		joined, err := joinURL(test.base, test.href)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, joined, "%v %v", test.base, test.href)
+	}
+}
+
+func TestSignedRepositoryURL(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	signed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") != "s3cret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		s.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer signed.Close()
+
+	r, _ := NewRepository(YummySettings{Client: signed.Client(), URL: Ptr(signed.URL + "/?token=s3cret")})
+	packages, code, err := r.Packages(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, packages, 2)
+	_, code, err = r.Signature(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestXMLBasePackages(t *testing.T) {
+	rpm := []byte("rpm content")
+	packages := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pool/foo-1.0-1.noarch.rpm" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(rpm)
+	}))
+	defer packages.Close()
+
+	primary := `<metadata xmlns="http://linux.duke.edu/metadata/common" packages="1"><package type="rpm">
+<name>foo</name><arch>noarch</arch><version epoch="0" ver="1.0" rel="1"/>
+<checksum type="sha256" pkgid="YES">` + sha256Hex(rpm) + `</checksum><size package="11"/>
+<location xml:base="` + packages.URL + `/pool/" href="foo-1.0-1.noarch.rpm"/></package></metadata>`
+	parsed, err := parsePackagesXML(bytes.NewReader([]byte(primary)), DefaultMaxXmlSize, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, Location{Href: "foo-1.0-1.noarch.rpm", Base: packages.URL + "/pool/"}, parsed[0].Location)
+
+	r, _ := NewRepository(YummySettings{Client: packages.Client(), URL: Ptr("http://foo.example.com/repo/")})
+	var downloaded bytes.Buffer
+	_, code, err := r.DownloadPackage(context.Background(), parsed[0], &downloaded)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, rpm, downloaded.Bytes())
+}
+
 func TestFetchRepomd(t *testing.T) {
 	s := server()
 	defer s.Close()
@@ -117,28 +278,50 @@ func TestFetchRepomd(t *testing.T) {
 		},
 		Data: []Data{
 			{
-				Type:     "other",
-				Location: Location{Href: "repodata/other.xml.gz"},
+				Type:         "other",
+				Location:     Location{Href: "repodata/other.xml.gz"},
+				Checksum:     Checksum{Type: "sha256", Value: "1b2d80894d18ec9ee51c740ed171c55ef997fbd6455c8923a156ecceabb69b1a"},
+				OpenChecksum: Checksum{Type: "sha256", Value: "b34a91c4bac7724ae1fbfc8ccbf36d7ed14d0ef75efefa16d4e7b9246fa4aa80"},
+				Size:         617,
+				OpenSize:     1478,
+				Timestamp:    "1308257578",
 			},
 			{
-				Type:     "filelists",
-				Location: Location{Href: "repodata/filelists.xml.gz"},
+				Type:         "filelists",
+				Location:     Location{Href: "repodata/filelists.xml.gz"},
+				Checksum:     Checksum{Type: "sha256", Value: "3b6af68cfdc74dfc4ce2dfe6e85abe71565ecfa37c1f048fd9f93034b0992be5"},
+				OpenChecksum: Checksum{Type: "sha256", Value: "fe0d771917855c28b2b8e48c9e4f29e526287e847f90ca4147bb90567d784968"},
+				Size:         672,
+				OpenSize:     1719,
+				Timestamp:    "1308257578",
 			},
 			{
-				Type:     "primary",
-				Location: Location{Href: "repodata/primary.xml.gz"},
+				Type:         "primary",
+				Location:     Location{Href: "repodata/primary.xml.gz"},
+				Checksum:     Checksum{Type: "sha256", Value: "0d601662ea6b0c7e71e02a1a71a85852b3ddba6ff900ad9406d38fb543393091"},
+				OpenChecksum: Checksum{Type: "sha256", Value: "dff2c3b65b1c2636b99510afd7e4ec36d9db996f16cc6e2485a62f04894d0476"},
+				Size:         1304,
+				OpenSize:     8525,
+				Timestamp:    "1308257578",
 			},
 			{
-				Type:     "group",
-				Location: Location{Href: "repodata/comps.xml"},
+				Type:      "group",
+				Location:  Location{Href: "repodata/comps.xml"},
+				Checksum:  Checksum{Type: "sha256", Value: "9585b88283adb08e9b70345ed8fb02e0a0cb212adc9fd810822c44112cec059c"},
+				Size:      406830,
+				Timestamp: "1698193209",
 			},
 			{
-				Type:     "updateinfo",
-				Location: Location{Href: "repodata/updateinfo.xml.gz"},
+				Type:      "updateinfo",
+				Location:  Location{Href: "repodata/updateinfo.xml.gz"},
+				Checksum:  Checksum{Type: "sha256", Value: "1a3f4adf9a598d5badaaef70e67a0f02198c68ca118f5543a91c3fd8ca95c6aa"},
+				Timestamp: "1299190192",
 			},
 			{
-				Type:     "modules",
-				Location: Location{Href: "repodata/module.yaml.zst"},
+				Type:      "modules",
+				Location:  Location{Href: "repodata/module.yaml.zst"},
+				Checksum:  Checksum{Type: "sha256", Value: "4307ecf77fe1abaf567a15336c5141d813ae223602d2bc4cd606b94fd9269fd4"},
+				Timestamp: "1299190192",
 			},
 		},
 		Revision:     "1308257578",
@@ -203,6 +386,43 @@ func TestGetCompsURL(t *testing.T) {
 	comps, err = r.getCompsURL()
 	assert.Nil(t, err)
 	assert.Nil(t, comps)
+
+	// compressed comps are preferred when available
+	r.repomd = &Repomd{Data: []Data{
+		{Type: "group", Location: Location{Href: "repodata/comps.xml"}},
+		{Type: "group_zck", Location: Location{Href: "repodata/comps.xml.zck"}},
+		{Type: "group_gz", Location: Location{Href: "repodata/comps.xml.gz"}},
+	}}
+	comps, err = r.getCompsURL()
+	assert.Nil(t, err)
+	assert.Equal(t, "http://foo.example.com/repo/repodata/comps.xml.gz", *comps)
+
+	r.repomd.Data = append(r.repomd.Data, Data{Type: "group_xz", Location: Location{Href: "repodata/comps.xml.xz"}})
+	comps, err = r.getCompsURL()
+	assert.Nil(t, err)
+	assert.Equal(t, "http://foo.example.com/repo/repodata/comps.xml.xz", *comps)
+}
+
+func TestFetchCompressedComps(t *testing.T) {
+	compsGz, err := os.ReadFile("mocks/comps.xml.gz")
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<repomd><data type="group"><location href="repodata/missing.xml"/></data>` +
+			`<data type="group_gz"><location href="repodata/comps.xml.gz"/></data></repomd>`))
+	})
+	mux.HandleFunc("/repodata/comps.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(compsGz)
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	packageGroups, code, err := r.PackageGroups(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, 2, len(packageGroups))
 }
 
 func TestFetchPackages(t *testing.T) {
@@ -221,6 +441,81 @@ func TestFetchPackages(t *testing.T) {
 	assert.Equal(t, packages, r.packages)
 	assert.Equal(t, 200, code)
 	assert.Nil(t, err)
+	assert.Equal(t, "Packages/n/nss-devel-3.19.1-18.el7.i686.rpm", packages[0].Location.Href)
+	assert.Equal(t, PackageSize{Package: 215192, Installed: 757126, Archive: 764528}, packages[0].Size)
+	assert.Equal(t, "MPLv2.0", packages[0].License)
+	assert.Equal(t, "nss-3.19.1-18.el7.src.rpm", packages[0].SourceRPM)
+	assert.Equal(t, &HeaderRange{Start: 1384, End: 54420}, packages[0].HeaderRange)
+	assert.Equal(t, &HeaderRange{Start: 1384, End: 9848}, packages[1].HeaderRange)
+}
+
+func TestFetchPackagesMaxPackages(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, MaxPackages: 1})
+	packages, code, err := r.Packages(context.Background())
+	var truncated *TruncatedError
+	assert.ErrorAs(t, err, &truncated)
+	assert.Equal(t, 1, truncated.Limit)
+	assert.Equal(t, 200, code)
+	assert.Len(t, packages, 1)
+	assert.Equal(t, "nss-devel", packages[0].Name)
+
+	// the cached packages are still reported as truncated
+	packages, _, err = r.Packages(context.Background())
+	assert.ErrorAs(t, err, &truncated)
+	assert.Len(t, packages, 1)
+
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, MaxPackages: 2})
+	packages, _, err = r.Packages(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, packages, 2)
+}
+
+func TestFetchPackageSummary(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	c := s.Client()
+	settings := YummySettings{
+		Client: c,
+		URL:    &s.URL,
+	}
+	r, _ := NewRepository(settings)
+
+	summary, code, err := r.PackageSummary(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, 32921, summary.PackageCount)
+	assert.Equal(t, int64(617+672+1304+406830), summary.MetadataSize)
+	assert.Nil(t, r.packages)
+
+	// the advertised count is reported from cache, even once packages are parsed
+	_, _, err = r.Packages(context.Background())
+	assert.Nil(t, err)
+	summary, code, err = r.PackageSummary(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, 32921, summary.PackageCount)
+}
+
+func TestParsePackageCount(t *testing.T) {
+	paths := []string{
+		"mocks/primary.xml.gz",
+		"mocks/primary.xml.xz",
+		"mocks/primary.xml.zst",
+		"mocks/primary.xml.bz2",
+	}
+
+	for _, path := range paths {
+		xmlFile, err := os.Open(path)
+		assert.NoError(t, err)
+		defer xmlFile.Close()
+		count, err := ParsePackageCount(xmlFile)
+		assert.NoError(t, err)
+		assert.Equal(t, 32921, count)
+	}
 }
 
 func TestFetchPackageGroups(t *testing.T) {
@@ -235,10 +530,27 @@ func TestFetchPackageGroups(t *testing.T) {
 	r, _ := NewRepository(settings)
 
 	packageGroups, code, err := r.PackageGroups(context.Background())
-	assert.Equal(t, 1, len(packageGroups))
+	assert.Equal(t, 2, len(packageGroups))
 	assert.Equal(t, packageGroups, r.comps.PackageGroups)
 	assert.Equal(t, 200, code)
 	assert.Nil(t, err)
+
+	assert.False(t, packageGroups[0].Default)
+	assert.False(t, packageGroups[0].UserVisible)
+	assert.True(t, packageGroups[0].BiarchOnly)
+	assert.Equal(t, 5, packageGroups[0].DisplayOrder)
+
+	// uservisible defaults to true when not set
+	assert.True(t, packageGroups[1].Default)
+	assert.True(t, packageGroups[1].UserVisible)
+	assert.False(t, packageGroups[1].BiarchOnly)
+
+	assert.Equal(t, []PackageReq{
+		{Name: "glx-utils", Type: PackageReqMandatory},
+		{Name: "mesa-dri-drivers", Type: PackageReqDefault},
+		{Name: "xorg-x11-drivers", Type: PackageReqOptional},
+		{Name: "nss-devel", Type: PackageReqConditional, Requires: "tpm-quote-tools"},
+	}, packageGroups[0].PackageList)
 }
 
 func TestFetchEnvironments(t *testing.T) {
@@ -259,6 +571,52 @@ func TestFetchEnvironments(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestFetchCategories(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	c := s.Client()
+	settings := YummySettings{
+		Client: c,
+		URL:    &s.URL,
+	}
+	r, _ := NewRepository(settings)
+
+	categories, code, err := r.Categories(context.Background())
+	assert.Equal(t, 1, len(categories))
+	assert.Equal(t, categories, r.comps.Categories)
+	assert.Equal(t, 200, code)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "desktops", categories[0].ID)
+	assert.Equal(t, "Desktops", categories[0].Name.Value)
+	assert.Equal(t, "Desktop environments.", categories[0].Description.Value)
+	assert.Equal(t, "Desktop-Umgebungen.", categories[0].Description.ForLocale("de"))
+	assert.Equal(t, []string{"base-x", "kde-desktop"}, categories[0].GroupList)
+}
+
+func TestLocalizedString(t *testing.T) {
+	xmlFile, err := os.Open("mocks/comps.xml")
+	assert.NoError(t, err)
+	defer xmlFile.Close()
+
+	comps, err := ParseCompsXML(xmlFile, nil)
+	assert.NoError(t, err)
+
+	name := comps.PackageGroups[0].Name
+	assert.Equal(t, "base-x", name.Value)
+	assert.Equal(t, "base-x", name.String())
+	assert.Equal(t, "kanta-x", name.ForLocale("fi"))
+	assert.Equal(t, "kanta-x", name.ForLocale("fi_FI"))
+	assert.Equal(t, "база-х", name.ForLocale("bg"))
+	assert.Equal(t, "base-x", name.ForLocale("xx"))
+
+	description := comps.Environments[0].Description
+	assert.Contains(t, description.Value, "The KDE Plasma Workspaces")
+	assert.Equal(t, description.Value, description.ForLocale("xx"))
+	assert.Contains(t, description.ForLocale("sk"), "KDE Plasma")
+}
+
 func TestBadUrl(t *testing.T) {
 	badUrl := "example.com/"
 	s := server()
@@ -306,10 +664,35 @@ func TestParseCompsXML(t *testing.T) {
 		comps, err := ParseCompsXML(xmlFile, &path)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, comps)
+		assert.Equal(t, []Langpack{
+			{Name: "firefox", Install: "firefox-langpack-%s"},
+			{Name: "libreoffice-core", Install: "libreoffice-langpack-%s"},
+		}, comps.Langpacks)
+		assert.Equal(t, "firefox-langpack-de", comps.Langpacks[0].PackageForLang("de"))
 	}
 }
 
 // if the xml is half complete, you get a parse error
+func TestParseRepomdXML(t *testing.T) {
+	repomd, err := ParseRepomdXML(io.NopCloser(bytes.NewReader(repomdXML)))
+	assert.NoError(t, err)
+	assert.NotNil(t, repomd.RepomdString)
+	assert.Equal(t, string(repomdXML), *repomd.RepomdString)
+
+	decoded, err := DecodeRepomdXML(bytes.NewReader(repomdXML))
+	assert.NoError(t, err)
+	assert.Nil(t, decoded.RepomdString)
+	assert.Equal(t, repomd.Data, decoded.Data)
+
+	_, err = parseRepomdXML(bytes.NewReader(repomdXML), 100, true)
+	assert.Error(t, err)
+}
+
+func TestParseCompsXMLMaxLimit(t *testing.T) {
+	_, err := parseCompsXML(io.NopCloser(bytes.NewReader(compsXML)), 200)
+	assert.Error(t, err)
+}
+
 func TestParseCompressedXMLDataWithError(t *testing.T) {
 	xmlFile, err := os.Open("mocks/primary.xml.gz")
 	assert.NoError(t, err)
@@ -335,6 +718,7 @@ func TestParseCompressedXMLData(t *testing.T) {
 		"mocks/primary.xml.gz",
 		"mocks/primary.xml.xz",
 		"mocks/primary.xml.zst",
+		"mocks/primary.xml.bz2",
 	}
 
 	for _, path := range paths {
@@ -362,6 +746,102 @@ func TestParseCompressedXMLData(t *testing.T) {
 	}
 }
 
+// Check that every member of metadata compressed as concatenated streams is read
+func TestParseCompressedXMLDataMultistream(t *testing.T) {
+	gz, err := gzip.NewReader(bytes.NewReader(primaryXML))
+	assert.NoError(t, err)
+	decompressed, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	// split within the first package, so that a reader stopping at the first stream parses none
+	split := bytes.Index(decompressed, []byte("<package ")) + 100
+
+	compressors := map[string]func(io.Writer) (io.WriteCloser, error){
+		"gzip": func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+		"zstd": func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) },
+		"xz":   func(w io.Writer) (io.WriteCloser, error) { return xz.NewWriter(w) },
+	}
+	for name, compressor := range compressors {
+		var concatenated bytes.Buffer
+		for _, member := range [][]byte{decompressed[:split], decompressed[split:]} {
+			w, err := compressor(&concatenated)
+			assert.NoError(t, err)
+			_, err = w.Write(member)
+			assert.NoError(t, err)
+			assert.NoError(t, w.Close())
+		}
+
+		result, err := ParseCompressedXMLData(&concatenated, DefaultMaxXmlSize)
+		assert.NoError(t, err, name)
+		assert.Len(t, result, 2, name)
+	}
+}
+
+// Check that primary.xml.gz served with a Content-Encoding is parsed whether the client decodes it or not
+func TestContentEncoding(t *testing.T) {
+	var doubleCompressed bytes.Buffer
+	gz := gzip.NewWriter(&doubleCompressed)
+	_, err := gz.Write(primaryXML)
+	assert.NoError(t, err)
+	assert.NoError(t, gz.Close())
+
+	tests := []struct {
+		name   string
+		body   []byte
+		client *http.Client
+	}{
+		{"decoded by the client", primaryXML, &http.Client{}},
+		{"double compressed", doubleCompressed.Bytes(), &http.Client{Transport: &http.Transport{DisableCompression: true}}},
+	}
+	for _, test := range tests {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repodata/repomd.xml", serveRepomdXML)
+		mux.HandleFunc("/repodata/primary.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(test.body)
+		})
+		s := httptest.NewServer(mux)
+
+		r, _ := NewRepository(YummySettings{Client: test.client, URL: &s.URL})
+		packages, _, err := r.Packages(context.Background())
+		assert.NoError(t, err, test.name)
+		assert.Len(t, packages, 2, test.name)
+		s.Close()
+	}
+
+	// uncompressed XML
+	gzipReader, err := gzip.NewReader(bytes.NewReader(primaryXML))
+	assert.NoError(t, err)
+	result, err := ParseCompressedXMLData(gzipReader, DefaultMaxXmlSize)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+}
+
+// Check that the format of metadata shorter than the peeked header is detected, and that empty metadata fails
+func TestParseCompressedDataShortInput(t *testing.T) {
+	var compressed bytes.Buffer
+	encoder, err := zstd.NewWriter(&compressed)
+	assert.NoError(t, err)
+	_, err = encoder.Write([]byte("<a/>"))
+	assert.NoError(t, err)
+	assert.NoError(t, encoder.Close())
+	assert.Less(t, compressed.Len(), fileTypeHeaderSize)
+
+	reader, err := ParseCompressedData(&compressed)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "<a/>", string(data))
+
+	comps, err := parseCompsXML(io.NopCloser(bytes.NewReader([]byte("<comps/>"))), DefaultMaxXmlSize)
+	assert.NoError(t, err)
+	assert.Empty(t, comps.PackageGroups)
+
+	_, err = ParseCompressedData(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, ErrEmptyBody)
+	_, err = parseCompsXML(io.NopCloser(bytes.NewReader(nil)), DefaultMaxXmlSize)
+	assert.ErrorIs(t, err, ErrEmptyBody)
+}
+
 func server() *httptest.Server {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/repodata/repomd.xml", serveRepomdXML)
@@ -369,6 +849,7 @@ func server() *httptest.Server {
 	mux.HandleFunc("/repodata/comps.xml", serveCompsXML)
 	mux.HandleFunc("/repodata/repomd.xml.asc", serveSignatureXML)
 	mux.HandleFunc("/repodata/module.yaml.zst", serveModulesMd)
+	mux.HandleFunc("/repodata/updateinfo.xml.gz", serveUpdateInfoXML)
 	mux.HandleFunc("/gpgkey.pub", serveGPGKey)
 	return httptest.NewServer(mux)
 }
@@ -403,3 +884,62 @@ func serveModulesMd(w http.ResponseWriter, r *http.Request) {
 	body := moduleYamlZst
 	_, _ = w.Write(body)
 }
+
+func TestMarshalComps(t *testing.T) {
+	comps, err := ParseCompsXML(io.NopCloser(bytes.NewReader(compsXML)), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []EnvironmentOption{{GroupID: "firefox", Default: true}, {GroupID: "kde-apps", Default: true}, {GroupID: "kde-education"}, {GroupID: "kde-media", Default: true}, {GroupID: "office-suite", Default: true}}, comps.Environments[0].OptionList)
+
+	marshaled, err := xml.Marshal(comps)
+	assert.Nil(t, err)
+	assert.Contains(t, string(marshaled), `<name xml:lang="de">KDE Plasma-Arbeitsumgebung</name>`)
+
+	roundTripped, err := ParseCompsXML(io.NopCloser(bytes.NewReader(marshaled)), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, comps, roundTripped)
+}
+
+func TestMarshalRepomd(t *testing.T) {
+	repomd, err := ParseRepomdXML(io.NopCloser(bytes.NewReader(repomdXML)))
+	assert.Nil(t, err)
+	repomd.Revision = "2"
+
+	marshaled, err := xml.Marshal(repomd)
+	assert.Nil(t, err)
+
+	roundTripped, err := ParseRepomdXML(io.NopCloser(bytes.NewReader(marshaled)))
+	assert.Nil(t, err)
+	assert.Equal(t, "2", roundTripped.Revision)
+	assert.Equal(t, repomd.Data, roundTripped.Data)
+}
+
+func TestPackageJSON(t *testing.T) {
+	pkg := Package{
+		Type:     "rpm",
+		Name:     "foo",
+		Arch:     "noarch",
+		Version:  Version{Version: "1.0", Release: "1", Epoch: 1},
+		Checksum: Checksum{Type: "sha256", Value: "abcd"},
+		Size:     PackageSize{Package: 10},
+		Location: Location{Href: "Packages/foo-1.0-1.noarch.rpm"},
+	}
+	marshaled, err := json.Marshal(pkg)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"type": "rpm",
+		"name": "foo",
+		"arch": "noarch",
+		"version": {"version": "1.0", "release": "1", "epoch": 1},
+		"checksum": {"value": "abcd", "type": "sha256"},
+		"summary": "",
+		"size": {"package": 10, "installed": 0, "archive": 0},
+		"location": {"href": "Packages/foo-1.0-1.noarch.rpm"},
+		"license": "",
+		"sourceRpm": ""
+	}`, string(marshaled))
+
+	repomd := Repomd{Revision: "1", RepomdString: Ptr("<repomd/>")}
+	marshaled, err = json.Marshal(repomd)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"revision": "1"}`, string(marshaled))
+}
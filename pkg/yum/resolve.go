@@ -0,0 +1,252 @@
+package yum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrGroupNotFound is returned when resolving a group that is not in the comps of the repository
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrEnvironmentNotFound is returned when resolving an environment that is not in the comps of the repository
+	ErrEnvironmentNotFound = errors.New("environment not found")
+	// ErrModuleStreamNotFound is returned when resolving a profile of a module stream that is not in the repository
+	ErrModuleStreamNotFound = errors.New("module stream not found")
+	// ErrProfileNotFound is returned when resolving a profile that the module stream does not have
+	ErrProfileNotFound = errors.New("profile not found")
+)
+
+// EnvironmentResolution is the expansion of an environment into its groups and the packages that installing
+// it would install
+type EnvironmentResolution struct {
+	Environment   Environment     `json:"environment"`
+	Groups        []ResolvedGroup `json:"groups"`        // Groups of the grouplist, then of the optionlist
+	MissingGroups []string        `json:"missingGroups"` // Groups listed by the environment that are not in comps
+	Packages      []Package       `json:"packages"`      // Packages of the selected groups, without duplicates
+}
+
+// ResolvedGroup is a group of an environment with the packages it selects, without its optional packages
+type ResolvedGroup struct {
+	Group    PackageGroup `json:"group"`
+	Optional bool         `json:"optional"` // Whether the group is from the optionlist
+	Selected bool         `json:"selected"` // Whether installing the environment installs the group
+	Packages []Package    `json:"packages"`
+}
+
+// ResolveGroup returns the packages that installing the group would install, the way dnf group install
+// selects them. Packages listed in the group that are not in the repository are skipped.
+func (r *Repository) ResolveGroup(ctx context.Context, groupID string, includeOptional bool) ([]Package, int, error) {
+	groups, _, err := r.PackageGroups(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	group, ok := findGroup(groups, groupID)
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %v", ErrGroupNotFound, groupID)
+	}
+
+	packages, code, err := r.Packages(ctx)
+	var truncated *TruncatedError
+	if err != nil && !errors.As(err, &truncated) {
+		return nil, code, err
+	}
+	return GroupPackages(group, packages, includeOptional), code, err
+}
+
+// ResolveEnvironment expands the environment into its groups and the packages that installing it would
+// install, the way dnf group install selects them: the groups of the grouplist and the default groups of the
+// optionlist, without their optional packages.
+func (r *Repository) ResolveEnvironment(ctx context.Context, envID string) (*EnvironmentResolution, int, error) {
+	environments, _, err := r.Environments(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	var environment *Environment
+	for i := range environments {
+		if environments[i].ID == envID {
+			environment = &environments[i]
+			break
+		}
+	}
+	if environment == nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrEnvironmentNotFound, envID)
+	}
+
+	groups, _, err := r.PackageGroups(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	packages, code, err := r.Packages(ctx)
+	var truncated *TruncatedError
+	if err != nil && !errors.As(err, &truncated) {
+		return nil, code, err
+	}
+	return ExpandEnvironment(*environment, groups, packages), code, err
+}
+
+// ResolveModuleProfile returns the packages that installing the profile of a module stream would install:
+// the binary artifacts of the latest version of the stream named by the profile. Modular filtering does not
+// apply, as installing a profile enables its stream.
+func (r *Repository) ResolveModuleProfile(ctx context.Context, module, stream, profile string) ([]Package, int, error) {
+	moduleMDs, _, err := r.ModuleMDs(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	packages, code, err := r.fetchPackages(ctx)
+	var truncated *TruncatedError
+	if err != nil && !errors.As(err, &truncated) {
+		return nil, code, err
+	}
+	profilePackages, profileErr := ProfilePackages(moduleMDs, packages, module, stream, profile)
+	if profileErr != nil {
+		return nil, code, profileErr
+	}
+	return profilePackages, code, err
+}
+
+// ProfilePackages returns the binary packages of packages that are artifacts of the latest version of a
+// module stream and named by its profile. Of several contexts of the latest version, the first one is used.
+func ProfilePackages(moduleMDs []ModuleMD, packages []Package, module, stream, profile string) ([]Package, error) {
+	var latest *ModuleMD
+	for i, moduleMD := range moduleMDs {
+		if moduleMD.Data.Name != module || moduleMD.Data.Stream != stream {
+			continue
+		}
+		if latest == nil || compareNumericStrings(moduleMD.Data.Version, latest.Data.Version) > 0 {
+			latest = &moduleMDs[i]
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w: %v:%v", ErrModuleStreamNotFound, module, stream)
+	}
+	rpms, ok := latest.Data.Profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v:%v/%v", ErrProfileNotFound, module, stream, profile)
+	}
+
+	artifacts := ModuleStreamPackages([]ModuleMD{*latest}, packages)[0].Packages
+	return selectPackages(artifacts, rpms.Rpms), nil
+}
+
+// ExpandEnvironment expands the environment into the given groups and the packages they select
+func ExpandEnvironment(environment Environment, groups []PackageGroup, packages []Package) *EnvironmentResolution {
+	resolution := &EnvironmentResolution{
+		Environment:   environment,
+		Groups:        []ResolvedGroup{},
+		MissingGroups: []string{},
+		Packages:      []Package{},
+	}
+	resolve := func(groupID string, optional bool, selected bool) {
+		group, ok := findGroup(groups, groupID)
+		if !ok {
+			resolution.MissingGroups = append(resolution.MissingGroups, groupID)
+			return
+		}
+		resolution.Groups = append(resolution.Groups, ResolvedGroup{
+			Group:    group,
+			Optional: optional,
+			Selected: selected,
+			Packages: GroupPackages(group, packages, false),
+		})
+	}
+	for _, groupID := range environment.GroupList {
+		resolve(groupID, false, true)
+	}
+	for _, option := range environment.OptionList {
+		resolve(option.GroupID, true, option.Default)
+	}
+
+	seen := map[NEVRA]bool{}
+	for _, group := range resolution.Groups {
+		if !group.Selected {
+			continue
+		}
+		for _, pkg := range group.Packages {
+			if nevra := pkg.NEVRA(); !seen[nevra] {
+				seen[nevra] = true
+				resolution.Packages = append(resolution.Packages, pkg)
+			}
+		}
+	}
+	return resolution
+}
+
+// GroupPackages returns the latest build of each architecture of the binary packages of packages that are
+// selected by the group, as dnf group install would install them. Mandatory and default packages are always
+// selected, optional packages if includeOptional is set, and conditional packages if the package they
+// require is selected.
+func GroupPackages(group PackageGroup, packages []Package, includeOptional bool) []Package {
+	return selectPackages(packages, GroupPackageNames(group, includeOptional))
+}
+
+// GroupPackageNames returns the names of the packages selected by the group, in the order of its package
+// list. Conditional packages are selected if the package they require is selected by the group.
+func GroupPackageNames(group PackageGroup, includeOptional bool) []string {
+	selected := map[string]bool{}
+	for _, req := range group.PackageList {
+		switch req.Type {
+		case PackageReqMandatory, PackageReqDefault, "":
+			selected[req.Name] = true
+		case PackageReqOptional:
+			selected[req.Name] = selected[req.Name] || includeOptional
+		}
+	}
+	// a conditional package may require another conditional package
+	for changed := true; changed; {
+		changed = false
+		for _, req := range group.PackageList {
+			if req.Type == PackageReqConditional && !selected[req.Name] && selected[req.Requires] {
+				selected[req.Name] = true
+				changed = true
+			}
+		}
+	}
+
+	names := []string{}
+	for _, req := range group.PackageList {
+		if selected[req.Name] {
+			names = append(names, req.Name)
+			delete(selected, req.Name)
+		}
+	}
+	return names
+}
+
+// selectPackages returns the latest build of each architecture of the binary packages of packages with the
+// given names, as dnf installs them, in the order of names
+func selectPackages(packages []Package, names []string) []Package {
+	latest := map[nameArch]int{}
+	byName := map[string][]nameArch{}
+	for i, pkg := range packages {
+		if pkg.IsSource() {
+			continue
+		}
+		key := nameArch{pkg.Name, pkg.Arch}
+		current, ok := latest[key]
+		if !ok {
+			byName[pkg.Name] = append(byName[pkg.Name], key)
+		}
+		if !ok || CompareEVR(pkg.NEVRA(), packages[current].NEVRA()) > 0 {
+			latest[key] = i
+		}
+	}
+
+	result := []Package{}
+	for _, name := range names {
+		for _, key := range byName[name] {
+			result = append(result, packages[latest[key]])
+		}
+		delete(byName, name)
+	}
+	return result
+}
+
+func findGroup(groups []PackageGroup, groupID string) (PackageGroup, bool) {
+	for _, group := range groups {
+		if group.ID == groupID {
+			return group, true
+		}
+	}
+	return PackageGroup{}, false
+}
@@ -0,0 +1,176 @@
+package yum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var resolveGroup = PackageGroup{
+	ID: "tools",
+	PackageList: []PackageReq{
+		{Name: "make", Type: PackageReqMandatory},
+		{Name: "gcc", Type: PackageReqDefault},
+		{Name: "gdb", Type: PackageReqOptional},
+		{Name: "gcc-gfortran", Type: PackageReqConditional, Requires: "gcc"},
+		{Name: "gdb-doc", Type: PackageReqConditional, Requires: "gdb"},
+		{Name: "missing", Type: PackageReqMandatory},
+	},
+}
+
+func TestGroupPackageNames(t *testing.T) {
+	assert.Equal(t, []string{"make", "gcc", "gcc-gfortran", "missing"}, GroupPackageNames(resolveGroup, false))
+	assert.Equal(t, []string{"make", "gcc", "gdb", "gcc-gfortran", "gdb-doc", "missing"}, GroupPackageNames(resolveGroup, true))
+
+	chained := PackageGroup{PackageList: []PackageReq{
+		{Name: "c", Type: PackageReqConditional, Requires: "b"},
+		{Name: "b", Type: PackageReqConditional, Requires: "a"},
+		{Name: "a", Type: PackageReqMandatory},
+		{Name: "a", Type: PackageReqDefault},
+	}}
+	assert.Equal(t, []string{"c", "b", "a"}, GroupPackageNames(chained, false))
+}
+
+func TestGroupPackages(t *testing.T) {
+	packages := []Package{
+		{Name: "gcc", Arch: "x86_64"},
+		{Name: "make", Arch: "x86_64"},
+		{Name: "make", Arch: "src"},
+		{Name: "gdb", Arch: "x86_64"},
+		{Name: "gcc", Arch: "i686"},
+	}
+	resolved := GroupPackages(resolveGroup, packages, false)
+	assert.Equal(t, []Package{packages[1], packages[0], packages[4]}, resolved)
+
+	// only the latest build of each architecture is installed
+	versioned := []Package{
+		{Name: "make", Arch: "x86_64", Version: Version{Version: "4.3", Release: "7.el9"}},
+		{Name: "make", Arch: "x86_64", Version: Version{Version: "4.3", Release: "8.el9"}},
+		{Name: "make", Arch: "x86_64", Version: Version{Version: "4.2", Release: "9.el9"}},
+		{Name: "make", Arch: "i686", Version: Version{Version: "4.3", Release: "7.el9"}},
+	}
+	resolved = GroupPackages(resolveGroup, versioned, false)
+	assert.Equal(t, []Package{versioned[1], versioned[3]}, resolved)
+}
+
+func TestResolveGroup(t *testing.T) {
+	s := server()
+	defer s.Close()
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+
+	// nss-devel is conditional on tpm-quote-tools, which the group does not select
+	packages, _, err := r.ResolveGroup(context.Background(), "base-x", true)
+	require.NoError(t, err)
+	assert.Empty(t, packages)
+
+	_, _, err = r.ResolveGroup(context.Background(), "missing", false)
+	assert.ErrorIs(t, err, ErrGroupNotFound)
+}
+
+func TestExpandEnvironment(t *testing.T) {
+	environment := Environment{
+		ID:        "workstation",
+		GroupList: []string{"tools", "base", "unknown"},
+		OptionList: []EnvironmentOption{
+			{GroupID: "debugging", Default: true},
+			{GroupID: "docs"},
+		},
+	}
+	groups := []PackageGroup{
+		resolveGroup,
+		{ID: "base", PackageList: []PackageReq{{Name: "bash"}, {Name: "make", Type: PackageReqMandatory}}},
+		{ID: "debugging", PackageList: []PackageReq{{Name: "gdb", Type: PackageReqDefault}}},
+		{ID: "docs", PackageList: []PackageReq{{Name: "man-pages", Type: PackageReqDefault}}},
+	}
+	packages := []Package{
+		{Name: "bash", Arch: "x86_64"},
+		{Name: "make", Arch: "x86_64"},
+		{Name: "gcc", Arch: "x86_64"},
+		{Name: "gdb", Arch: "x86_64"},
+		{Name: "man-pages", Arch: "noarch"},
+	}
+
+	resolution := ExpandEnvironment(environment, groups, packages)
+	assert.Equal(t, environment, resolution.Environment)
+	assert.Equal(t, []string{"unknown"}, resolution.MissingGroups)
+	require.Len(t, resolution.Groups, 4)
+	assert.Equal(t, "tools", resolution.Groups[0].Group.ID)
+	assert.Equal(t, []Package{packages[1], packages[2]}, resolution.Groups[0].Packages)
+	assert.True(t, resolution.Groups[2].Optional)
+	assert.True(t, resolution.Groups[2].Selected)
+	assert.False(t, resolution.Groups[3].Selected)
+	assert.Equal(t, []Package{packages[1], packages[2], packages[0], packages[3]}, resolution.Packages)
+}
+
+func TestResolveEnvironment(t *testing.T) {
+	s := server()
+	defer s.Close()
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+
+	resolution, _, err := r.ResolveEnvironment(context.Background(), "kde-desktop-environment")
+	require.NoError(t, err)
+	require.Len(t, resolution.Groups, 2)
+	assert.Equal(t, "base-x", resolution.Groups[0].Group.ID)
+	assert.Equal(t, "fonts", resolution.Groups[1].Group.ID)
+	assert.Len(t, resolution.MissingGroups, 12)
+	assert.Empty(t, resolution.Packages)
+
+	_, _, err = r.ResolveEnvironment(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrEnvironmentNotFound)
+}
+
+func TestProfilePackages(t *testing.T) {
+	packages := []Package{
+		{Name: "nodejs", Arch: "x86_64", Version: Version{Version: "18.1", Release: "1", Epoch: 1}},
+		{Name: "nodejs", Arch: "src", Version: Version{Version: "18.1", Release: "1", Epoch: 1}},
+		{Name: "npm", Arch: "x86_64", Version: Version{Version: "18.1", Release: "1", Epoch: 1}},
+		{Name: "nodejs-devel", Arch: "x86_64", Version: Version{Version: "18.1", Release: "1", Epoch: 1}},
+		{Name: "nodejs", Arch: "x86_64", Version: Version{Version: "18.0", Release: "1", Epoch: 1}},
+		{Name: "npm", Arch: "x86_64", Version: Version{Version: "1.0", Release: "1"}},
+	}
+	profiles := map[string]RpmProfiles{
+		"common":      {Rpms: []string{"nodejs", "npm"}},
+		"development": {Rpms: []string{"nodejs", "nodejs-devel", "npm"}},
+	}
+	moduleMDs := []ModuleMD{
+		{Document: "modulemd", Data: Stream{Name: "nodejs", Stream: "18", Version: "9", Profiles: profiles, Artifacts: Artifacts{Rpms: []string{
+			"nodejs-1:18.0-1.x86_64",
+		}}}},
+		{Document: "modulemd", Data: Stream{Name: "nodejs", Stream: "18", Version: "10", Profiles: profiles, Artifacts: Artifacts{Rpms: []string{
+			"nodejs-1:18.1-1.x86_64", "nodejs-1:18.1-1.src", "npm-1:18.1-1.x86_64", "nodejs-devel-1:18.1-1.x86_64",
+		}}}},
+	}
+
+	resolved, err := ProfilePackages(moduleMDs, packages, "nodejs", "18", "common")
+	require.NoError(t, err)
+	assert.Equal(t, []Package{packages[0], packages[2]}, resolved)
+
+	resolved, err = ProfilePackages(moduleMDs, packages, "nodejs", "18", "development")
+	require.NoError(t, err)
+	assert.Equal(t, []Package{packages[0], packages[3], packages[2]}, resolved)
+
+	_, err = ProfilePackages(moduleMDs, packages, "nodejs", "18", "minimal")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+
+	_, err = ProfilePackages(moduleMDs, packages, "nodejs", "20", "common")
+	assert.ErrorIs(t, err, ErrModuleStreamNotFound)
+}
+
+func TestResolveModuleProfile(t *testing.T) {
+	s := server()
+	defer s.Close()
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+
+	// the artifacts of the mock module streams are not in the mock primary.xml
+	packages, _, err := r.ResolveModuleProfile(context.Background(), "nodejs", "8", "default")
+	require.NoError(t, err)
+	assert.Empty(t, packages)
+
+	_, _, err = r.ResolveModuleProfile(context.Background(), "nodejs", "8", "missing")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+
+	_, _, err = r.ResolveModuleProfile(context.Background(), "nodejs", "99", "default")
+	assert.ErrorIs(t, err, ErrModuleStreamNotFound)
+}
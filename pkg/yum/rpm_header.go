@@ -0,0 +1,201 @@
+package yum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	rpmLeadMagic   = []byte{0xed, 0xab, 0xee, 0xdb}
+	rpmHeaderMagic = []byte{0x8e, 0xad, 0xe8, 0x01}
+)
+
+const (
+	rpmLeadSize             = 96
+	rpmIndexEntrySize       = 16
+	rpmMaxHeaderSize        = 256 << 20
+	rpmMaxHeaderTags        = 1 << 16
+	rpmTagName              = 1000
+	rpmTagVersion           = 1001
+	rpmTagRelease           = 1002
+	rpmTagEpoch             = 1003
+	rpmTagSummary           = 1004
+	rpmTagLicense           = 1014
+	rpmTagArch              = 1022
+	rpmTagSourceRPM         = 1044
+	rpmTagPayloadDigest     = 5092
+	rpmTagPayloadDigestAlgo = 5093
+	rpmTypeInt32            = 4
+	rpmTypeString           = 6
+	rpmTypeStringArray      = 8
+	rpmTypeI18NString       = 9
+)
+
+// rpmDigestAlgorithms maps the OpenPGP hash algorithm ids used by RPM to checksum types
+var rpmDigestAlgorithms = map[int32]string{
+	1:  "md5",
+	2:  "sha1",
+	8:  "sha256",
+	9:  "sha384",
+	10: "sha512",
+	11: "sha224",
+}
+
+// RPMHeader holds the fields of an RPM file's header used to check it against repository metadata
+type RPMHeader struct {
+	NEVRA         NEVRA    `json:"nevra"`
+	Summary       string   `json:"summary"`
+	License       string   `json:"license"`
+	SourceRPM     string   `json:"sourceRpm"`     // empty for source packages
+	PayloadDigest Checksum `json:"payloadDigest"` // digest of the compressed payload, empty for packages built without one
+	HeaderStart   int64    `json:"headerStart"`   // offset of the main header in the file, as in primary.xml's header-range
+	HeaderEnd     int64    `json:"headerEnd"`     // offset of the end of the main header, and start of the payload
+}
+
+// MatchesPackage returns true if the header describes the package with the same NEVRA
+func (h RPMHeader) MatchesPackage(pkg Package) bool {
+	return h.NEVRA == pkg.NEVRA()
+}
+
+// ReadRPMHeaderFile reads the header of the RPM file at path
+func ReadRPMHeaderFile(path string) (*RPMHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadRPMHeader(f)
+}
+
+// ReadRPMHeader reads the lead, signature and main header of an RPM from a stream. Only the headers
+// are read, leaving reader positioned at the start of the payload.
+func ReadRPMHeader(reader io.Reader) (*RPMHeader, error) {
+	lead := make([]byte, rpmLeadSize)
+	if _, err := io.ReadFull(reader, lead); err != nil {
+		return nil, fmt.Errorf("error reading RPM lead: %w", err)
+	}
+	if !bytes.Equal(lead[:4], rpmLeadMagic) {
+		return nil, fmt.Errorf("not an RPM file")
+	}
+
+	offset := int64(rpmLeadSize)
+	signatureSize, _, err := readRPMHeaderSection(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading RPM signature header: %w", err)
+	}
+	offset += signatureSize
+	// the main header is aligned to 8 bytes
+	if padding := (8 - offset%8) % 8; padding > 0 {
+		if _, err = io.CopyN(io.Discard, reader, padding); err != nil {
+			return nil, fmt.Errorf("error reading RPM signature header: %w", err)
+		}
+		offset += padding
+	}
+
+	headerSize, tags, err := readRPMHeaderSection(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading RPM header: %w", err)
+	}
+
+	header := RPMHeader{
+		NEVRA: NEVRA{
+			Name:    tags.string(rpmTagName),
+			Epoch:   tags.int32(rpmTagEpoch),
+			Version: tags.string(rpmTagVersion),
+			Release: tags.string(rpmTagRelease),
+			Arch:    tags.string(rpmTagArch),
+		},
+		Summary:     tags.string(rpmTagSummary),
+		License:     tags.string(rpmTagLicense),
+		SourceRPM:   tags.string(rpmTagSourceRPM),
+		HeaderStart: offset,
+		HeaderEnd:   offset + headerSize,
+	}
+	if header.SourceRPM == "" {
+		header.NEVRA.Arch = "src"
+	}
+	if digest := tags.string(rpmTagPayloadDigest); digest != "" {
+		algorithm, ok := rpmDigestAlgorithms[tags.int32(rpmTagPayloadDigestAlgo)]
+		if !ok {
+			// RPM defaults to sha256 when no algorithm is recorded
+			algorithm = "sha256"
+		}
+		header.PayloadDigest = Checksum{Type: algorithm, Value: digest}
+	}
+	return &header, nil
+}
+
+// rpmTags maps the tags of a header to their type and raw data
+type rpmTags map[int32]rpmTag
+
+type rpmTag struct {
+	Type uint32
+	Data []byte
+}
+
+// string returns the first string of a string, string array or i18n string tag
+func (t rpmTags) string(tag int32) string {
+	value, ok := t[tag]
+	if !ok {
+		return ""
+	}
+	switch value.Type {
+	case rpmTypeString, rpmTypeStringArray, rpmTypeI18NString:
+		s, _, _ := strings.Cut(string(value.Data), "\x00")
+		return s
+	}
+	return ""
+}
+
+// int32 returns the first value of an int32 tag
+func (t rpmTags) int32(tag int32) int32 {
+	value, ok := t[tag]
+	if !ok || value.Type != rpmTypeInt32 || len(value.Data) < 4 {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(value.Data))
+}
+
+// readRPMHeaderSection reads a header structure, returning its size in bytes and its tags
+func readRPMHeaderSection(reader io.Reader) (int64, rpmTags, error) {
+	intro := make([]byte, 16)
+	if _, err := io.ReadFull(reader, intro); err != nil {
+		return 0, nil, err
+	}
+	if !bytes.Equal(intro[:4], rpmHeaderMagic) {
+		return 0, nil, fmt.Errorf("bad header magic")
+	}
+	indexCount := binary.BigEndian.Uint32(intro[8:12])
+	storeSize := binary.BigEndian.Uint32(intro[12:16])
+	if indexCount > rpmMaxHeaderTags || storeSize > rpmMaxHeaderSize {
+		return 0, nil, fmt.Errorf("header too large: %d tags, %d bytes", indexCount, storeSize)
+	}
+
+	index := make([]byte, indexCount*rpmIndexEntrySize)
+	if _, err := io.ReadFull(reader, index); err != nil {
+		return 0, nil, err
+	}
+	store := make([]byte, storeSize)
+	if _, err := io.ReadFull(reader, store); err != nil {
+		return 0, nil, err
+	}
+
+	tags := rpmTags{}
+	for i := uint32(0); i < indexCount; i++ {
+		entry := index[i*rpmIndexEntrySize:]
+		tag := int32(binary.BigEndian.Uint32(entry[0:4]))
+		tagType := binary.BigEndian.Uint32(entry[4:8])
+		offset := binary.BigEndian.Uint32(entry[8:12])
+		if offset > storeSize {
+			return 0, nil, fmt.Errorf("tag %d out of bounds", tag)
+		}
+		tags[tag] = rpmTag{Type: tagType, Data: store[offset:]}
+	}
+
+	size := int64(len(intro)) + int64(len(index)) + int64(storeSize)
+	return size, tags, nil
+}
@@ -0,0 +1,101 @@
+package yum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rpmHeaderEntry is a tag written by buildRPMHeaderSection
+type rpmHeaderEntry struct {
+	tag     int32
+	tagType uint32
+	data    []byte
+}
+
+func rpmString(tag int32, s string) rpmHeaderEntry {
+	return rpmHeaderEntry{tag: tag, tagType: rpmTypeString, data: append([]byte(s), 0)}
+}
+
+func rpmInt32(tag int32, i int32) rpmHeaderEntry {
+	return rpmHeaderEntry{tag: tag, tagType: rpmTypeInt32, data: binary.BigEndian.AppendUint32(nil, uint32(i))}
+}
+
+func buildRPMHeaderSection(entries ...rpmHeaderEntry) []byte {
+	var index, store bytes.Buffer
+	for _, entry := range entries {
+		_ = binary.Write(&index, binary.BigEndian, []uint32{uint32(entry.tag), entry.tagType, uint32(store.Len()), 1})
+		store.Write(entry.data)
+	}
+	section := append([]byte{}, rpmHeaderMagic...)
+	section = append(section, 0, 0, 0, 0)
+	section = binary.BigEndian.AppendUint32(section, uint32(len(entries)))
+	section = binary.BigEndian.AppendUint32(section, uint32(store.Len()))
+	section = append(section, index.Bytes()...)
+	return append(section, store.Bytes()...)
+}
+
+// buildRPM returns an RPM with the given main header entries followed by a payload
+func buildRPM(entries ...rpmHeaderEntry) []byte {
+	rpm := make([]byte, rpmLeadSize)
+	copy(rpm, rpmLeadMagic)
+	rpm = append(rpm, buildRPMHeaderSection(rpmString(1000, "abc"))...)
+	for len(rpm)%8 != 0 {
+		rpm = append(rpm, 0)
+	}
+	rpm = append(rpm, buildRPMHeaderSection(entries...)...)
+	return append(rpm, []byte("payload")...)
+}
+
+func TestReadRPMHeader(t *testing.T) {
+	rpm := buildRPM(
+		rpmString(rpmTagName, "foo"),
+		rpmInt32(rpmTagEpoch, 1),
+		rpmString(rpmTagVersion, "1.0"),
+		rpmString(rpmTagRelease, "2.el9"),
+		rpmString(rpmTagArch, "x86_64"),
+		rpmHeaderEntry{tag: rpmTagSummary, tagType: rpmTypeI18NString, data: []byte("Foo tool\x00")},
+		rpmString(rpmTagLicense, "MIT"),
+		rpmString(rpmTagSourceRPM, "foo-1.0-2.el9.src.rpm"),
+		rpmHeaderEntry{tag: rpmTagPayloadDigest, tagType: rpmTypeStringArray, data: []byte("abcdef\x00")},
+		rpmInt32(rpmTagPayloadDigestAlgo, 8),
+	)
+
+	reader := bytes.NewReader(rpm)
+	header, err := ReadRPMHeader(reader)
+	require.NoError(t, err)
+	assert.Equal(t, NEVRA{Name: "foo", Epoch: 1, Version: "1.0", Release: "2.el9", Arch: "x86_64"}, header.NEVRA)
+	assert.Equal(t, "Foo tool", header.Summary)
+	assert.Equal(t, "MIT", header.License)
+	assert.Equal(t, Checksum{Type: "sha256", Value: "abcdef"}, header.PayloadDigest)
+	assert.Equal(t, int64(len(rpm)-len("payload")), header.HeaderEnd)
+	assert.Zero(t, header.HeaderStart%8)
+
+	// only the headers are consumed
+	payload, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(payload))
+
+	assert.True(t, header.MatchesPackage(Package{Name: "foo", Arch: "x86_64", Version: Version{Epoch: 1, Version: "1.0", Release: "2.el9"}}))
+	assert.False(t, header.MatchesPackage(Package{Name: "foo", Arch: "x86_64", Version: Version{Version: "1.0", Release: "2.el9"}}))
+}
+
+func TestReadRPMHeaderFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.src.rpm")
+	require.NoError(t, os.WriteFile(path, buildRPM(rpmString(rpmTagName, "foo"), rpmString(rpmTagArch, "x86_64")), 0644))
+
+	header, err := ReadRPMHeaderFile(path)
+	require.NoError(t, err)
+	// source packages have no source rpm
+	assert.Equal(t, "src", header.NEVRA.Arch)
+	assert.Empty(t, header.PayloadDigest.Value)
+
+	_, err = ReadRPMHeader(bytes.NewReader(primaryXML))
+	assert.Error(t, err)
+}
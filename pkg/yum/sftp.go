@@ -0,0 +1,572 @@
+package yum
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTP version 3 packet types and status codes, as OpenSSH implements them
+const (
+	sftpProtocolVersion = 3
+
+	sftpInit    = 1
+	sftpVersion = 2
+	sftpOpen    = 3
+	sftpClose   = 4
+	sftpRead    = 5
+	sftpFstat   = 8
+	sftpStatus  = 101
+	sftpHandle  = 102
+	sftpData    = 103
+	sftpAttrs   = 105
+
+	sftpOK               = 0
+	sftpEOF              = 1
+	sftpNoSuchFile       = 2
+	sftpPermissionDenied = 3
+
+	sftpOpenRead      = 0x1
+	sftpAttrSize      = 0x1
+	sftpAttrUIDGID    = 0x2
+	sftpAttrPerms     = 0x4
+	sftpAttrTimes     = 0x8
+	sftpMaxReadLength = 32 << 10
+	sftpMaxPacketSize = 256 << 10
+)
+
+// sftpRangeRegex matches the open ended ranges sent to resume downloads
+var sftpRangeRegex = regexp.MustCompile(`^bytes=(\d+)-$`)
+
+// SSHHop is an SSH server connections are made through, such as a jump host
+type SSHHop struct {
+	Address string            // host:port, port 22 if omitted
+	Config  *ssh.ClientConfig // SFTPClient.Config if nil
+}
+
+// SFTPClient is an HTTPDoer that serves repositories from SSH servers over SFTP, for repositories only reachable
+// over SSH, possibly through jump hosts. Set it as YummySettings.Client with a URL of the form
+// sftp://[user@]host[:port]/path: requests for sftp URLs are served from the files of the server, and other
+// requests are sent as they are. Connections are reused across requests until Close is called.
+type SFTPClient struct {
+	Client    HTTPDoer          // Sends the requests for other URLs, http.DefaultClient if nil
+	Config    *ssh.ClientConfig // Authenticates to the server, with its user replaced by the user of the URL if any
+	JumpHosts []SSHHop          // Servers to connect through, in order, as with ssh -J
+
+	mu    sync.Mutex
+	conns map[string]*sftpConn // Connections by user and address
+}
+
+// Do sends req, serving GET and HEAD requests for sftp://host/path URLs from the file at path on host
+func (c *SFTPClient) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "sftp" {
+		client := c.Client
+		if isNilClient(client) {
+			client = http.DefaultClient
+		}
+		return client.Do(req)
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return sftpResponse(req, http.StatusMethodNotAllowed, http.Header{}, http.NoBody, 0), nil
+	}
+
+	conn, handle, err := c.open(req.Context(), req.URL)
+	var status *sftpStatusError
+	if errors.As(err, &status) {
+		switch status.code {
+		case sftpNoSuchFile:
+			return sftpResponse(req, http.StatusNotFound, http.Header{}, http.NoBody, 0), nil
+		case sftpPermissionDenied:
+			return sftpResponse(req, http.StatusForbidden, http.Header{}, http.NoBody, 0), nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening %v: %w", req.URL.Redacted(), err)
+	}
+
+	size, modified, err := conn.fstat(req.Context(), handle)
+	if err != nil {
+		_ = conn.close(req.Context(), handle)
+		return nil, fmt.Errorf("error reading attributes of %v: %w", req.URL.Redacted(), err)
+	}
+	header := http.Header{}
+	if !modified.IsZero() {
+		header.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	code, offset := http.StatusOK, int64(0)
+	if match := sftpRangeRegex.FindStringSubmatch(req.Header.Get("Range")); match != nil && size >= 0 {
+		start, err := strconv.ParseInt(match[1], 10, 64)
+		if err == nil && start < size {
+			code, offset = http.StatusPartialContent, start
+			header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, size-1, size))
+		}
+	}
+	length := int64(-1)
+	if size >= 0 {
+		length = size - offset
+		header.Set("Content-Length", strconv.FormatInt(length, 10))
+	}
+
+	if req.Method == http.MethodHead {
+		_ = conn.close(req.Context(), handle)
+		return sftpResponse(req, code, header, http.NoBody, length), nil
+	}
+	file := &sftpFile{ctx: req.Context(), conn: conn, handle: handle, offset: offset}
+	return sftpResponse(req, code, header, file, length), nil
+}
+
+// Close closes the connections of the client
+func (c *SFTPClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for key, conn := range c.conns {
+		errs = append(errs, conn.Close())
+		delete(c.conns, key)
+	}
+	return errors.Join(errs...)
+}
+
+// open opens the file of an sftp URL, connecting to its server if there is no open connection to it. A cached
+// connection the server closed is replaced once.
+func (c *SFTPClient) open(ctx context.Context, u *url.URL) (*sftpConn, string, error) {
+	if c.Config == nil {
+		return nil, "", errors.New("SFTPClient has no SSH client config")
+	}
+	config := *c.Config
+	if user := u.User.Username(); user != "" {
+		config.User = user
+	}
+	address := sshAddress(u.Host)
+	key := config.User + "@" + address
+
+	c.mu.Lock()
+	conn, cached := c.conns[key]
+	c.mu.Unlock()
+	if cached {
+		handle, err := conn.open(ctx, u.Path)
+		if err == nil || conn.broken() == nil {
+			return conn, handle, err
+		}
+		c.drop(key, conn)
+	}
+
+	conn, err := c.dial(ctx, address, &config)
+	if err != nil {
+		return nil, "", err
+	}
+	c.mu.Lock()
+	if c.conns == nil {
+		c.conns = map[string]*sftpConn{}
+	}
+	c.conns[key] = conn
+	c.mu.Unlock()
+
+	handle, err := conn.open(ctx, u.Path)
+	return conn, handle, err
+}
+
+// drop closes a broken connection and removes it from the cache
+func (c *SFTPClient) drop(key string, conn *sftpConn) {
+	c.mu.Lock()
+	if c.conns[key] == conn {
+		delete(c.conns, key)
+	}
+	c.mu.Unlock()
+	_ = conn.Close()
+}
+
+// dial connects to address through the jump hosts and starts an SFTP session
+func (c *SFTPClient) dial(ctx context.Context, address string, config *ssh.ClientConfig) (conn *sftpConn, err error) {
+	var closers []io.Closer
+	defer func() {
+		if err != nil {
+			for i := len(closers) - 1; i >= 0; i-- {
+				_ = closers[i].Close()
+			}
+		}
+	}()
+
+	hops := append(append([]SSHHop{}, c.JumpHosts...), SSHHop{Address: address, Config: config})
+	var client *ssh.Client
+	for _, hop := range hops {
+		hopAddress := sshAddress(hop.Address)
+		hopConfig := hop.Config
+		if hopConfig == nil {
+			hopConfig = c.Config
+		}
+
+		var netConn net.Conn
+		if client == nil {
+			netConn, err = (&net.Dialer{}).DialContext(ctx, "tcp", hopAddress)
+		} else {
+			netConn, err = client.DialContext(ctx, "tcp", hopAddress)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to %v: %w", hopAddress, err)
+		}
+		sshConn, channels, requests, err := ssh.NewClientConn(netConn, hopAddress, hopConfig)
+		if err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("error connecting to %v: %w", hopAddress, err)
+		}
+		client = ssh.NewClient(sshConn, channels, requests)
+		closers = append(closers, client)
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("error opening SSH session to %v: %w", address, err)
+	}
+	closers = append(closers, session)
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = session.RequestSubsystem("sftp"); err != nil {
+		return nil, fmt.Errorf("error starting SFTP subsystem on %v: %w", address, err)
+	}
+
+	// the session is closed to interrupt the version exchange if ctx is done first
+	stop := context.AfterFunc(ctx, func() { _ = session.Close() })
+	conn, err = newSFTPConn(stdout, stdin)
+	if !stop() {
+		err = cmp.Or(err, ctx.Err())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error starting SFTP session on %v: %w", address, err)
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		conn.closers = append(conn.closers, closers[i])
+	}
+	return conn, nil
+}
+
+// sshAddress adds the default SSH port to an address without one
+func sshAddress(address string) string {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return net.JoinHostPort(address, "22")
+	}
+	return address
+}
+
+// sftpResponse returns a response to req served from an SFTP server
+func sftpResponse(req *http.Request, code int, header http.Header, body io.ReadCloser, length int64) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %v", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          body,
+		ContentLength: length,
+		Request:       req,
+	}
+}
+
+// sftpStatusError is an SSH_FXP_STATUS response other than SSH_FX_OK
+type sftpStatusError struct {
+	code    uint32
+	message string
+}
+
+func (e *sftpStatusError) Error() string {
+	return fmt.Sprintf("SFTP status %d: %v", e.code, e.message)
+}
+
+// sftpConn is an SFTP version 3 session. Requests are sent one at a time, so that files can be read
+// concurrently over one connection. A request whose context is done before its response arrives closes the
+// session, as the response can no longer be told apart from the responses to later requests, and the client
+// connects again for the next request.
+//
+// Only the open, fstat, read and close requests of reading a file are implemented, rather than depending on
+// github.com/pkg/sftp: they are a small part of its client, which would add a dependency and its own
+// dependencies to every user of the module, for the few repositories served over SSH.
+type sftpConn struct {
+	turn    chan struct{} // Held by the request being sent, so that waiting for it honors the context
+	mu      sync.Mutex
+	r       *bufio.Reader
+	w       io.Writer
+	id      uint32
+	err     error       // Error that broke the session
+	closers []io.Closer // SSH session and clients, innermost first
+}
+
+// newSFTPConn starts an SFTP session over r and w, the stdout and stdin of the sftp subsystem
+func newSFTPConn(r io.Reader, w io.Writer) (*sftpConn, error) {
+	c := &sftpConn{turn: make(chan struct{}, 1), r: bufio.NewReader(r), w: w}
+	if err := c.writePacket(sftpInit, binary.BigEndian.AppendUint32(nil, sftpProtocolVersion)); err != nil {
+		return nil, err
+	}
+	packetType, payload, err := c.readPacket()
+	if err != nil {
+		return nil, err
+	}
+	if packetType != sftpVersion || len(payload) < 4 {
+		return nil, fmt.Errorf("unexpected SFTP packet %d instead of version", packetType)
+	}
+	if version := binary.BigEndian.Uint32(payload); version != sftpProtocolVersion {
+		return nil, fmt.Errorf("unsupported SFTP version %d", version)
+	}
+	return c, nil
+}
+
+// Close closes the SSH session and connections of c
+func (c *sftpConn) Close() error {
+	var errs []error
+	for _, closer := range c.closers {
+		if err := closer.Close(); err != nil && !errors.Is(err, io.EOF) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// broken returns the error that broke the session, if any
+func (c *sftpConn) broken() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// writePacket writes a packet of packetType with payload
+func (c *sftpConn) writePacket(packetType byte, payload []byte) error {
+	packet := binary.BigEndian.AppendUint32(make([]byte, 0, 5+len(payload)), uint32(1+len(payload)))
+	packet = append(append(packet, packetType), payload...)
+	_, err := c.w.Write(packet)
+	return err
+}
+
+// readPacket reads a packet and returns its type and payload
+func (c *sftpConn) readPacket() (byte, []byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(c.r, length[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size == 0 || size > sftpMaxPacketSize {
+		return 0, nil, fmt.Errorf("invalid SFTP packet length %d", size)
+	}
+	packet := make([]byte, size)
+	if _, err := io.ReadFull(c.r, packet); err != nil {
+		return 0, nil, err
+	}
+	return packet[0], packet[1:], nil
+}
+
+// request sends a request of packetType and returns the response to it, waiting for the requests sent
+// before it. If ctx is done before the response arrives, the session is closed. An SSH_FXP_STATUS response
+// other than SSH_FX_OK is returned as an *sftpStatusError.
+func (c *sftpConn) request(ctx context.Context, packetType byte, payload []byte) (byte, *sftpReader, error) {
+	select {
+	case c.turn <- struct{}{}:
+	case <-ctx.Done():
+		return 0, nil, ctx.Err()
+	}
+	defer func() { <-c.turn }()
+	if err := c.broken(); err != nil {
+		return 0, nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	c.id++
+	id := c.id
+	stop := context.AfterFunc(ctx, func() { _ = c.Close() })
+	responseType, response, err := func() (byte, []byte, error) {
+		if err := c.writePacket(packetType, append(binary.BigEndian.AppendUint32(nil, id), payload...)); err != nil {
+			return 0, nil, err
+		}
+		return c.readPacket()
+	}()
+	if !stop() {
+		err = ctx.Err()
+	}
+	if err == nil && (len(response) < 4 || binary.BigEndian.Uint32(response) != id) {
+		err = fmt.Errorf("unexpected SFTP response to request %d", id)
+	}
+	if err != nil {
+		c.mu.Lock()
+		c.err = fmt.Errorf("SFTP session closed: %w", err)
+		c.mu.Unlock()
+		return 0, nil, c.broken()
+	}
+
+	reader := &sftpReader{data: response[4:]}
+	if responseType == sftpStatus {
+		code, message := reader.uint32(), reader.string()
+		if reader.err != nil {
+			return 0, nil, reader.err
+		}
+		if code != sftpOK {
+			return 0, nil, &sftpStatusError{code: code, message: message}
+		}
+	}
+	return responseType, reader, nil
+}
+
+// open opens a file for reading and returns its handle
+func (c *sftpConn) open(ctx context.Context, path string) (string, error) {
+	payload := appendSFTPString(nil, path)
+	payload = binary.BigEndian.AppendUint32(payload, sftpOpenRead)
+	payload = binary.BigEndian.AppendUint32(payload, 0) // no attributes
+	responseType, reader, err := c.request(ctx, sftpOpen, payload)
+	if err != nil {
+		return "", err
+	}
+	if responseType != sftpHandle {
+		return "", fmt.Errorf("unexpected SFTP response %d to open", responseType)
+	}
+	handle := reader.string()
+	return handle, reader.err
+}
+
+// fstat returns the size of an open file, -1 if the server did not return it, and the time it was modified
+func (c *sftpConn) fstat(ctx context.Context, handle string) (int64, time.Time, error) {
+	responseType, reader, err := c.request(ctx, sftpFstat, appendSFTPString(nil, handle))
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if responseType != sftpAttrs {
+		return 0, time.Time{}, fmt.Errorf("unexpected SFTP response %d to fstat", responseType)
+	}
+
+	size, modified := int64(-1), time.Time{}
+	flags := reader.uint32()
+	if flags&sftpAttrSize != 0 {
+		size = int64(reader.uint64())
+	}
+	if flags&sftpAttrUIDGID != 0 {
+		reader.uint32()
+		reader.uint32()
+	}
+	if flags&sftpAttrPerms != 0 {
+		reader.uint32()
+	}
+	if flags&sftpAttrTimes != 0 {
+		reader.uint32() // atime
+		modified = time.Unix(int64(reader.uint32()), 0)
+	}
+	return size, modified, reader.err
+}
+
+// read reads up to length bytes of an open file at offset, returning io.EOF at the end of the file
+func (c *sftpConn) read(ctx context.Context, handle string, offset uint64, length uint32) ([]byte, error) {
+	payload := appendSFTPString(nil, handle)
+	payload = binary.BigEndian.AppendUint64(payload, offset)
+	payload = binary.BigEndian.AppendUint32(payload, length)
+	responseType, reader, err := c.request(ctx, sftpRead, payload)
+	var status *sftpStatusError
+	if errors.As(err, &status) && status.code == sftpEOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if responseType != sftpData {
+		return nil, fmt.Errorf("unexpected SFTP response %d to read", responseType)
+	}
+	data := reader.string()
+	return []byte(data), reader.err
+}
+
+// close closes an open file
+func (c *sftpConn) close(ctx context.Context, handle string) error {
+	_, _, err := c.request(ctx, sftpClose, appendSFTPString(nil, handle))
+	return err
+}
+
+// sftpFile reads an open file from its offset, as the body of a response to a request with context ctx
+type sftpFile struct {
+	ctx    context.Context
+	conn   *sftpConn
+	handle string
+	offset int64
+	closed bool
+}
+
+func (f *sftpFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, errors.New("read of closed SFTP file")
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	data, err := f.conn.read(f.ctx, f.handle, uint64(f.offset), uint32(min(len(p), sftpMaxReadLength)))
+	if err != nil {
+		return 0, err
+	}
+	n := copy(p, data)
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *sftpFile) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	return f.conn.close(f.ctx, f.handle)
+}
+
+// appendSFTPString appends a length prefixed string
+func appendSFTPString(b []byte, s string) []byte {
+	return append(binary.BigEndian.AppendUint32(b, uint32(len(s))), s...)
+}
+
+// sftpReader reads the fields of a packet, recording the first error
+type sftpReader struct {
+	data []byte
+	err  error
+}
+
+func (r *sftpReader) uint32() uint32 {
+	if len(r.data) < 4 {
+		r.err = cmp.Or(r.err, io.ErrUnexpectedEOF)
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return v
+}
+
+func (r *sftpReader) uint64() uint64 {
+	if len(r.data) < 8 {
+		r.err = cmp.Or(r.err, io.ErrUnexpectedEOF)
+		return 0
+	}
+	v := binary.BigEndian.Uint64(r.data)
+	r.data = r.data[8:]
+	return v
+}
+
+func (r *sftpReader) string() string {
+	length := r.uint32()
+	if uint64(len(r.data)) < uint64(length) {
+		r.err = cmp.Or(r.err, io.ErrUnexpectedEOF)
+		return ""
+	}
+	s := string(r.data[:length])
+	r.data = r.data[length:]
+	return s
+}
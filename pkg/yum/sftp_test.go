@@ -0,0 +1,278 @@
+package yum
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshServer runs an SSH server accepting the password "secret", serving files over the sftp subsystem and
+// forwarding direct-tcpip channels, as jump hosts do. It returns its address and host key.
+func sshServer(t *testing.T, files map[string][]byte) (string, ssh.PublicKey) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if string(password) != "secret" {
+				return nil, io.EOF
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			netConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, channels, requests, err := ssh.NewServerConn(netConn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(requests)
+				for newChannel := range channels {
+					switch newChannel.ChannelType() {
+					case "session":
+						go serveSFTPSession(newChannel, files)
+					case "direct-tcpip":
+						go forwardChannel(newChannel)
+					default:
+						_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), signer.PublicKey()
+}
+
+func forwardChannel(newChannel ssh.NewChannel) {
+	var target struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	conn, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	go func() {
+		_, _ = io.Copy(conn, channel)
+		conn.Close()
+	}()
+	_, _ = io.Copy(channel, conn)
+	channel.Close()
+}
+
+func serveSFTPSession(newChannel ssh.NewChannel, files map[string][]byte) {
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		return
+	}
+	defer channel.Close()
+	for req := range requests {
+		ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+		_ = req.Reply(ok, nil)
+		if ok {
+			go ssh.DiscardRequests(requests)
+			serveSFTP(channel, files)
+			return
+		}
+	}
+}
+
+// serveSFTP answers the SFTP requests of sftpConn from files
+func serveSFTP(rw io.ReadWriter, files map[string][]byte) {
+	conn := &sftpConn{r: bufio.NewReader(rw), w: rw}
+	handles := map[string]string{}
+	status := func(id, code uint32) []byte {
+		payload := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, id), code)
+		return appendSFTPString(appendSFTPString(payload, "status"), "")
+	}
+	for {
+		packetType, payload, err := conn.readPacket()
+		if err != nil {
+			return
+		}
+		if packetType == sftpInit {
+			_ = conn.writePacket(sftpVersion, binary.BigEndian.AppendUint32(nil, sftpProtocolVersion))
+			continue
+		}
+
+		reader := &sftpReader{data: payload}
+		id := reader.uint32()
+		responseType, response := byte(sftpStatus), status(id, sftpOK)
+		switch packetType {
+		case sftpOpen:
+			name := reader.string()
+			if _, ok := files[name]; ok {
+				handle := strconv.Itoa(len(handles))
+				handles[handle] = name
+				responseType, response = sftpHandle, appendSFTPString(binary.BigEndian.AppendUint32(nil, id), handle)
+			} else {
+				response = status(id, sftpNoSuchFile)
+			}
+		case sftpFstat:
+			content := files[handles[reader.string()]]
+			response = binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, id), sftpAttrSize)
+			responseType, response = sftpAttrs, binary.BigEndian.AppendUint64(response, uint64(len(content)))
+		case sftpRead:
+			content := files[handles[reader.string()]]
+			offset, length := reader.uint64(), reader.uint32()
+			if offset >= uint64(len(content)) {
+				response = status(id, sftpEOF)
+			} else {
+				data := content[offset:min(offset+uint64(length), uint64(len(content)))]
+				responseType, response = sftpData, appendSFTPString(binary.BigEndian.AppendUint32(nil, id), string(data))
+			}
+		case sftpClose:
+			delete(handles, reader.string())
+		}
+		if conn.writePacket(responseType, response) != nil {
+			return
+		}
+	}
+}
+
+func TestSFTPClient(t *testing.T) {
+	files := map[string][]byte{
+		"/srv/epel/repodata/repomd.xml":      repomdXML,
+		"/srv/epel/repodata/primary.xml.gz":  primaryXML,
+		"/srv/epel/repodata/comps.xml":       compsXML,
+		"/srv/epel/repodata/module.yaml.zst": moduleYamlZst,
+	}
+	address, hostKey := sshServer(t, files)
+
+	client := &SFTPClient{Config: &ssh.ClientConfig{
+		User:            "mirror",
+		Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+	}}
+	defer client.Close()
+	r, _ := NewRepository(YummySettings{Client: client, URL: Ptr("sftp://" + address + "/srv/epel")})
+
+	packages, code, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Len(t, packages, 2)
+
+	groups, _, err := r.PackageGroups(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, groups)
+	assert.Len(t, client.conns, 1)
+
+	_, code, err = r.Signature(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// ranges resume downloads
+	req, _ := http.NewRequest(http.MethodGet, "sftp://"+address+"/srv/epel/repodata/repomd.xml", nil)
+	req.Header.Set("Range", "bytes=10-")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, repomdXML[10:], body)
+
+	client.Config.Auth = []ssh.AuthMethod{ssh.Password("wrong")}
+	require.NoError(t, client.Close())
+	r.Clear()
+	_, _, err = r.Repomd(context.Background())
+	assert.Error(t, err)
+}
+
+func TestSFTPClientJumpHost(t *testing.T) {
+	address, hostKey := sshServer(t, map[string][]byte{"/srv/epel/repodata/repomd.xml": repomdXML})
+	jumpAddress, jumpHostKey := sshServer(t, nil)
+
+	client := &SFTPClient{
+		Config: &ssh.ClientConfig{
+			Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+			HostKeyCallback: ssh.FixedHostKey(hostKey),
+		},
+		JumpHosts: []SSHHop{{Address: jumpAddress, Config: &ssh.ClientConfig{
+			User:            "jump",
+			Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+			HostKeyCallback: ssh.FixedHostKey(jumpHostKey),
+		}}},
+	}
+	defer client.Close()
+	r, _ := NewRepository(YummySettings{Client: client, URL: Ptr("sftp://mirror@" + address + "/srv/epel")})
+
+	repomd, code, err := r.Repomd(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.NotEmpty(t, repomd.Data)
+}
+
+func TestSFTPConnContext(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	// a server that starts the session, then never answers
+	go func() {
+		stalled := &sftpConn{r: bufio.NewReader(server), w: server}
+		if _, _, err := stalled.readPacket(); err != nil {
+			return
+		}
+		_ = stalled.writePacket(sftpVersion, binary.BigEndian.AppendUint32(nil, sftpProtocolVersion))
+		_, _ = io.Copy(io.Discard, server)
+	}()
+	conn, err := newSFTPConn(client, client)
+	require.NoError(t, err)
+	conn.closers = []io.Closer{client}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	waiting := make(chan error)
+	go func() {
+		// waits for the stalled request, until its own context is done
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		time.Sleep(5 * time.Millisecond)
+		_, err := conn.open(ctx, "/srv/epel/repodata/primary.xml.gz")
+		waiting <- err
+	}()
+	_, err = conn.open(ctx, "/srv/epel/repodata/repomd.xml")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, <-waiting, context.DeadlineExceeded)
+
+	// the session was closed
+	assert.ErrorIs(t, conn.broken(), context.DeadlineExceeded)
+	_, err = conn.open(context.Background(), "/srv/epel/repodata/repomd.xml")
+	assert.ErrorContains(t, err, "SFTP session closed")
+}
@@ -0,0 +1,28 @@
+package yum
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// signRequest returns a copy of req with its URL signed by the SignURL setting, or req itself if SignURL is
+// not set. req is left unsigned, so that it can be logged and traced without its token.
+func (r *Repository) signRequest(req *http.Request) (*http.Request, error) {
+	if r.settings.SignURL == nil {
+		return req, nil
+	}
+	u := *req.URL
+	if req.URL.User != nil {
+		user := *req.URL.User
+		u.User = &user
+	}
+	if err := r.settings.SignURL(req.Context(), &u); err != nil {
+		return nil, fmt.Errorf("error signing URL %v: %w", req.URL, err)
+	}
+	signed := req.Clone(req.Context())
+	signed.URL = &u
+	if req.Host == req.URL.Host {
+		signed.Host = u.Host
+	}
+	return signed, nil
+}
@@ -0,0 +1,66 @@
+package yum
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignURL(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	requested := []string{}
+	signed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sig") != "signed:"+r.URL.Path {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		requested = append(requested, r.URL.Path)
+		s.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer signed.Close()
+
+	signURL := func(ctx context.Context, u *url.URL) error {
+		query := u.Query()
+		query.Set("sig", "signed:"+u.Path)
+		u.RawQuery = query.Encode()
+		return nil
+	}
+	r, err := NewRepository(YummySettings{Client: signed.Client(), URL: &signed.URL, SignURL: signURL, GPGKeyPaths: []string{"gpgkey.pub"}})
+	require.NoError(t, err)
+
+	packages, code, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, packages, 2)
+	_, code, err = r.Signature(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	_, code, err = r.GPGKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"/repodata/repomd.xml", "/repodata/primary.xml.gz", "/repodata/repomd.xml.asc", "/gpgkey.pub"}, requested)
+	assert.Equal(t, 1, r.Stats()["gpgkey"].Fetches)
+}
+
+func TestSignURLError(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	errSign := errors.New("no credentials")
+	r, _ := NewRepository(YummySettings{
+		Client:  s.Client(),
+		URL:     &s.URL,
+		SignURL: func(ctx context.Context, u *url.URL) error { return errSign },
+	})
+	_, code, err := r.Repomd(context.Background())
+	assert.ErrorIs(t, err, errSign)
+	assert.Equal(t, 0, code)
+}
@@ -0,0 +1,56 @@
+package yum
+
+// IsSourceArch reports whether arch is the architecture of source packages, "src", or "nosrc" for source
+// packages that leave out some of their sources
+func IsSourceArch(arch string) bool {
+	return arch == "src" || arch == "nosrc"
+}
+
+// IsSource reports whether the package is a source package
+func (p Package) IsSource() bool {
+	return IsSourceArch(p.Arch)
+}
+
+// SourceNEVRA returns the NEVRA of the source package the package was built from, parsed from its sourcerpm,
+// such as "bash-5.1.8-6.el9.src.rpm". The epoch is not part of a sourcerpm and is zero. Returns false for
+// source packages and packages whose sourcerpm is missing or invalid.
+func (p Package) SourceNEVRA() (NEVRA, bool) {
+	if p.IsSource() || p.SourceRPM == "" {
+		return NEVRA{}, false
+	}
+	nevra, err := ParseNEVRA(p.SourceRPM)
+	if err != nil || !IsSourceArch(nevra.Arch) {
+		return NEVRA{}, false
+	}
+	return nevra, true
+}
+
+// SourceName returns the name of the source package the package was built from, its own name for a source
+// package, and an empty string if it has no valid sourcerpm
+func (p Package) SourceName() string {
+	if p.IsSource() {
+		return p.Name
+	}
+	nevra, _ := p.SourceNEVRA()
+	return nevra.Name
+}
+
+// FilterSourcePackages returns the source packages of packages
+func FilterSourcePackages(packages []Package) []Package {
+	return filterPackages(packages, true)
+}
+
+// FilterBinaryPackages returns the packages of packages that are not source packages
+func FilterBinaryPackages(packages []Package) []Package {
+	return filterPackages(packages, false)
+}
+
+func filterPackages(packages []Package, source bool) []Package {
+	filtered := []Package{}
+	for _, pkg := range packages {
+		if pkg.IsSource() == source {
+			filtered = append(filtered, pkg)
+		}
+	}
+	return filtered
+}
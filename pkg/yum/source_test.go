@@ -0,0 +1,35 @@
+package yum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourcePackages(t *testing.T) {
+	binary := Package{Name: "bash-doc", Arch: "noarch", SourceRPM: "bash-5.1.8-6.el9.src.rpm"}
+	source := Package{Name: "bash", Arch: "src"}
+	nosrc := Package{Name: "firmware", Arch: "nosrc"}
+	invalid := Package{Name: "broken", Arch: "x86_64", SourceRPM: "broken.rpm"}
+
+	assert.False(t, binary.IsSource())
+	assert.True(t, source.IsSource())
+	assert.True(t, nosrc.IsSource())
+
+	nevra, ok := binary.SourceNEVRA()
+	assert.True(t, ok)
+	assert.Equal(t, NEVRA{Name: "bash", Version: "5.1.8", Release: "6.el9", Arch: "src"}, nevra)
+	_, ok = source.SourceNEVRA()
+	assert.False(t, ok)
+	_, ok = invalid.SourceNEVRA()
+	assert.False(t, ok)
+
+	assert.Equal(t, "bash", binary.SourceName())
+	assert.Equal(t, "bash", source.SourceName())
+	assert.Empty(t, invalid.SourceName())
+
+	packages := []Package{binary, source, nosrc, invalid}
+	assert.Equal(t, []Package{source, nosrc}, FilterSourcePackages(packages))
+	assert.Equal(t, []Package{binary, invalid}, FilterBinaryPackages(packages))
+	assert.Empty(t, FilterSourcePackages(nil))
+}
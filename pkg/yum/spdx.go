@@ -0,0 +1,194 @@
+package yum
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SBOMOptions describe the repository a software bill of materials is generated for
+type SBOMOptions struct {
+	// Name of the document, such as the name of the repository
+	Name string
+	// RepositoryURL is the URL of the repository, used to give the download location of each package
+	RepositoryURL string
+	// Distro is the vendor of the packages, such as "fedora" or "redhat", used as the namespace of
+	// package URLs
+	Distro string
+	// Namespace is the unique URI of an SPDX document, generated from Name if empty
+	Namespace string
+	// Created is the creation time of the document, the current time if zero
+	Created time.Time
+}
+
+// spdxNoAssertion is the SPDX value for information that was not determined
+const spdxNoAssertion = "NOASSERTION"
+
+// spdxAlgorithms maps checksum types to SPDX checksum algorithms
+var spdxAlgorithms = map[string]string{
+	"md5":    "MD5",
+	"sha":    "SHA1",
+	"sha1":   "SHA1",
+	"sha224": "SHA224",
+	"sha256": "SHA256",
+	"sha384": "SHA384",
+	"sha512": "SHA512",
+}
+
+// spdxInvalidIDChars matches the characters not allowed in SPDX identifiers
+var spdxInvalidIDChars = regexp.MustCompile(`[^A-Za-z0-9.-]`)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Summary          string            `json:"summary,omitempty"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// WriteSPDX writes an SPDX 2.3 JSON document describing packages to w, with the checksum, license and
+// source RPM of each package, so that compliance tooling can consume repository snapshots.
+// Licenses are declared as given by the packages when they are SPDX expressions, as recent distributions
+// write them, and as NOASSERTION otherwise, such as for the legacy license strings of older distributions.
+func WriteSPDX(w io.Writer, packages []Package, opts SBOMOptions) error {
+	created := opts.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	namespace := opts.Namespace
+	if namespace == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return err
+		}
+		namespace = fmt.Sprintf("https://spdx.org/spdxdocs/%v-%v", url.PathEscape(opts.Name), hex.EncodeToString(id))
+	}
+
+	document := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              opts.Name,
+		DocumentNamespace: namespace,
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: yummy"},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	ids := map[string]bool{}
+	for _, pkg := range packages {
+		id := "SPDXRef-Package-" + spdxInvalidIDChars.ReplaceAllString(pkg.NEVRA().String(), "-")
+		for i := 2; ids[id]; i++ {
+			id = fmt.Sprintf("SPDXRef-Package-%v-%d", spdxInvalidIDChars.ReplaceAllString(pkg.NEVRA().String(), "-"), i)
+		}
+		ids[id] = true
+
+		spdxPkg := spdxPackage{
+			SPDXID:           id,
+			Name:             pkg.Name,
+			VersionInfo:      evrString(pkg.Version),
+			DownloadLocation: packageDownloadLocation(opts.RepositoryURL, pkg),
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			Summary:          pkg.Summary,
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  PackageURL{Distro: opts.Distro, NEVRA: pkg.NEVRA(), RepositoryURL: opts.RepositoryURL}.String(),
+			}},
+		}
+		if isSPDXExpression(pkg.License) {
+			spdxPkg.LicenseDeclared = pkg.License
+		}
+		if pkg.SourceRPM != "" {
+			spdxPkg.SourceInfo = "built package from: " + pkg.SourceRPM
+		}
+		if algorithm, ok := spdxAlgorithms[strings.ToLower(pkg.Checksum.Type)]; ok {
+			spdxPkg.Checksums = []spdxChecksum{{Algorithm: algorithm, ChecksumValue: pkg.Checksum.Value}}
+		}
+		document.Packages = append(document.Packages, spdxPkg)
+		document.Relationships = append(document.Relationships, spdxRelationship{
+			SPDXElementID:      document.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: id,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// evrString formats a version as [epoch:]version-release, omitting a zero epoch
+func evrString(version Version) string {
+	if version.Epoch != 0 {
+		return fmt.Sprintf("%d:%v-%v", version.Epoch, version.Version, version.Release)
+	}
+	return version.Version + "-" + version.Release
+}
+
+// packageDownloadLocation returns the URL of the package in the repository at repositoryURL, or at the
+// xml:base of its location, or NOASSERTION if the location is unknown
+func packageDownloadLocation(repositoryURL string, pkg Package) string {
+	if pkg.Location.Base != "" {
+		repositoryURL = pkg.Location.Base
+	}
+	if repositoryURL == "" || pkg.Location.Href == "" {
+		return spdxNoAssertion
+	}
+	location, err := joinURL(repositoryURL, pkg.Location.Href)
+	if err != nil {
+		return spdxNoAssertion
+	}
+	return location
+}
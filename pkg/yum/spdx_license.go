@@ -0,0 +1,121 @@
+package yum
+
+import (
+	"regexp"
+	"strings"
+)
+
+// spdxLicenseIDs are the SPDX license identifiers recognized in the licenses of packages, the licenses
+// common in distributions, lower cased as identifiers match case insensitively. Other identifiers are not
+// declared, so that legacy license strings such as "GPLv2+" are not mistaken for SPDX expressions.
+var spdxLicenseIDs = lowerSet(
+	"0BSD", "AFL-2.1", "AFL-3.0", "AGPL-1.0-only", "AGPL-1.0-or-later", "AGPL-3.0", "AGPL-3.0-only",
+	"AGPL-3.0-or-later", "Apache-1.0", "Apache-1.1", "Apache-2.0", "APSL-2.0", "Artistic-1.0",
+	"Artistic-1.0-Perl", "Artistic-2.0", "Beerware", "Bitstream-Vera", "BSD-1-Clause", "BSD-2-Clause",
+	"BSD-2-Clause-Patent", "BSD-3-Clause", "BSD-3-Clause-Clear", "BSD-4-Clause", "BSD-4-Clause-UC",
+	"BSD-Source-Code", "BSL-1.0", "bzip2-1.0.6", "CC-BY-3.0", "CC-BY-4.0", "CC-BY-SA-3.0", "CC-BY-SA-4.0",
+	"CC0-1.0", "CDDL-1.0", "CDDL-1.1", "CPL-1.0", "curl", "EPL-1.0", "EPL-2.0", "EUPL-1.1", "EUPL-1.2",
+	"FSFAP", "FSFUL", "FSFULLR", "FTL", "GFDL-1.1-only", "GFDL-1.1-or-later", "GFDL-1.2-only",
+	"GFDL-1.2-or-later", "GFDL-1.3-only", "GFDL-1.3-or-later", "GPL-1.0-only", "GPL-1.0-or-later", "GPL-2.0",
+	"GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0", "GPL-3.0-only", "GPL-3.0-or-later", "HPND", "IJG",
+	"ImageMagick", "Info-ZIP", "ISC", "LGPL-2.0", "LGPL-2.0-only", "LGPL-2.0-or-later", "LGPL-2.1",
+	"LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-3.0", "LGPL-3.0-only", "LGPL-3.0-or-later", "Libpng",
+	"libpng-2.0", "libtiff", "LPPL-1.3c", "MirOS", "MIT", "MIT-0", "MIT-CMU", "MPL-1.0", "MPL-1.1",
+	"MPL-2.0", "MPL-2.0-no-copyleft-exception", "MS-PL", "MS-RL", "NCSA", "NTP", "OFL-1.0", "OFL-1.1",
+	"OLDAP-2.8", "OpenSSL", "PHP-3.0", "PHP-3.01", "PostgreSQL", "PSF-2.0", "Python-2.0", "Ruby",
+	"Sendmail", "SGI-B-2.0", "SISSL", "Sleepycat", "Spencer-94", "Spencer-99", "TCL", "TCP-wrappers",
+	"Unicode-DFS-2016", "Unlicense", "UPL-1.0", "Vim", "W3C", "WTFPL", "X11", "XFree86-1.1", "Zlib",
+	"zlib-acknowledgement", "ZPL-2.0", "ZPL-2.1",
+)
+
+// spdxExceptionIDs are the SPDX license exception identifiers recognized after WITH, lower cased
+var spdxExceptionIDs = lowerSet(
+	"389-exception", "Autoconf-exception-2.0", "Autoconf-exception-3.0", "Bison-exception-2.2",
+	"Classpath-exception-2.0", "eCos-exception-2.0", "FLTK-exception", "Font-exception-2.0",
+	"GCC-exception-2.0", "GCC-exception-3.1", "GPL-3.0-linking-exception", "Libtool-exception",
+	"Linux-syscall-note", "LLVM-exception", "OpenJDK-assembly-exception-1.0", "openvpn-openssl-exception",
+	"Qt-GPL-exception-1.0", "Qt-LGPL-exception-1.1", "u-boot-exception-2.0", "WxWindows-exception-3.1",
+)
+
+// spdxLicenseRef matches the identifiers of licenses that are not on the SPDX license list
+var spdxLicenseRef = regexp.MustCompile(`^(DocumentRef-[A-Za-z0-9.-]+:)?LicenseRef-[A-Za-z0-9.-]+$`)
+
+func lowerSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[strings.ToLower(value)] = true
+	}
+	return set
+}
+
+// isSPDXExpression returns true if license is an SPDX license expression of recognized identifiers, such as
+// "GPL-2.0-or-later AND (MIT OR Apache-2.0)"
+func isSPDXExpression(license string) bool {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(license))
+	parser := spdxParser{tokens: tokens}
+	return len(tokens) > 0 && parser.compound() && parser.pos == len(tokens)
+}
+
+// spdxParser is a recursive descent parser of the tokens of an SPDX license expression
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *spdxParser) next() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// compound parses an expression of terms joined by OR
+func (p *spdxParser) compound() bool {
+	if !p.term() {
+		return false
+	}
+	for p.next() == "OR" {
+		p.pos++
+		if !p.term() {
+			return false
+		}
+	}
+	return true
+}
+
+// term parses licenses joined by AND, which binds tighter than OR
+func (p *spdxParser) term() bool {
+	if !p.license() {
+		return false
+	}
+	for p.next() == "AND" {
+		p.pos++
+		if !p.license() {
+			return false
+		}
+	}
+	return true
+}
+
+// license parses a parenthesized expression, or a license identifier with an optional exception
+func (p *spdxParser) license() bool {
+	token := p.next()
+	p.pos++
+	if token == "(" {
+		if !p.compound() || p.next() != ")" {
+			return false
+		}
+		p.pos++
+		return true
+	}
+	if !spdxLicenseRef.MatchString(token) && !spdxLicenseIDs[strings.ToLower(strings.TrimSuffix(token, "+"))] {
+		return false
+	}
+	if p.next() == "WITH" {
+		p.pos++
+		exception := p.next()
+		p.pos++
+		return spdxExceptionIDs[strings.ToLower(exception)]
+	}
+	return true
+}
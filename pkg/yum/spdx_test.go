@@ -0,0 +1,105 @@
+package yum
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSPDX(t *testing.T) {
+	packages := []Package{
+		{
+			Name:      "nss-devel",
+			Arch:      "i686",
+			Version:   Version{Version: "3.19.1", Release: "18.el7"},
+			Checksum:  Checksum{Type: "sha256", Value: "abcd"},
+			Summary:   "Development libraries for Network Security Services",
+			Location:  Location{Href: "Packages/n/nss-devel-3.19.1-18.el7.i686.rpm"},
+			License:   "MPL-2.0",
+			SourceRPM: "nss-3.19.1-18.el7.src.rpm",
+		},
+		{Name: "foo", Arch: "x86_64", Version: Version{Epoch: 1, Version: "1.0", Release: "1"}, Checksum: Checksum{Type: "sha", Value: "ef01"}},
+		{Name: "bash", Arch: "x86_64", Version: Version{Version: "4.4.20", Release: "4.el8"}, License: "GPLv3+"},
+	}
+	var out bytes.Buffer
+	require.NoError(t, WriteSPDX(&out, packages, SBOMOptions{
+		Name:          "epel7",
+		RepositoryURL: "https://example.com/epel/7/",
+		Created:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}))
+
+	var document spdxDocument
+	require.NoError(t, json.Unmarshal(out.Bytes(), &document))
+	assert.Equal(t, "SPDX-2.3", document.SPDXVersion)
+	assert.Equal(t, "2024-01-02T03:04:05Z", document.CreationInfo.Created)
+	assert.Contains(t, document.DocumentNamespace, "https://spdx.org/spdxdocs/epel7-")
+	require.Len(t, document.Packages, 3)
+	assert.Equal(t, spdxPackage{
+		SPDXID:           "SPDXRef-Package-nss-devel-0-3.19.1-18.el7.i686",
+		Name:             "nss-devel",
+		VersionInfo:      "3.19.1-18.el7",
+		DownloadLocation: "https://example.com/epel/7/Packages/n/nss-devel-3.19.1-18.el7.i686.rpm",
+		LicenseConcluded: "NOASSERTION",
+		LicenseDeclared:  "MPL-2.0",
+		CopyrightText:    "NOASSERTION",
+		Summary:          "Development libraries for Network Security Services",
+		SourceInfo:       "built package from: nss-3.19.1-18.el7.src.rpm",
+		Checksums:        []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: "abcd"}},
+		ExternalRefs: []spdxExternalRef{{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  "pkg:rpm/nss-devel@3.19.1-18.el7?arch=i686&repository_url=https%3A%2F%2Fexample.com%2Fepel%2F7%2F",
+		}},
+	}, document.Packages[0])
+	assert.Equal(t, "1:1.0-1", document.Packages[1].VersionInfo)
+	assert.Equal(t, "NOASSERTION", document.Packages[1].DownloadLocation)
+	assert.Equal(t, "NOASSERTION", document.Packages[1].LicenseDeclared)
+	// legacy license strings are not SPDX expressions
+	assert.Equal(t, "NOASSERTION", document.Packages[2].LicenseDeclared)
+	assert.Equal(t, []spdxChecksum{{Algorithm: "SHA1", ChecksumValue: "ef01"}}, document.Packages[1].Checksums)
+	assert.Equal(t, spdxRelationship{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: document.Packages[1].SPDXID}, document.Relationships[1])
+}
+
+func TestIsSPDXExpression(t *testing.T) {
+	for _, license := range []string{
+		"MIT",
+		"mit",
+		"GPL-2.0-or-later",
+		"GPL-2.0+",
+		"LGPL-2.1-or-later AND (MIT OR Apache-2.0)",
+		"GPL-3.0-or-later WITH GCC-exception-3.1",
+		"((BSD-3-Clause))",
+		"LicenseRef-Fedora-Public-Domain",
+	} {
+		assert.True(t, isSPDXExpression(license), license)
+	}
+	for _, license := range []string{
+		"",
+		"GPLv2+",
+		"GPLv2 and LGPLv2+",
+		"MIT and BSD",
+		"Public Domain",
+		"MIT OR",
+		"(MIT",
+		"MIT)",
+		"GPL-2.0-only WITH MIT",
+	} {
+		assert.False(t, isSPDXExpression(license), license)
+	}
+}
+
+func TestPackageDownloadLocation(t *testing.T) {
+	pkg := Package{Location: Location{Href: "Packages/f/foo.rpm"}}
+	assert.Equal(t, "https://example.com/repo/Packages/f/foo.rpm", packageDownloadLocation("https://example.com/repo/", pkg))
+	assert.Equal(t, "NOASSERTION", packageDownloadLocation("", pkg))
+
+	pkg.Location.Base = "https://cdn.example.com/"
+	assert.Equal(t, "https://cdn.example.com/Packages/f/foo.rpm", packageDownloadLocation("https://example.com/repo/", pkg))
+
+	pkg.Location.Href = "https://mirror.example.com/foo.rpm"
+	assert.Equal(t, "https://mirror.example.com/foo.rpm", packageDownloadLocation("https://example.com/repo/", pkg))
+}
@@ -0,0 +1,145 @@
+package yum
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SpilledPackages holds packages in a temporary file instead of memory, for repositories too large to keep
+// their packages in a slice. Packages are read back in the order of primary.xml with Iterate. Close removes
+// the file.
+type SpilledPackages struct {
+	path  string
+	count int
+}
+
+// SpillPackages fetches primary.xml like Packages, writing the packages to a temporary file in dir, or the
+// default directory for temporary files if dir is empty, as they are decoded. Modular filtering does not
+// apply, and the packages are not cached. MaxPackages applies as it does to Packages. Returns response code
+// and error.
+func (r *Repository) SpillPackages(ctx context.Context, dir string) (*SpilledPackages, int, error) {
+	reader, resp, err := r.getPrimary(ctx)
+	if err != nil {
+		return nil, erroredStatusCode(resp), err
+	}
+	defer resp.Body.Close()
+
+	spilled, err := spillPackagesXML(reader, *r.settings.MaxXmlSize, r.settings.MaxPackages, dir)
+	if spilled == nil {
+		return nil, resp.StatusCode, err
+	}
+	r.parsed("primary", spilled.Len())
+	return spilled, resp.StatusCode, err
+}
+
+// SpillCompressedXMLData decodes a compressed primary.xml like ParseCompressedXMLData, writing the packages
+// to a temporary file in dir instead of returning them in a slice
+func SpillCompressedXMLData(body io.Reader, maxSize int64, dir string) (*SpilledPackages, error) {
+	reader, err := ParseCompressedData(body)
+	if err != nil {
+		return nil, fmt.Errorf("error unzipping response body: %w", err)
+	}
+	return spillPackagesXML(reader, maxSize, 0, dir)
+}
+
+// spillPackagesXML spills the packages of an uncompressed primary.xml. If there are more than maxPackages,
+// the packages spilled so far are returned with a *TruncatedError.
+func spillPackagesXML(reader io.Reader, maxSize int64, maxPackages int, dir string) (*SpilledPackages, error) {
+	file, err := os.CreateTemp(dir, "yummy-packages-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating spill file: %w", err)
+	}
+	spilled := &SpilledPackages{path: file.Name()}
+
+	writer := bufio.NewWriter(file)
+	encoder := gob.NewEncoder(writer)
+	decodeErr := decodePackagesXML(reader, maxSize, maxPackages, func(int) {}, func(pkg Package) error {
+		spilled.count++
+		return encoder.Encode(&pkg)
+	})
+	var truncated *TruncatedError
+	if decodeErr == nil || errors.As(decodeErr, &truncated) {
+		err = writer.Flush()
+	} else {
+		err = decodeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(spilled.path)
+		return nil, fmt.Errorf("error spilling packages: %w", err)
+	}
+	return spilled, decodeErr
+}
+
+// Len returns the number of spilled packages
+func (s *SpilledPackages) Len() int {
+	return s.count
+}
+
+// Iterate returns an iterator over the spilled packages, which must be closed. Several iterators can be used
+// at the same time.
+func (s *SpilledPackages) Iterate() (*PackageIterator, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening spill file: %w", err)
+	}
+	return &PackageIterator{file: file, decoder: gob.NewDecoder(bufio.NewReader(file)), remaining: s.count}, nil
+}
+
+// Close removes the spill file
+func (s *SpilledPackages) Close() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// PackageIterator reads spilled packages one at a time, in the manner of bufio.Scanner:
+//
+//	for it.Next() {
+//		pkg := it.Package()
+//	}
+//	if err := it.Err(); err != nil {
+type PackageIterator struct {
+	file      *os.File
+	decoder   *gob.Decoder
+	remaining int
+	pkg       Package
+	err       error
+}
+
+// Next reads the next package, returning false at the end of the packages or on error
+func (it *PackageIterator) Next() bool {
+	if it.err != nil || it.remaining == 0 {
+		return false
+	}
+	it.pkg = Package{}
+	if err := it.decoder.Decode(&it.pkg); err != nil {
+		it.err = fmt.Errorf("error reading spill file: %w", err)
+		return false
+	}
+	it.remaining--
+	return true
+}
+
+// Package returns the package read by the last call to Next
+func (it *PackageIterator) Package() Package {
+	return it.pkg
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *PackageIterator) Err() error {
+	return it.err
+}
+
+// Close closes the spill file
+func (it *PackageIterator) Close() error {
+	return it.file.Close()
+}
@@ -0,0 +1,96 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpillPackages(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	expected, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	r.Clear()
+
+	dir := t.TempDir()
+	spilled, code, err := r.SpillPackages(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Nil(t, r.packages, "spilled packages are not cached")
+	assert.Equal(t, 2, spilled.Len())
+
+	it, err := spilled.Iterate()
+	require.NoError(t, err)
+	packages := []Package{}
+	for it.Next() {
+		packages = append(packages, it.Package())
+	}
+	assert.NoError(t, it.Err())
+	assert.NoError(t, it.Close())
+	assert.Equal(t, expected, packages)
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+	assert.NoError(t, spilled.Close())
+	entries, _ = os.ReadDir(dir)
+	assert.Empty(t, entries)
+}
+
+func TestSpillPackagesMaxPackages(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, MaxPackages: 1})
+	spilled, _, err := r.SpillPackages(context.Background(), t.TempDir())
+	var truncated *TruncatedError
+	assert.ErrorAs(t, err, &truncated)
+	require.NotNil(t, spilled)
+	defer spilled.Close()
+	assert.Equal(t, 1, spilled.Len())
+}
+
+func TestSpillCompressedXMLData(t *testing.T) {
+	data := benchmarkPrimaryXML(t, 1000)
+	expected, err := ParseCompressedXMLData(bytes.NewReader(data), DefaultMaxXmlSize)
+	require.NoError(t, err)
+
+	spilled, err := SpillCompressedXMLData(bytes.NewReader(data), DefaultMaxXmlSize, t.TempDir())
+	require.NoError(t, err)
+	defer spilled.Close()
+	assert.Equal(t, 1000, spilled.Len())
+
+	// iterators are independent
+	first, err := spilled.Iterate()
+	require.NoError(t, err)
+	defer first.Close()
+	second, err := spilled.Iterate()
+	require.NoError(t, err)
+	defer second.Close()
+	for i := range expected {
+		require.True(t, first.Next())
+		require.True(t, second.Next())
+		assert.Equal(t, expected[i], first.Package())
+		assert.Equal(t, expected[i], second.Package())
+	}
+	assert.False(t, first.Next())
+	assert.NoError(t, first.Err())
+}
+
+func TestSpillCompressedXMLDataError(t *testing.T) {
+	xmlFile, err := os.Open("mocks/primary.xml.gz")
+	require.NoError(t, err)
+	defer xmlFile.Close()
+
+	dir := t.TempDir()
+	_, err = SpillCompressedXMLData(xmlFile, 200, dir)
+	assert.Error(t, err)
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries, "the spill file is removed")
+}
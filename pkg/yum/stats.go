@@ -0,0 +1,120 @@
+package yum
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// OperationStats are the statistics of the requests for one type of metadata, such as "primary", or for
+// package downloads
+type OperationStats struct {
+	Fetches           int           `json:"fetches"`           // Requests sent
+	Errors            int           `json:"errors"`            // Requests that failed without a response
+	StatusCodes       map[int]int   `json:"statusCodes"`       // Number of responses by status code
+	Duration          time.Duration `json:"duration"`          // Total duration of the fetches, until the response bodies were closed
+	LastDuration      time.Duration `json:"lastDuration"`      // Duration of the last fetch
+	DownloadedBytes   int64         `json:"downloadedBytes"`   // Bytes of response bodies read
+	DecompressedBytes int64         `json:"decompressedBytes"` // Bytes of metadata read after decompression
+	CacheHits         int           `json:"cacheHits"`         // Requests for metadata served from the repository's cache
+	CacheMisses       int           `json:"cacheMisses"`       // Requests for metadata that had to be fetched
+	Items             int           `json:"items"`             // Items parsed, such as packages or advisories
+}
+
+// Stats are the statistics of the requests of a repository since it was created, by metadata type
+type Stats map[string]OperationStats
+
+// stats collects the Stats of a repository, as an observer of its requests
+type stats struct {
+	mu         sync.Mutex
+	operations map[string]*OperationStats
+}
+
+func newStats() *stats {
+	return &stats{operations: map[string]*OperationStats{}}
+}
+
+// update calls f with the statistics of metadataType, holding the lock
+func (s *stats) update(metadataType string, f func(*OperationStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	operation, ok := s.operations[metadataType]
+	if !ok {
+		operation = &OperationStats{StatusCodes: map[int]int{}}
+		s.operations[metadataType] = operation
+	}
+	f(operation)
+}
+
+func (s *stats) fetched(metadataType string, resp *http.Response) {
+	s.update(metadataType, func(operation *OperationStats) {
+		operation.Fetches++
+		if resp == nil {
+			operation.Errors++
+		} else {
+			operation.StatusCodes[resp.StatusCode]++
+		}
+	})
+}
+
+func (s *stats) downloaded(metadataType string, n int) {
+	s.update(metadataType, func(operation *OperationStats) { operation.DownloadedBytes += int64(n) })
+}
+
+func (s *stats) finished(metadataType string, duration time.Duration) {
+	s.update(metadataType, func(operation *OperationStats) {
+		operation.LastDuration = duration
+		operation.Duration += duration
+	})
+}
+
+func (s *stats) decompressed(metadataType string, n int) {
+	s.update(metadataType, func(operation *OperationStats) { operation.DecompressedBytes += int64(n) })
+}
+
+func (s *stats) parsed(metadataType string, count int) {
+	s.update(metadataType, func(operation *OperationStats) { operation.Items += count })
+}
+
+func (s *stats) cacheRequest(metadataType string, hit bool) {
+	s.update(metadataType, func(operation *OperationStats) {
+		if hit {
+			operation.CacheHits++
+		} else {
+			operation.CacheMisses++
+		}
+	})
+}
+
+// Stats returns a copy of the statistics of the requests of the repository. Statistics are kept across
+// calls to Clear, and shared by the copies of a Repository.
+func (r *Repository) Stats() Stats {
+	result := Stats{}
+	if r.stats == nil {
+		return result
+	}
+	r.stats.mu.Lock()
+	defer r.stats.mu.Unlock()
+	for metadataType, operation := range r.stats.operations {
+		copied := *operation
+		copied.StatusCodes = make(map[int]int, len(operation.StatusCodes))
+		for code, count := range operation.StatusCodes {
+			copied.StatusCodes[code] = count
+		}
+		result[metadataType] = copied
+	}
+	return result
+}
+
+// parsed reports the items parsed from metadata to the observers, and completes its checksums
+func (r *Repository) parsed(metadataType string, count int) {
+	r.observers().parsed(metadataType, count)
+	r.checksums.parsed(metadataType)
+}
+
+// decompressed returns a reader reporting the bytes read from reader, which holds decompressed metadata, to
+// the observers, and hashing them
+func (r *Repository) decompressed(metadataType string, reader io.Reader) io.Reader {
+	return r.checksums.decompressed(metadataType, r.observers().decompressed(metadataType, reader))
+}
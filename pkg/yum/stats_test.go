@@ -0,0 +1,68 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, err := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	require.NoError(t, err)
+
+	_, _, err = r.Packages(context.Background())
+	require.NoError(t, err)
+	_, _, err = r.Packages(context.Background())
+	require.NoError(t, err)
+	_, _, err = r.Advisories(context.Background())
+	require.NoError(t, err)
+	_, _, _ = r.Filelists(context.Background())
+
+	stats := r.Stats()
+	primary := stats["primary"]
+	assert.Equal(t, 1, primary.Fetches)
+	assert.Equal(t, map[int]int{http.StatusOK: 1}, primary.StatusCodes)
+	assert.Equal(t, int64(len(primaryXML)), primary.DownloadedBytes)
+	assert.Greater(t, primary.DecompressedBytes, int64(len(primaryXML)))
+	assert.Equal(t, 1, primary.CacheHits)
+	assert.Equal(t, 1, primary.CacheMisses)
+	assert.Equal(t, 2, primary.Items)
+	assert.Positive(t, primary.Duration)
+	assert.Equal(t, primary.Duration, primary.LastDuration)
+
+	assert.Equal(t, 1, stats["repomd"].Fetches)
+	assert.Greater(t, stats["updateinfo"].DecompressedBytes, int64(0))
+	assert.Positive(t, stats["updateinfo"].Items)
+	assert.Equal(t, map[int]int{http.StatusNotFound: 1}, stats["filelists"].StatusCodes)
+
+	// the returned statistics are a copy
+	primary.StatusCodes[http.StatusOK] = 5
+	assert.Equal(t, 1, r.Stats()["primary"].StatusCodes[http.StatusOK])
+
+	// statistics are kept across Clear
+	r.Clear()
+	_, _, err = r.Repomd(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, r.Stats()["repomd"].Fetches)
+}
+
+func TestStatsFailedRequest(t *testing.T) {
+	url := "http://127.0.0.1:0"
+	r, _ := NewRepository(YummySettings{URL: &url})
+	_, _, err := r.Repomd(context.Background())
+	assert.Error(t, err)
+
+	repomd := r.Stats()["repomd"]
+	assert.Equal(t, 1, repomd.Fetches)
+	assert.Equal(t, 1, repomd.Errors)
+	assert.Empty(t, repomd.StatusCodes)
+
+	var zero Repository
+	assert.Empty(t, zero.Stats())
+}
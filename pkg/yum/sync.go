@@ -0,0 +1,202 @@
+package yum
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultSyncConcurrency is the number of files Sync downloads at once when SyncOptions.Concurrency is not set
+const DefaultSyncConcurrency = 4
+
+// SyncOptions configures Repository.Sync
+type SyncOptions struct {
+	// Concurrency is the number of files downloaded at once, DefaultSyncConcurrency if not positive
+	Concurrency int
+	// Filter selects the packages to download, all packages are downloaded if nil
+	Filter func(Package) bool
+}
+
+// SyncResult counts the files handled by Repository.Sync
+type SyncResult struct {
+	Downloaded int   `json:"downloaded"` // files downloaded
+	Skipped    int   `json:"skipped"`    // files already on disk with a matching checksum
+	Failed     int   `json:"failed"`     // files that could not be downloaded
+	Bytes      int64 `json:"bytes"`      // bytes downloaded
+}
+
+// syncFile is a file to mirror, with the checksum and size it is expected to have
+type syncFile struct {
+	location Location
+	checksum Checksum
+	size     int64
+}
+
+// Sync mirrors the repository into dir, as reposync does: every metadata file listed in repomd.xml and the
+// packages selected by opts.Filter are downloaded to the same relative paths, skipping files already on disk
+// whose checksum matches. repomd.xml and its signature are written last, so that an interrupted sync never
+// leaves a repomd.xml referencing missing metadata. Failures of individual files do not stop the sync and are
+// returned together.
+func (r *Repository) Sync(ctx context.Context, dir string, opts SyncOptions) (SyncResult, error) {
+	repomd, _, err := r.Repomd(ctx)
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("error fetching repomd.xml: %w", err)
+	}
+
+	packages, _, err := r.Packages(ctx)
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("error fetching packages: %w", err)
+	}
+
+	files := metadataSyncFiles(repomd)
+	for _, pkg := range packages {
+		if opts.Filter == nil || opts.Filter(pkg) {
+			files = append(files, syncFile{location: pkg.Location, checksum: pkg.Checksum, size: pkg.Size.Package})
+		}
+	}
+
+	return r.syncFiles(ctx, dir, files, opts.Concurrency)
+}
+
+// MirrorMetadata downloads repomd.xml, its signature and every metadata file it references into dir, verbatim
+// and verified against their checksums, producing a layout that can be served as a repository as is.
+// Packages are not downloaded, see Sync. Files are handled as by Sync.
+func (r *Repository) MirrorMetadata(ctx context.Context, dir string) (SyncResult, error) {
+	repomd, _, err := r.Repomd(ctx)
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("error fetching repomd.xml: %w", err)
+	}
+
+	return r.syncFiles(ctx, dir, metadataSyncFiles(repomd), DefaultSyncConcurrency)
+}
+
+// metadataSyncFiles returns the metadata files listed in repomd
+func metadataSyncFiles(repomd *Repomd) []syncFile {
+	files := make([]syncFile, 0, len(repomd.Data))
+	for _, data := range repomd.Data {
+		files = append(files, syncFile{location: data.Location, checksum: data.Checksum, size: data.Size})
+	}
+	return files
+}
+
+// syncFiles downloads files into dir, concurrency at a time, then writes repomd.xml and its signature if
+// all files were downloaded
+func (r *Repository) syncFiles(ctx context.Context, dir string, files []syncFile, concurrency int) (SyncResult, error) {
+	var result SyncResult
+
+	if concurrency <= 0 {
+		concurrency = DefaultSyncConcurrency
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var errs []error
+	semaphore := make(chan struct{}, concurrency)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(file syncFile) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			skipped, written, err := r.syncFile(ctx, dir, file)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			switch {
+			case err != nil:
+				result.Failed++
+				errs = append(errs, err)
+			case skipped:
+				result.Skipped++
+			default:
+				result.Downloaded++
+				result.Bytes += written
+			}
+		}(file)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+	if len(errs) > 0 {
+		return result, errors.Join(errs...)
+	}
+
+	if err := writeSyncFile(dir, "repodata/repomd.xml", []byte(*r.repomd.RepomdString)); err != nil {
+		return result, err
+	}
+	if sig, _, sigErr := r.Signature(ctx); sigErr == nil {
+		if err := writeSyncFile(dir, "repodata/repomd.xml.asc", []byte(*sig)); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// syncFile downloads a file into dir unless a file with a matching checksum is already there.
+// Files are downloaded to a partial file first, so interrupted downloads never replace a file and
+// are resumed by the next sync.
+func (r *Repository) syncFile(ctx context.Context, dir string, file syncFile) (bool, int64, error) {
+	destPath, err := syncPath(dir, file.location.Href)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if fileMatches(destPath, file.checksum, file.size) {
+		return true, 0, nil
+	}
+
+	written, _, err := r.downloadFile(ctx, file.location, file.checksum, file.size, destPath)
+	return false, written, err
+}
+
+// syncPath returns the path of href within dir, refusing hrefs that would escape it
+func syncPath(dir string, href string) (string, error) {
+	local := filepath.FromSlash(href)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("refusing to sync %v outside of %v", href, dir)
+	}
+	return filepath.Join(dir, local), nil
+}
+
+// fileMatches returns true if the file at path exists and has the given checksum and, if not zero, size
+func fileMatches(path string, checksum Checksum, size int64) bool {
+	hash, err := newChecksumHash(checksum.Type)
+	if err != nil {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	read, err := io.Copy(hash, f)
+	if err != nil || (size != 0 && read != size) {
+		return false
+	}
+	return strings.EqualFold(hex.EncodeToString(hash.Sum(nil)), checksum.Value)
+}
+
+func writeSyncFile(dir string, href string, data []byte) error {
+	destPath, err := syncPath(dir, href)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(destPath, data, 0644)
+}
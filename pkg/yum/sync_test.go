@@ -0,0 +1,138 @@
+package yum
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// syncServer serves a repository with two packages, foo and bar
+func syncServer(t *testing.T) *httptest.Server {
+	rpms := map[string][]byte{
+		"Packages/foo-1.0-1.x86_64.rpm": []byte("foo rpm"),
+		"Packages/bar-1.0-1.x86_64.rpm": []byte("bar rpm"),
+	}
+
+	primary := `<metadata xmlns="http://linux.duke.edu/metadata/common" packages="2">`
+	for _, name := range []string{"foo", "bar"} {
+		href := fmt.Sprintf("Packages/%v-1.0-1.x86_64.rpm", name)
+		primary += fmt.Sprintf(`<package type="rpm"><name>%v</name><arch>x86_64</arch><version epoch="0" ver="1.0" rel="1"/>
+<checksum type="sha256" pkgid="YES">%v</checksum><size package="%d"/><location href="%v"/></package>`,
+			name, sha256Hex(rpms[href]), len(rpms[href]), href)
+	}
+	primary += `</metadata>`
+	var primaryGz bytes.Buffer
+	gz := gzip.NewWriter(&primaryGz)
+	_, err := gz.Write([]byte(primary))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	repomd := fmt.Sprintf(`<repomd xmlns="http://linux.duke.edu/metadata/repo">
+<data type="primary"><checksum type="sha256">%v</checksum><size>%d</size><location href="repodata/primary.xml.gz"/></data>
+</repomd>`, sha256Hex(primaryGz.Bytes()), primaryGz.Len())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(repomd))
+	})
+	mux.HandleFunc("/repodata/primary.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(primaryGz.Bytes())
+	})
+	for href, rpm := range rpms {
+		rpm := rpm
+		mux.HandleFunc("/"+href, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(rpm)
+		})
+	}
+	return httptest.NewServer(mux)
+}
+
+func TestSync(t *testing.T) {
+	s := syncServer(t)
+	defer s.Close()
+	dir := t.TempDir()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	result, err := r.Sync(context.Background(), dir, SyncOptions{Concurrency: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Downloaded)
+	assert.Equal(t, 0, result.Skipped)
+
+	rpm, err := os.ReadFile(filepath.Join(dir, "Packages", "foo-1.0-1.x86_64.rpm"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo rpm", string(rpm))
+	assert.FileExists(t, filepath.Join(dir, "repodata", "repomd.xml"))
+	assert.FileExists(t, filepath.Join(dir, "repodata", "primary.xml.gz"))
+
+	// files with a matching checksum are not downloaded again, others are replaced
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Packages", "bar-1.0-1.x86_64.rpm"), []byte("corrupt"), 0644))
+	r.Clear()
+	result, err = r.Sync(context.Background(), dir, SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Downloaded)
+	assert.Equal(t, 2, result.Skipped)
+}
+
+func TestSyncFilter(t *testing.T) {
+	s := syncServer(t)
+	defer s.Close()
+	dir := t.TempDir()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	result, err := r.Sync(context.Background(), dir, SyncOptions{Filter: func(p Package) bool { return p.Name == "foo" }})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Downloaded)
+	assert.FileExists(t, filepath.Join(dir, "Packages", "foo-1.0-1.x86_64.rpm"))
+	assert.NoFileExists(t, filepath.Join(dir, "Packages", "bar-1.0-1.x86_64.rpm"))
+}
+
+func TestSyncPath(t *testing.T) {
+	_, err := syncPath("/tmp/repo", "../etc/passwd")
+	assert.Error(t, err)
+	_, err = syncPath("/tmp/repo", "/etc/passwd")
+	assert.Error(t, err)
+	path, err := syncPath("/tmp/repo", "Packages/foo.rpm")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/repo", "Packages", "foo.rpm"), path)
+}
+
+func TestMirrorMetadata(t *testing.T) {
+	s := syncServer(t)
+	defer s.Close()
+	dir := t.TempDir()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	result, err := r.MirrorMetadata(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Downloaded)
+
+	repomd, err := os.ReadFile(filepath.Join(dir, "repodata", "repomd.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, *r.repomd.RepomdString, string(repomd))
+	assert.FileExists(t, filepath.Join(dir, "repodata", "primary.xml.gz"))
+	assert.NoDirExists(t, filepath.Join(dir, "Packages"))
+
+	// the mirror can be read as a repository
+	mirror := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer mirror.Close()
+	r, _ = NewRepository(YummySettings{Client: mirror.Client(), URL: &mirror.URL})
+	packages, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, packages, 2)
+}
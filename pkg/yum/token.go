@@ -0,0 +1,95 @@
+package yum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenExpiryMargin is how long before it expires a cached token is refreshed
+const tokenExpiryMargin = time.Minute
+
+// TokenSource returns the access token requests are authenticated with, such as an OAuth 2.0 bearer token
+type TokenSource func(ctx context.Context) (token string, err error)
+
+// StaticToken returns a TokenSource always returning token, for tokens obtained out of band
+func StaticToken(token string) TokenSource {
+	return func(context.Context) (string, error) {
+		return token, nil
+	}
+}
+
+// accessToken is a token and the time it expires, zero if it does not
+type accessToken struct {
+	value  string
+	expiry time.Time
+}
+
+// cachingTokenSource returns a TokenSource that reuses the token returned by fetch until shortly before
+// it expires. It is safe for concurrent use.
+func cachingTokenSource(fetch func(ctx context.Context) (accessToken, error)) TokenSource {
+	var mu sync.Mutex
+	var cached accessToken
+	return func(ctx context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cached.value != "" && (cached.expiry.IsZero() || time.Until(cached.expiry) > tokenExpiryMargin) {
+			return cached.value, nil
+		}
+		token, err := fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+		cached = token
+		return token.value, nil
+	}
+}
+
+// fetchOAuthToken sends a request to an OAuth 2.0 token endpoint and returns the access token of the response.
+// expires_in may be a number or, as Azure returns it, a string.
+func fetchOAuthToken(client HTTPDoer, req *http.Request) (accessToken, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return accessToken{}, fmt.Errorf("error fetching token from %v: %w", req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return accessToken{}, fmt.Errorf("error reading token from %v: %w", req.URL.Redacted(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return accessToken{}, fmt.Errorf("Cannot fetch token from %v: %d: %s", req.URL.Redacted(), resp.StatusCode, truncateBody(body))
+	}
+
+	var response struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return accessToken{}, fmt.Errorf("error parsing token from %v: %w", req.URL.Redacted(), err)
+	}
+	if response.AccessToken == "" {
+		return accessToken{}, fmt.Errorf("no access token in response from %v", req.URL.Redacted())
+	}
+
+	token := accessToken{value: response.AccessToken}
+	if seconds, err := response.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		token.expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	return token, nil
+}
+
+// truncateBody shortens an error response body for use in an error message
+func truncateBody(body []byte) []byte {
+	const maxLength = 200
+	if len(body) > maxLength {
+		return append(body[:maxLength:maxLength], "..."...)
+	}
+	return body
+}
@@ -0,0 +1,69 @@
+package yum
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingTokenSource(t *testing.T) {
+	var fetches int
+	expiry := time.Now().Add(tokenExpiryMargin / 2)
+	source := cachingTokenSource(func(ctx context.Context) (accessToken, error) {
+		fetches++
+		return accessToken{value: "token", expiry: expiry}, nil
+	})
+
+	// tokens are refreshed shortly before they expire
+	_, _ = source(context.Background())
+	_, _ = source(context.Background())
+	assert.Equal(t, 2, fetches)
+
+	expiry = time.Now().Add(time.Hour)
+	for range 2 {
+		token, err := source(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "token", token)
+	}
+	assert.Equal(t, 3, fetches)
+
+	failing := cachingTokenSource(func(ctx context.Context) (accessToken, error) {
+		return accessToken{}, errors.New("no credentials")
+	})
+	_, err := failing(context.Background())
+	assert.ErrorContains(t, err, "no credentials")
+}
+
+func TestFetchOAuthToken(t *testing.T) {
+	doer := &handlerDoer{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/number":
+			_, _ = w.Write([]byte(`{"access_token": "a", "expires_in": 60}`))
+		case "/string":
+			_, _ = w.Write([]byte(`{"access_token": "b", "expires_in": "60"}`))
+		case "/empty":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})}
+
+	for path, value := range map[string]string{"/number": "a", "/string": "b"} {
+		req, _ := http.NewRequest(http.MethodGet, "http://token.example.com"+path, nil)
+		token, err := fetchOAuthToken(doer, req)
+		require.NoError(t, err)
+		assert.Equal(t, value, token.value)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), token.expiry, 5*time.Second)
+	}
+
+	for _, path := range []string{"/empty", "/unauthorized"} {
+		req, _ := http.NewRequest(http.MethodGet, "http://token.example.com"+path, nil)
+		_, err := fetchOAuthToken(doer, req)
+		assert.Error(t, err, path)
+	}
+}
@@ -0,0 +1,119 @@
+package yum
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans of repositories
+const tracerName = "github.com/content-services/yummy/pkg/yum"
+
+// tracer returns the tracer of the TracerProvider of the settings, or of the global provider if none is set
+func (r *Repository) tracer() trace.Tracer {
+	provider := r.settings.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(tracerName)
+}
+
+// traced calls fetch in a span named after operation, such as "Repomd", recording the status code and error
+// it returns. Spans of the requests fetch sends are children of the span.
+func traced[T any](ctx context.Context, r *Repository, operation string, fetch func(context.Context) (T, int, error)) (T, int, error) {
+	attributes := []attribute.KeyValue{}
+	if r.settings.URL != nil {
+		attributes = append(attributes, attribute.String("yum.repository.url", redactURL(r.repositoryURL())))
+	}
+	ctx, span := r.tracer().Start(ctx, "yum."+operation, trace.WithAttributes(attributes...))
+	defer span.End()
+
+	result, code, err := fetch(ctx)
+	if code != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", code))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, code, err
+}
+
+// redactURL returns rawURL with the password of its userinfo redacted, as url.URL.Redacted, or an empty
+// string if it cannot be parsed
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Redacted()
+}
+
+// spanCacheHit records on the span of ctx that the result of its operation was served from cache
+func spanCacheHit(ctx context.Context) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("yum.cache_hit", true))
+}
+
+// spanItems records on the span of ctx the number of items its operation parsed
+func spanItems(ctx context.Context, count int) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("yum.items", count))
+}
+
+// startRequestSpan starts the client span of a request for a file of the given metadata type
+func (r *Repository) startRequestSpan(req *http.Request, metadataType string) trace.Span {
+	_, span := r.tracer().Start(req.Context(), req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.Redacted()),
+			attribute.String("yum.metadata_type", metadataType),
+		),
+	)
+	return span
+}
+
+// endRequestSpan records the response to a request on its span. The span ends when the response body is
+// closed, after recording the size of the body read.
+func endRequestSpan(span trace.Span, resp *http.Response, err error) {
+	if err != nil || resp == nil {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		return
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	resp.Body = &tracedBody{ReadCloser: resp.Body, span: span}
+}
+
+// tracedBody counts the bytes read through it and ends its span when closed
+type tracedBody struct {
+	io.ReadCloser
+	span  trace.Span
+	bytes int64
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes += int64(n)
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	if b.span != nil {
+		b.span.SetAttributes(attribute.Int64("http.response.body.size", b.bytes))
+		b.span.End()
+		b.span = nil
+	}
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,120 @@
+package yum
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func TestTracing(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	r, _ := NewRepository(YummySettings{
+		Client:         s.Client(),
+		URL:            &s.URL,
+		TracerProvider: provider,
+	})
+
+	_, _, err := r.Packages(context.Background())
+	require.NoError(t, err)
+	_, _, err = r.Repomd(context.Background())
+	require.NoError(t, err)
+
+	spans := map[string][]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+	require.Len(t, spans["yum.Packages"], 1)
+	require.Len(t, spans["yum.Repomd"], 3)
+	require.Len(t, spans["GET"], 2)
+
+	packages := spans["yum.Packages"][0]
+	attributes := spanAttributes(packages)
+	assert.Equal(t, s.URL, attributes["yum.repository.url"].AsString())
+	assert.Equal(t, int64(200), attributes["http.response.status_code"].AsInt64())
+	assert.Equal(t, int64(2), attributes["yum.items"].AsInt64())
+
+	cached := spanAttributes(spans["yum.Repomd"][2])
+	assert.True(t, cached["yum.cache_hit"].AsBool())
+
+	for _, request := range spans["GET"] {
+		attributes := spanAttributes(request)
+		assert.Equal(t, packages.SpanContext().TraceID(), request.SpanContext().TraceID())
+		assert.Equal(t, int64(200), attributes["http.response.status_code"].AsInt64())
+		assert.Greater(t, attributes["http.response.body.size"].AsInt64(), int64(0))
+		if attributes["yum.metadata_type"].AsString() == "primary" {
+			assert.Equal(t, packages.SpanContext().SpanID(), request.Parent().SpanID())
+			assert.Equal(t, int64(len(primaryXML)), attributes["http.response.body.size"].AsInt64())
+		}
+	}
+}
+
+func TestTracingError(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	url := s.URL + "/missing"
+	r, _ := NewRepository(YummySettings{
+		Client:         s.Client(),
+		URL:            &url,
+		TracerProvider: provider,
+	})
+
+	_, code, err := r.Repomd(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 404, code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, codes.Error, span.Status().Code)
+	}
+}
+
+func TestTracingRedactsCredentials(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	url := strings.Replace(s.URL, "http://", "http://user:secret@", 1)
+	r, _ := NewRepository(YummySettings{
+		Client:         s.Client(),
+		URL:            &url,
+		TracerProvider: provider,
+	})
+
+	_, _, err := r.Repomd(context.Background())
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		for _, kv := range span.Attributes() {
+			assert.NotContains(t, kv.Value.Emit(), "secret", kv.Key)
+		}
+	}
+	redacted := strings.Replace(s.URL, "http://", "http://user:xxxxx@", 1)
+	assert.Equal(t, redacted, spanAttributes(spans[1])["yum.repository.url"].AsString())
+	assert.Equal(t, redacted+"/repodata/repomd.xml", spanAttributes(spans[0])["url.full"].AsString())
+}
@@ -0,0 +1,162 @@
+package yum
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Treeinfo describes an installable tree, as published in the .treeinfo file at the root of install media
+// and kickstart trees
+type Treeinfo struct {
+	Release        TreeinfoRelease              `json:"release"`
+	Arch           string                       `json:"arch"`
+	BuildTimestamp int64                        `json:"buildTimestamp"`
+	Platforms      []string                     `json:"platforms,omitempty"`
+	Variants       []TreeinfoVariant            `json:"variants,omitempty"`
+	Images         map[string]map[string]string `json:"images,omitempty"`    // Platform to image type (e.g. "kernel", "boot.iso") to path
+	Stage2         map[string]string            `json:"stage2,omitempty"`    // Installer runtime image type to path
+	Checksums      map[string]string            `json:"checksums,omitempty"` // Path to checksum, formatted as "type:value"
+}
+
+type TreeinfoRelease struct {
+	Name    string `json:"name"`
+	Short   string `json:"short"`
+	Version string `json:"version"`
+}
+
+type TreeinfoVariant struct {
+	ID         string `json:"id"`
+	UID        string `json:"uid"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Packages   string `json:"packages"`   // Path to the packages of the variant, relative to the tree root
+	Repository string `json:"repository"` // Path to the repository of the variant, relative to the tree root
+}
+
+// treeinfoPaths are the file names a tree's treeinfo may be published as, in order of preference
+var treeinfoPaths = []string{".treeinfo", "treeinfo"}
+
+// Treeinfo fetches and parses the .treeinfo file at the root of the repository, if there is one.
+// Returns response code and error. Repositories that are not installable trees return a nil Treeinfo.
+func (r *Repository) Treeinfo(ctx context.Context) (*Treeinfo, int, error) {
+	var resp *http.Response
+	var treeinfo Treeinfo
+
+	if r.treeinfo != nil {
+		return r.treeinfo, 200, nil
+	}
+
+	for _, treeinfoPath := range treeinfoPaths {
+		treeinfoURL, err := joinURL(r.repositoryURL(), treeinfoPath)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error parsing treeinfo URL: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, treeinfoURL, nil)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error creating request: %w", err)
+		}
+
+		if resp, err = r.do(req, "treeinfo"); err != nil {
+			return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", treeinfoURL, err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			continue
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, resp.StatusCode, fmt.Errorf("Cannot fetch %v: %d", treeinfoURL, resp.StatusCode)
+		}
+
+		if treeinfo, err = ParseTreeinfo(resp.Body); err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("error parsing treeinfo: %w", err)
+		}
+		r.treeinfo = &treeinfo
+		return r.treeinfo, resp.StatusCode, nil
+	}
+
+	return nil, http.StatusNotFound, nil
+}
+
+// ParseTreeinfo parses a .treeinfo file, in either the productmd format or the older format with a [general] section
+func ParseTreeinfo(body io.Reader) (Treeinfo, error) {
+	treeinfo := Treeinfo{
+		Images:    map[string]map[string]string{},
+		Stage2:    map[string]string{},
+		Checksums: map[string]string{},
+	}
+
+	sections, err := parseINI(body)
+	if err != nil {
+		return Treeinfo{}, err
+	}
+
+	for _, section := range sections {
+		switch {
+		case section.Name == "release":
+			treeinfo.Release = TreeinfoRelease{Name: section.Keys["name"], Short: section.Keys["short"], Version: section.Keys["version"]}
+		case section.Name == "tree":
+			treeinfo.Arch = section.Keys["arch"]
+			if timestamp := section.Keys["build_timestamp"]; timestamp != "" {
+				if treeinfo.BuildTimestamp, err = parseTimestamp(timestamp); err != nil {
+					return Treeinfo{}, fmt.Errorf("invalid build_timestamp: %w", err)
+				}
+			}
+			treeinfo.Platforms = splitList(section.Keys["platforms"])
+		case strings.HasPrefix(section.Name, "variant-"):
+			treeinfo.Variants = append(treeinfo.Variants, TreeinfoVariant{
+				ID:         section.Keys["id"],
+				UID:        section.Keys["uid"],
+				Name:       section.Keys["name"],
+				Type:       section.Keys["type"],
+				Packages:   section.Keys["packages"],
+				Repository: section.Keys["repository"],
+			})
+		case strings.HasPrefix(section.Name, "images-"):
+			treeinfo.Images[strings.TrimPrefix(section.Name, "images-")] = section.Keys
+		case section.Name == "stage2":
+			treeinfo.Stage2 = section.Keys
+		case section.Name == "checksums":
+			treeinfo.Checksums = section.Keys
+		}
+	}
+
+	// treeinfo 1.0 only has a general section, which productmd treeinfos keep for compatibility
+	if general, ok := iniSectionByName(sections, "general"); ok {
+		if treeinfo.Release.Name == "" {
+			treeinfo.Release = TreeinfoRelease{Name: general.Keys["family"], Version: general.Keys["version"]}
+		}
+		if treeinfo.Arch == "" {
+			treeinfo.Arch = general.Keys["arch"]
+		}
+		if variant := general.Keys["variant"]; variant != "" && len(treeinfo.Variants) == 0 {
+			treeinfo.Variants = append(treeinfo.Variants, TreeinfoVariant{ID: variant, UID: variant, Name: variant, Packages: general.Keys["packagedir"], Repository: "."})
+		}
+	}
+
+	return treeinfo, nil
+}
+
+// parseTimestamp parses unix timestamps, which some metadata formats write with a fractional part
+func parseTimestamp(timestamp string) (int64, error) {
+	seconds, _, _ := strings.Cut(strings.TrimSpace(timestamp), ".")
+	return strconv.ParseInt(seconds, 10, 64)
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(list string) []string {
+	result := []string{}
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package yum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTreeinfo(t *testing.T) {
+	f, err := os.Open("mocks/treeinfo")
+	require.NoError(t, err)
+	defer f.Close()
+
+	treeinfo, err := ParseTreeinfo(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, TreeinfoRelease{Name: "Red Hat Enterprise Linux", Short: "RHEL", Version: "8.5.0"}, treeinfo.Release)
+	assert.Equal(t, "x86_64", treeinfo.Arch)
+	assert.Equal(t, int64(1634745599), treeinfo.BuildTimestamp)
+	assert.Equal(t, []string{"x86_64", "xen"}, treeinfo.Platforms)
+	require.Len(t, treeinfo.Variants, 2)
+	assert.Equal(t, TreeinfoVariant{ID: "BaseOS", UID: "BaseOS", Name: "BaseOS", Type: "variant", Packages: "Packages", Repository: "."}, treeinfo.Variants[1])
+	assert.Equal(t, "images/pxeboot/vmlinuz", treeinfo.Images["x86_64"]["kernel"])
+	assert.Equal(t, "images/boot.iso", treeinfo.Images["x86_64"]["boot.iso"])
+	assert.Len(t, treeinfo.Images["xen"], 2)
+	assert.Equal(t, "images/install.img", treeinfo.Stage2["mainimage"])
+	assert.True(t, strings.HasPrefix(treeinfo.Checksums["images/boot.iso"], "sha256:"))
+}
+
+func TestParseTreeinfoGeneral(t *testing.T) {
+	treeinfo, err := ParseTreeinfo(strings.NewReader(`
+[general]
+family = CentOS
+version = 7
+arch = x86_64
+variant =
+packagedir = Packages
+
+[images-x86_64]
+kernel = images/pxeboot/vmlinuz
+`))
+	require.NoError(t, err)
+	assert.Equal(t, TreeinfoRelease{Name: "CentOS", Version: "7"}, treeinfo.Release)
+	assert.Equal(t, "x86_64", treeinfo.Arch)
+	assert.Empty(t, treeinfo.Variants)
+	assert.Equal(t, "images/pxeboot/vmlinuz", treeinfo.Images["x86_64"]["kernel"])
+
+	_, err = ParseTreeinfo(strings.NewReader("arch = x86_64"))
+	assert.Error(t, err)
+}
+
+func TestFetchTreeinfo(t *testing.T) {
+	treeinfoFile, err := os.ReadFile("mocks/treeinfo")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/treeinfo", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(treeinfoFile)
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	treeinfo, code, err := r.Treeinfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "RHEL", treeinfo.Release.Short)
+	assert.Equal(t, treeinfo, r.treeinfo)
+
+	// not an installable tree
+	s2 := server()
+	defer s2.Close()
+	r, _ = NewRepository(YummySettings{Client: s2.Client(), URL: &s2.URL})
+	treeinfo, code, err = r.Treeinfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Nil(t, treeinfo)
+}
@@ -0,0 +1,335 @@
+package yum
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type AdvisoryType string
+
+const (
+	AdvisorySecurity    AdvisoryType = "security"
+	AdvisoryBugfix      AdvisoryType = "bugfix"
+	AdvisoryEnhancement AdvisoryType = "enhancement"
+	AdvisoryNewPackage  AdvisoryType = "newpackage"
+)
+
+// Severity of an advisory, ordered so that severities can be compared
+type Severity int
+
+const (
+	SeverityNone Severity = iota
+	SeverityLow
+	SeverityModerate
+	SeverityImportant
+	SeverityCritical
+)
+
+var severityNames = []string{"None", "Low", "Moderate", "Important", "Critical"}
+
+// ParseSeverity parses a severity name case-insensitively, returning SeverityNone for unknown names
+func ParseSeverity(name string) Severity {
+	for i, severityName := range severityNames {
+		if strings.EqualFold(strings.TrimSpace(name), severityName) {
+			return Severity(i)
+		}
+	}
+	return SeverityNone
+}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return severityNames[SeverityNone]
+	}
+	return severityNames[s]
+}
+
+// MarshalText formats the severity by name, so that it serializes the same way it is parsed
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	*s = ParseSeverity(string(text))
+	return nil
+}
+
+// Advisory is an erratum from the updateinfo metadata of a repository
+type Advisory struct {
+	ID          string            `xml:"id" json:"id"`
+	Type        AdvisoryType      `xml:"type,attr" json:"type"`
+	Status      string            `xml:"status,attr" json:"status"`
+	From        string            `xml:"from,attr" json:"from"`
+	Version     string            `xml:"version,attr" json:"version"`
+	Title       string            `xml:"title" json:"title"`
+	Severity    Severity          `xml:"severity" json:"severity"`
+	Release     string            `xml:"release" json:"release"`
+	Issued      AdvisoryDate      `xml:"issued" json:"issued"`
+	Updated     AdvisoryDate      `xml:"updated" json:"updated"`
+	Rights      string            `xml:"rights" json:"rights"`
+	Summary     string            `xml:"summary" json:"summary"`
+	Description string            `xml:"description" json:"description"`
+	Solution    string            `xml:"solution" json:"solution"`
+	References  []Reference       `xml:"references>reference" json:"references,omitempty"`
+	Packages    []AdvisoryPackage `xml:"pkglist>collection>package" json:"packages,omitempty"`
+}
+
+type ReferenceType string
+
+const (
+	ReferenceCVE      ReferenceType = "cve"
+	ReferenceBugzilla ReferenceType = "bugzilla"
+	ReferenceSelf     ReferenceType = "self"
+)
+
+// Reference links an advisory to a CVE, a bug or another web page. For CVE and bugzilla
+// references, ID is the CVE id or bug number.
+type Reference struct {
+	Type  ReferenceType `xml:"type,attr" json:"type"`
+	ID    string        `xml:"id,attr" json:"id"`
+	URL   string        `xml:"href,attr" json:"url"`
+	Title string        `xml:"title,attr" json:"title"`
+}
+
+// CVEs returns the ids of the CVEs referenced by the advisory
+func (a Advisory) CVEs() []string {
+	return a.referenceIDs(ReferenceCVE)
+}
+
+// BugzillaIDs returns the ids of the bugs referenced by the advisory
+func (a Advisory) BugzillaIDs() []string {
+	return a.referenceIDs(ReferenceBugzilla)
+}
+
+func (a Advisory) referenceIDs(referenceType ReferenceType) []string {
+	ids := []string{}
+	for _, reference := range a.References {
+		if reference.Type == referenceType && reference.ID != "" {
+			ids = append(ids, reference.ID)
+		}
+	}
+	return ids
+}
+
+type AdvisoryDate struct {
+	Date string `xml:"date,attr" json:"date"`
+}
+
+// AdvisoryPackage is a package fixed by an advisory
+type AdvisoryPackage struct {
+	Name            string     `xml:"name,attr" json:"name"`
+	Epoch           int32      `xml:"epoch,attr" json:"epoch"`
+	Version         string     `xml:"version,attr" json:"version"`
+	Release         string     `xml:"release,attr" json:"release"`
+	Arch            string     `xml:"arch,attr" json:"arch"`
+	Src             string     `xml:"src,attr" json:"src"`
+	Filename        string     `xml:"filename" json:"filename"`
+	Checksums       []Checksum `xml:"sum" json:"checksums,omitempty"`
+	RebootSuggested bool       `xml:"reboot_suggested" json:"rebootSuggested"`
+}
+
+// NEVRA returns the NEVRA of the advisory package
+func (p AdvisoryPackage) NEVRA() NEVRA {
+	return NEVRA{
+		Name:    p.Name,
+		Epoch:   p.Epoch,
+		Version: p.Version,
+		Release: p.Release,
+		Arch:    p.Arch,
+	}
+}
+
+// AdvisoryOption filters the advisories returned by Repository.Advisories and FilterAdvisories
+type AdvisoryOption func(*advisoryFilter)
+
+type advisoryFilter struct {
+	types       []AdvisoryType
+	minSeverity Severity
+}
+
+// WithType only returns advisories of one of the given types
+func WithType(types ...AdvisoryType) AdvisoryOption {
+	return func(f *advisoryFilter) {
+		f.types = append(f.types, types...)
+	}
+}
+
+// WithMinSeverity only returns advisories of at least the given severity
+func WithMinSeverity(severity Severity) AdvisoryOption {
+	return func(f *advisoryFilter) {
+		f.minSeverity = severity
+	}
+}
+
+// FilterAdvisories returns the advisories matching all of the given options
+func FilterAdvisories(advisories []Advisory, opts ...AdvisoryOption) []Advisory {
+	var filter advisoryFilter
+	for _, opt := range opts {
+		opt(&filter)
+	}
+
+	result := []Advisory{}
+	for _, advisory := range advisories {
+		if len(filter.types) > 0 && !slices.Contains(filter.types, advisory.Type) {
+			continue
+		}
+		if advisory.Severity < filter.minSeverity {
+			continue
+		}
+		result = append(result, advisory)
+	}
+	return result
+}
+
+// Advisories populates r.Advisories with the advisories from the updateinfo metadata of the repository,
+// filtered by the given options. Returns response code and error.
+// If the advisories were successfully fetched previously, will filter the cached advisories.
+func (r *Repository) Advisories(ctx context.Context, opts ...AdvisoryOption) ([]Advisory, int, error) {
+	var err error
+	var updateInfoURL *string
+	var resp *http.Response
+	var advisories []Advisory
+
+	r.cacheRequest(ctx, "updateinfo", r.advisories != nil)
+	if r.advisories != nil {
+		return FilterAdvisories(r.advisories, opts...), 200, nil
+	}
+
+	if _, _, err = r.Repomd(ctx); err != nil {
+		return nil, 0, fmt.Errorf("error parsing repomd.xml: %w", err)
+	}
+
+	if updateInfoURL, err = r.getUpdateInfoURL(); err != nil {
+		return nil, 0, fmt.Errorf("error parsing updateinfo URL: %w", err)
+	}
+
+	if updateInfoURL == nil {
+		return []Advisory{}, 200, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *updateInfoURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	if resp, err = r.do(req, "updateinfo"); err != nil {
+		return nil, erroredStatusCode(resp), fmt.Errorf("GET error for file %v: %w", *updateInfoURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("Cannot fetch %v: %d", *updateInfoURL, resp.StatusCode)
+	}
+
+	if advisories, err = parseUpdateInfoXML(r.decompressedBody(resp, "updateinfo"), *r.settings.MaxXmlSize); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("error parsing updateinfo.xml: %w", err)
+	}
+	r.parsed("updateinfo", len(advisories))
+	r.advisories = advisories
+
+	return FilterAdvisories(advisories, opts...), resp.StatusCode, nil
+}
+
+// AdvisoriesForPackage returns the advisories whose package list contains the package with the given NEVRA.
+// Lookups use an index over the advisories that is built once, on first use.
+func (r *Repository) AdvisoriesForPackage(ctx context.Context, nevra NEVRA) ([]Advisory, int, error) {
+	advisories, code, err := r.Advisories(ctx)
+	if err != nil {
+		return nil, code, err
+	}
+
+	if r.advisoryIndex == nil {
+		r.advisoryIndex = indexAdvisories(advisories)
+	}
+
+	result := []Advisory{}
+	for _, i := range r.advisoryIndex[nevra] {
+		result = append(result, advisories[i])
+	}
+	return result, code, nil
+}
+
+// indexAdvisories maps each package NEVRA to the indexes of the advisories listing it
+func indexAdvisories(advisories []Advisory) map[NEVRA][]int {
+	index := map[NEVRA][]int{}
+	for i, advisory := range advisories {
+		for _, pkg := range advisory.Packages {
+			nevra := pkg.NEVRA()
+			if indexes := index[nevra]; len(indexes) > 0 && indexes[len(indexes)-1] == i {
+				continue
+			}
+			index[nevra] = append(index[nevra], i)
+		}
+	}
+	return index
+}
+
+func (r *Repository) getUpdateInfoURL() (*string, error) {
+	var updateInfoLocation Location
+
+	for _, data := range r.repomd.Data {
+		if data.Type == "updateinfo" {
+			updateInfoLocation = data.Location
+		}
+	}
+
+	if updateInfoLocation.Href == "" {
+		return nil, nil
+	}
+
+	updateInfoURL, err := r.getLocationURL(updateInfoLocation)
+	if err != nil {
+		return nil, err
+	}
+	return &updateInfoURL, nil
+}
+
+// ParseUpdateInfoXML creates an Advisory array from a compressed or uncompressed updateinfo.xml,
+// reading at most DefaultMaxXmlSize bytes of it
+func ParseUpdateInfoXML(body io.ReadCloser) ([]Advisory, error) {
+	return parseUpdateInfoXML(body, DefaultMaxXmlSize)
+}
+
+// parseUpdateInfoXML parses a compressed or uncompressed updateinfo.xml, reading at most maxSize bytes
+func parseUpdateInfoXML(body io.ReadCloser, maxSize int64) ([]Advisory, error) {
+	advisories := []Advisory{}
+
+	reader, err := ExtractIfCompressed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxSize))
+	var element, item string
+	for {
+		t, decodeError := decoder.Token()
+
+		if decodeError == io.EOF {
+			break
+		} else if decodeError != nil {
+			return nil, fmt.Errorf("error decoding token: %w", xmlParseError(decoder, element, item, decodeError))
+		} else if t == nil {
+			break
+		}
+
+		elType, ok := t.(xml.StartElement)
+		if ok && elType.Name.Local == "update" {
+			var advisory Advisory
+			if decodeElementError := decoder.DecodeElement(&advisory, &elType); decodeElementError != nil {
+				return nil, xmlParseError(decoder, elType.Name.Local, cmp.Or(advisory.ID, item), decodeElementError)
+			}
+			item = advisory.ID
+			advisories = append(advisories, advisory)
+		} else if ok {
+			element = elType.Name.Local
+		}
+	}
+
+	return advisories, nil
+}
@@ -0,0 +1,155 @@
+package yum
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed "mocks/updateinfo.xml.gz"
+var updateInfoXML []byte
+
+func TestParseUpdateInfoXML(t *testing.T) {
+	f, err := os.Open("mocks/updateinfo.xml.gz")
+	require.NoError(t, err)
+	defer f.Close()
+
+	advisories, err := ParseUpdateInfoXML(f)
+	require.NoError(t, err)
+	require.Len(t, advisories, 4)
+
+	advisory := advisories[0]
+	assert.Equal(t, "RHSA-2015:1981", advisory.ID)
+	assert.Equal(t, AdvisorySecurity, advisory.Type)
+	assert.Equal(t, SeverityImportant, advisory.Severity)
+	assert.Equal(t, "final", advisory.Status)
+	assert.Equal(t, "2015-11-05 00:00:00", advisory.Issued.Date)
+	assert.Equal(t, "Red Hat Enterprise Linux 7", advisory.Release)
+	require.Len(t, advisory.Packages, 1)
+	assert.Equal(t, "nss-devel", advisory.Packages[0].Name)
+	assert.Equal(t, "3.19.1", advisory.Packages[0].Version)
+	assert.Equal(t, "sha256", advisory.Packages[0].Checksums[0].Type)
+	assert.True(t, advisory.Packages[0].RebootSuggested)
+
+	require.Len(t, advisory.References, 4)
+	assert.Equal(t, Reference{
+		Type:  ReferenceBugzilla,
+		ID:    "1269351",
+		URL:   "https://bugzilla.redhat.com/show_bug.cgi?id=1269351",
+		Title: "CVE-2015-7181 nss: heap buffer overflow",
+	}, advisory.References[1])
+	assert.Equal(t, []string{"CVE-2015-7181", "CVE-2015-7182"}, advisory.CVEs())
+	assert.Equal(t, []string{"1269351"}, advisory.BugzillaIDs())
+
+	assert.Equal(t, SeverityNone, advisories[2].Severity)
+	assert.Empty(t, advisories[2].References)
+	assert.Empty(t, advisories[2].CVEs())
+	assert.Len(t, advisories[2].Packages, 2)
+	assert.Empty(t, advisories[3].Packages)
+}
+
+func TestParseUpdateInfoXMLMaxSize(t *testing.T) {
+	f, err := os.Open("mocks/updateinfo.xml.gz")
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = parseUpdateInfoXML(f, 100)
+	assert.ErrorContains(t, err, "unexpected EOF")
+}
+
+func TestSeverity(t *testing.T) {
+	assert.Equal(t, SeverityCritical, ParseSeverity("critical"))
+	assert.Equal(t, SeverityModerate, ParseSeverity(" Moderate "))
+	assert.Equal(t, SeverityNone, ParseSeverity("unknown"))
+	assert.Equal(t, "Important", SeverityImportant.String())
+	assert.True(t, SeverityImportant > SeverityModerate)
+}
+
+func TestAdvisoryJSON(t *testing.T) {
+	advisory := Advisory{
+		ID:       "RHSA-2024:0001",
+		Type:     AdvisorySecurity,
+		Severity: SeverityImportant,
+		Packages: []AdvisoryPackage{{Name: "foo", Version: "1.0", Release: "1", Arch: "x86_64", RebootSuggested: true}},
+	}
+	marshaled, err := json.Marshal(advisory)
+	require.NoError(t, err)
+	assert.Contains(t, string(marshaled), `"severity":"Important"`)
+	assert.Contains(t, string(marshaled), `"rebootSuggested":true`)
+	assert.NotContains(t, string(marshaled), `"references"`)
+
+	var unmarshaled Advisory
+	require.NoError(t, json.Unmarshal(marshaled, &unmarshaled))
+	assert.Equal(t, advisory, unmarshaled)
+}
+
+func TestFetchAdvisories(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	ctx := context.Background()
+
+	advisories, code, err := r.Advisories(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.Len(t, advisories, 4)
+	assert.Equal(t, advisories, r.advisories)
+
+	advisories, _, err = r.Advisories(ctx, WithType(AdvisorySecurity))
+	require.NoError(t, err)
+	assert.Len(t, advisories, 2)
+
+	advisories, _, err = r.Advisories(ctx, WithType(AdvisorySecurity), WithMinSeverity(SeverityImportant))
+	require.NoError(t, err)
+	require.Len(t, advisories, 1)
+	assert.Equal(t, "RHSA-2015:1981", advisories[0].ID)
+
+	advisories, _, err = r.Advisories(ctx, WithType(AdvisoryBugfix, AdvisoryEnhancement))
+	require.NoError(t, err)
+	assert.Len(t, advisories, 2)
+}
+
+func serveUpdateInfoXML(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/gzip")
+	body := updateInfoXML
+	_, _ = w.Write(body)
+}
+
+func TestAdvisoriesForPackage(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	ctx := context.Background()
+
+	nevra, err := ParseNEVRA("nss-devel-0:3.19.1-18.el7.i686")
+	require.NoError(t, err)
+	advisories, code, err := r.AdvisoriesForPackage(ctx, nevra)
+	require.NoError(t, err)
+	assert.Equal(t, 200, code)
+	require.Len(t, advisories, 2)
+	assert.Equal(t, "RHSA-2015:1981", advisories[0].ID)
+	assert.Equal(t, "RHBA-2016:0100", advisories[1].ID)
+	assert.NotNil(t, r.advisoryIndex)
+
+	nevra.Arch = "x86_64"
+	advisories, _, err = r.AdvisoriesForPackage(ctx, nevra)
+	require.NoError(t, err)
+	require.Len(t, advisories, 1)
+	assert.Equal(t, "RHBA-2016:0100", advisories[0].ID)
+
+	nevra.Version = "1.0"
+	advisories, _, err = r.AdvisoriesForPackage(ctx, nevra)
+	require.NoError(t, err)
+	assert.Empty(t, advisories)
+
+	r.Clear()
+	assert.Nil(t, r.advisoryIndex)
+}
@@ -1,11 +1,16 @@
 package yum
 
 import (
-	"bufio"
+	"cmp"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
 	"io"
-
-	"github.com/h2non/filetype"
-	"github.com/h2non/filetype/matchers"
+	"strconv"
+	"strings"
 )
 
 // Converts any struct to a pointer to that struct
@@ -13,26 +18,53 @@ func Ptr[T any](item T) *T {
 	return &item
 }
 
-func ExtractIfCompressed(reader io.ReadCloser) (extractedReader io.Reader, err error) {
-	bufferedReader := bufio.NewReader(reader)
-	header, err := bufferedReader.Peek(20)
-	if err != nil {
-		return nil, err
+// forLocale looks up locale in translations, falling back to the language without its territory,
+// so "pt_BR" matches a "pt" translation
+func forLocale[T any](translations map[string]T, locale string) (T, bool) {
+	if t, ok := translations[locale]; ok {
+		return t, true
 	}
-	fileType, err := filetype.Match(header)
-	if err != nil {
-		return nil, err
+	if lang, _, found := strings.Cut(locale, "_"); found {
+		if t, ok := translations[lang]; ok {
+			return t, true
+		}
 	}
+	var zero T
+	return zero, false
+}
 
-	// handle compressed file
-	if fileType == matchers.TypeGz || fileType == matchers.TypeZstd || fileType == matchers.TypeXz {
-		extractedReader, err = ParseCompressedData(bufferedReader)
-		if err != nil {
-			return nil, err
-		}
-		return extractedReader, nil
-	} else {
-		// handle uncompressed comps
-		return bufferedReader, nil
+// compareNumericStrings compares a and b as integers if both are numeric, and as strings otherwise
+func compareNumericStrings(a, b string) int {
+	aInt, aErr := strconv.ParseInt(a, 10, 64)
+	bInt, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr == nil && bErr == nil {
+		return cmp.Compare(aInt, bInt)
 	}
+	return strings.Compare(a, b)
+}
+
+// newChecksumHash returns a hash for a repomd/primary checksum type such as "sha256"
+func newChecksumHash(checksumType string) (hash.Hash, error) {
+	switch checksumType {
+	case "md5":
+		return md5.New(), nil
+	case "sha", "sha1":
+		return sha1.New(), nil
+	case "sha224":
+		return sha256.New224(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha384":
+		return sha512.New384(), nil
+	case "sha512":
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum type: %v", checksumType)
+	}
+}
+
+// ExtractIfCompressed returns a reader decompressing reader if it is compressed, see DecompressingReader
+func ExtractIfCompressed(reader io.ReadCloser) (extractedReader io.Reader, err error) {
+	extractedReader, _, err = DecompressingReader(reader)
+	return extractedReader, err
 }
@@ -0,0 +1,76 @@
+package yum
+
+import "strings"
+
+// baseArches maps machine architectures to the base architecture of their packages, for those that differ
+var baseArches = map[string]string{
+	"i386": "i386", "i486": "i386", "i586": "i386", "i686": "i386", "athlon": "i386", "geode": "i386",
+	"amd64": "x86_64", "ia32e": "x86_64",
+	"armv7l": "armhfp", "armv7hl": "armhfp", "armv7hnl": "armhfp",
+	"armv6l": "arm", "armv5tel": "arm",
+	"ppc64p7": "ppc64", "ppc64iseries": "ppc64", "ppc64pseries": "ppc64",
+	"sparc64v": "sparc64", "sparcv9": "sparc",
+}
+
+// BaseArch returns the base architecture of packages built for arch, such as i386 for i686, as given by
+// $basearch in repository URLs. Architectures without a distinct base architecture are returned as is.
+func BaseArch(arch string) string {
+	if baseArch, ok := baseArches[arch]; ok {
+		return baseArch
+	}
+	return arch
+}
+
+// SubstituteVars replaces the yum variables in s, written $name or ${name}, with their values in vars, such as
+// $releasever and $basearch in a .repo baseurl. If vars sets arch but not basearch, $basearch is the
+// BaseArch of arch. Variable names are letters, digits and underscores; unknown variables are left as is.
+func SubstituteVars(s string, vars map[string]string) string {
+	if len(vars) == 0 || !strings.Contains(s, "$") {
+		return s
+	}
+	lookup := func(name string) (string, bool) {
+		if value, ok := vars[name]; ok {
+			return value, true
+		}
+		if arch, ok := vars["arch"]; ok && name == "basearch" {
+			return BaseArch(arch), true
+		}
+		return "", false
+	}
+
+	var result strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 {
+			result.WriteString(s)
+			return result.String()
+		}
+		result.WriteString(s[:i])
+		s = s[i:]
+
+		var name, variable string
+		if strings.HasPrefix(s, "${") {
+			if end := strings.IndexByte(s, '}'); end > 0 {
+				name, variable = s[2:end], s[:end+1]
+			}
+		} else {
+			end := 1
+			for end < len(s) && isVarNameByte(s[end]) {
+				end++
+			}
+			name, variable = s[1:end], s[:end]
+		}
+
+		if value, ok := lookup(name); ok && name != "" {
+			result.WriteString(value)
+			s = s[len(variable):]
+		} else {
+			result.WriteByte('$')
+			s = s[1:]
+		}
+	}
+}
+
+func isVarNameByte(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || b == '_'
+}
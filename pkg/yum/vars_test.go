@@ -0,0 +1,47 @@
+package yum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubstituteVars(t *testing.T) {
+	vars := map[string]string{"releasever": "9", "basearch": "x86_64", "contentdir": "pub/rocky"}
+
+	assert.Equal(t, "https://example.com/pub/rocky/9/BaseOS/x86_64/os/",
+		SubstituteVars("https://example.com/$contentdir/$releasever/BaseOS/$basearch/os/", vars))
+	assert.Equal(t, "https://example.com/9-x86_64/",
+		SubstituteVars("https://example.com/${releasever}-${basearch}/", vars))
+	assert.Equal(t, "https://example.com/$unknown/${other}/$/9",
+		SubstituteVars("https://example.com/$unknown/${other}/$/$releasever", vars))
+	assert.Equal(t, "https://example.com/$releasever", SubstituteVars("https://example.com/$releasever", nil))
+	assert.Equal(t, "https://example.com/9x", SubstituteVars("https://example.com/${releasever}x", vars))
+	assert.Equal(t, "https://example.com/$releaseverx", SubstituteVars("https://example.com/$releaseverx", vars))
+	assert.Equal(t, "https://example.com/${releasever", SubstituteVars("https://example.com/${releasever", vars))
+}
+
+func TestSubstituteVarsArch(t *testing.T) {
+	assert.Equal(t, "i686/i386", SubstituteVars("$arch/$basearch", map[string]string{"arch": "i686"}))
+	assert.Equal(t, "aarch64/aarch64", SubstituteVars("$arch/$basearch", map[string]string{"arch": "aarch64"}))
+	assert.Equal(t, "armv7hl/armhfp", SubstituteVars("$arch/$basearch", map[string]string{"arch": "armv7hl"}))
+	assert.Equal(t, "i686/i586", SubstituteVars("$arch/$basearch", map[string]string{"arch": "i686", "basearch": "i586"}))
+}
+
+func TestRepositoryVars(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	url := s.URL + "/$dir"
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &url, Vars: map[string]string{"dir": "missing"}})
+	_, code, err := r.Repomd(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 404, code)
+
+	r.Configure(YummySettings{Vars: map[string]string{"dir": ""}})
+	repomd, code, err := r.Repomd(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 200, code)
+	assert.NotNil(t, repomd)
+}
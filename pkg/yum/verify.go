@@ -0,0 +1,171 @@
+package yum
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+)
+
+var (
+	// ErrGPGKeyExpired is wrapped by signature problems where repomd.xml was signed by an expired key
+	ErrGPGKeyExpired = errors.New("gpg key expired")
+	// ErrGPGKeyRevoked is wrapped by signature problems where repomd.xml was signed by a revoked key
+	ErrGPGKeyRevoked = errors.New("gpg key revoked")
+)
+
+// VerifyProblem describes a single issue found while verifying a repository
+type VerifyProblem = MetadataError
+
+// VerifyReport is the result of Repository.Verify, listing every problem found
+type VerifyReport struct {
+	Problems []VerifyProblem
+}
+
+// OK returns true if no problems were found
+func (v VerifyReport) OK() bool {
+	return len(v.Problems) == 0
+}
+
+// Err combines all problems into a single error wrapping a *MultiError, or returns nil if there were none
+func (v VerifyReport) Err() error {
+	if v.OK() {
+		return nil
+	}
+	return fmt.Errorf("repository verification failed: %w", &MultiError{Errors: v.Problems})
+}
+
+// Verify checks that the repomd exists, that every metadata file it advertises can be fetched
+// and matches its checksum and size, and, if gpgKey is not nil or the repository is configured with
+// GPGKeys, that the repomd signature verifies against any of the armored keys. All problems found
+// are reported rather than only the first.
+func (r *Repository) Verify(ctx context.Context, gpgKey *string) VerifyReport {
+	var report VerifyReport
+
+	repomd, code, err := r.Repomd(ctx)
+	if err != nil {
+		repomdURL, _ := r.getRepomdURL()
+		report.Problems = append(report.Problems, VerifyProblem{Type: "repomd", URL: repomdURL, StatusCode: code, Err: err})
+		return report
+	}
+
+	for _, data := range repomd.Data {
+		if problem := r.verifyData(ctx, data); problem != nil {
+			report.Problems = append(report.Problems, *problem)
+		}
+	}
+
+	gpgKeys := r.settings.GPGKeys
+	if gpgKey != nil {
+		gpgKeys = append([]string{*gpgKey}, gpgKeys...)
+	}
+	if len(gpgKeys) > 0 {
+		if problem := r.verifySignature(ctx, gpgKeys); problem != nil {
+			report.Problems = append(report.Problems, *problem)
+		}
+	}
+
+	return report
+}
+
+// verifyData fetches a single metadata file and compares it to the checksum and size in repomd.xml
+func (r *Repository) verifyData(ctx context.Context, data Data) *VerifyProblem {
+	dataURL, err := r.getLocationURL(data.Location)
+	if err != nil {
+		return &VerifyProblem{Type: data.Type, Err: fmt.Errorf("error parsing URL: %w", err)}
+	}
+	problem := VerifyProblem{Type: data.Type, URL: dataURL}
+
+	hash, err := newChecksumHash(data.Checksum.Type)
+	if err != nil {
+		problem.Err = err
+		return &problem
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, dataURL, nil)
+	if err != nil {
+		problem.Err = fmt.Errorf("error creating request: %w", err)
+		return &problem
+	}
+
+	resp, err := r.do(req, data.Type)
+	if err != nil {
+		problem.StatusCode = erroredStatusCode(resp)
+		problem.Err = fmt.Errorf("GET error for file %v: %w", dataURL, err)
+		return &problem
+	}
+	defer resp.Body.Close()
+	problem.StatusCode = resp.StatusCode
+
+	if resp.StatusCode != http.StatusOK {
+		problem.Err = fmt.Errorf("Cannot fetch %v: %d", dataURL, resp.StatusCode)
+		return &problem
+	}
+
+	size, err := io.Copy(hash, resp.Body)
+	if err != nil {
+		problem.Err = fmt.Errorf("error reading %v: %w", dataURL, err)
+		return &problem
+	}
+
+	if data.Size != 0 && size != data.Size {
+		problem.Err = fmt.Errorf("size mismatch: expected %d, got %d", data.Size, size)
+		return &problem
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(sum, data.Checksum.Value) {
+		problem.Err = fmt.Errorf("checksum mismatch: expected %v, got %v", data.Checksum.Value, sum)
+		return &problem
+	}
+	return nil
+}
+
+// verifySignature checks the detached repomd.xml.asc signature against a keyring of armored keys
+func (r *Repository) verifySignature(ctx context.Context, gpgKeys []string) *VerifyProblem {
+	sigURL, _ := r.getSignatureURL()
+	problem := VerifyProblem{Type: "signature", URL: sigURL}
+
+	sig, code, err := r.Signature(ctx)
+	problem.StatusCode = code
+	if err != nil {
+		problem.Err = fmt.Errorf("error fetching signature: %w", err)
+		return &problem
+	}
+
+	keyRing, err := readKeyRing(gpgKeys)
+	if err != nil {
+		problem.Err = err
+		return &problem
+	}
+
+	_, err = openpgp.CheckArmoredDetachedSignature(keyRing, strings.NewReader(*r.repomd.RepomdString), strings.NewReader(*sig), nil)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, pgperrors.ErrKeyExpired):
+		problem.Err = fmt.Errorf("signature verification failed: %w: %v", ErrGPGKeyExpired, err)
+	case errors.Is(err, pgperrors.ErrKeyRevoked):
+		problem.Err = fmt.Errorf("signature verification failed: %w: %v", ErrGPGKeyRevoked, err)
+	default:
+		problem.Err = fmt.Errorf("signature verification failed: %w", err)
+	}
+	return &problem
+}
+
+// readKeyRing combines armored keys, each of which may hold several keys, into a single keyring
+func readKeyRing(gpgKeys []string) (openpgp.EntityList, error) {
+	var keyRing openpgp.EntityList
+	for _, gpgKey := range gpgKeys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(gpgKey))
+		if err != nil {
+			return nil, fmt.Errorf("error reading gpg key: %w", err)
+		}
+		keyRing = append(keyRing, entities...)
+	}
+	return keyRing, nil
+}
@@ -0,0 +1,151 @@
+package yum
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	entity, err := openpgp.NewEntity("yummy", "test", "yummy@example.com", nil)
+	require.NoError(t, err)
+
+	s := signedRepomdServer(t, entity, nil)
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	report := r.Verify(context.Background(), Ptr(armoredPublicKey(t, entity)))
+	assert.True(t, report.OK())
+	assert.NoError(t, report.Err())
+
+	// the mock repository's key does not match the signing key
+	r.Clear()
+	report = r.Verify(context.Background(), Ptr(string(gpgKey)))
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "signature", report.Problems[0].Type)
+}
+
+func TestVerifyKeyring(t *testing.T) {
+	entity, err := openpgp.NewEntity("yummy", "test", "yummy@example.com", nil)
+	require.NoError(t, err)
+
+	s := signedRepomdServer(t, entity, nil)
+	defer s.Close()
+
+	// any key of the keyring may have signed the repomd
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, GPGKeys: []string{string(gpgKey), armoredPublicKey(t, entity)}})
+	report := r.Verify(context.Background(), nil)
+	assert.True(t, report.OK())
+
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, GPGKeys: []string{string(gpgKey)}})
+	report = r.Verify(context.Background(), nil)
+	require.Len(t, report.Problems, 1)
+	assert.NotErrorIs(t, report.Problems[0].Err, ErrGPGKeyExpired)
+	assert.NotErrorIs(t, report.Problems[0].Err, ErrGPGKeyRevoked)
+}
+
+func TestVerifyExpiredAndRevokedKeys(t *testing.T) {
+	past := &packet.Config{Time: func() time.Time { return time.Now().Add(-48 * time.Hour) }, KeyLifetimeSecs: 3600}
+	expired, err := openpgp.NewEntity("yummy", "expired", "yummy@example.com", past)
+	require.NoError(t, err)
+
+	s := signedRepomdServer(t, expired, past)
+	defer s.Close()
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, GPGKeys: []string{armoredPublicKey(t, expired)}})
+	report := r.Verify(context.Background(), nil)
+	require.Len(t, report.Problems, 1)
+	assert.ErrorIs(t, report.Problems[0].Err, ErrGPGKeyExpired)
+
+	revoked, err := openpgp.NewEntity("yummy", "revoked", "yummy@example.com", nil)
+	require.NoError(t, err)
+
+	// the key is revoked after signing the repomd
+	s = signedRepomdServer(t, revoked, nil)
+	defer s.Close()
+	require.NoError(t, revoked.RevokeKey(packet.KeyCompromised, "test", nil))
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: &s.URL, GPGKeys: []string{armoredPublicKey(t, revoked)}})
+	report = r.Verify(context.Background(), nil)
+	require.Len(t, report.Problems, 1)
+	assert.ErrorIs(t, report.Problems[0].Err, ErrGPGKeyRevoked)
+}
+
+// signedRepomdServer serves a repository with a valid primary.xml and a repomd.xml signed by entity
+func signedRepomdServer(t *testing.T, entity *openpgp.Entity, config *packet.Config) *httptest.Server {
+	sum := sha256.Sum256(primaryXML)
+	repomd := fmt.Sprintf(`<repomd xmlns="http://linux.duke.edu/metadata/repo">
+<revision>1</revision>
+<data type="primary">
+<checksum type="sha256">%v</checksum>
+<size>%d</size>
+<location href="repodata/primary.xml.gz"/>
+</data>
+</repomd>`, hex.EncodeToString(sum[:]), len(primaryXML))
+
+	var sig bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&sig, entity, strings.NewReader(repomd), config))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(repomd))
+	})
+	mux.HandleFunc("/repodata/repomd.xml.asc", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(sig.Bytes())
+	})
+	mux.HandleFunc("/repodata/primary.xml.gz", servePrimaryXML)
+	return httptest.NewServer(mux)
+}
+
+func armoredPublicKey(t *testing.T, entity *openpgp.Entity) string {
+	var pubKey bytes.Buffer
+	w, err := armor.Encode(&pubKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	return pubKey.String()
+}
+
+func TestVerifyReportsAllProblems(t *testing.T) {
+	s := server()
+	defer s.Close()
+
+	r, _ := NewRepository(YummySettings{Client: s.Client(), URL: &s.URL})
+	report := r.Verify(context.Background(), nil)
+	assert.False(t, report.OK())
+	assert.Error(t, report.Err())
+
+	problems := map[string]VerifyProblem{}
+	for _, p := range report.Problems {
+		problems[p.Type] = p
+	}
+	// not served by the mock server
+	assert.Equal(t, http.StatusNotFound, problems["other"].StatusCode)
+	assert.Equal(t, http.StatusNotFound, problems["filelists"].StatusCode)
+
+	var multiErr *MultiError
+	require.ErrorAs(t, report.Err(), &multiErr)
+	assert.Len(t, multiErr.Errors, len(report.Problems))
+	// served, but the mock repomd.xml checksums do not match the mock files
+	assert.Equal(t, http.StatusOK, problems["primary"].StatusCode)
+	assert.ErrorContains(t, problems["primary"].Err, "size mismatch")
+	assert.ErrorContains(t, problems["group"].Err, "size mismatch")
+
+	// an unreachable repomd is reported on its own
+	r, _ = NewRepository(YummySettings{Client: s.Client(), URL: Ptr(s.URL + "/missing")})
+	report = r.Verify(context.Background(), nil)
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "repomd", report.Problems[0].Type)
+	assert.Equal(t, http.StatusNotFound, report.Problems[0].StatusCode)
+}
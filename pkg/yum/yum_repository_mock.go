@@ -4,6 +4,8 @@ package yum
 
 import (
 	context "context"
+	io "io"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,30 +15,106 @@ type MockYumRepository struct {
 	mock.Mock
 }
 
-// Clear provides a mock function with no fields
-func (_m *MockYumRepository) Clear() {
-	_m.Called()
+// Advisories provides a mock function with given fields: ctx, opts
+func (_m *MockYumRepository) Advisories(ctx context.Context, opts ...AdvisoryOption) ([]Advisory, int, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Advisories")
+	}
+
+	var r0 []Advisory
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...AdvisoryOption) ([]Advisory, int, error)); ok {
+		return rf(ctx, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...AdvisoryOption) []Advisory); ok {
+		r0 = rf(ctx, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Advisory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...AdvisoryOption) int); ok {
+		r1 = rf(ctx, opts...)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ...AdvisoryOption) error); ok {
+		r2 = rf(ctx, opts...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// Comps provides a mock function with given fields: ctx
-func (_m *MockYumRepository) Comps(ctx context.Context) (*Comps, int, error) {
+// AdvisoriesForPackage provides a mock function with given fields: ctx, nevra
+func (_m *MockYumRepository) AdvisoriesForPackage(ctx context.Context, nevra NEVRA) ([]Advisory, int, error) {
+	ret := _m.Called(ctx, nevra)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdvisoriesForPackage")
+	}
+
+	var r0 []Advisory
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, NEVRA) ([]Advisory, int, error)); ok {
+		return rf(ctx, nevra)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, NEVRA) []Advisory); ok {
+		r0 = rf(ctx, nevra)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Advisory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, NEVRA) int); ok {
+		r1 = rf(ctx, nevra)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, NEVRA) error); ok {
+		r2 = rf(ctx, nevra)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Applications provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Applications(ctx context.Context) ([]Application, int, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Comps")
+		panic("no return value specified for Applications")
 	}
 
-	var r0 *Comps
+	var r0 []Application
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*Comps, int, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) ([]Application, int, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) *Comps); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) []Application); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*Comps)
+			r0 = ret.Get(0).([]Application)
 		}
 	}
 
@@ -55,30 +133,25 @@ func (_m *MockYumRepository) Comps(ctx context.Context) (*Comps, int, error) {
 	return r0, r1, r2
 }
 
-// Configure provides a mock function with given fields: settings
-func (_m *MockYumRepository) Configure(settings YummySettings) {
-	_m.Called(settings)
-}
-
-// Environments provides a mock function with given fields: ctx
-func (_m *MockYumRepository) Environments(ctx context.Context) ([]Environment, int, error) {
+// Categories provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Categories(ctx context.Context) ([]Category, int, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Environments")
+		panic("no return value specified for Categories")
 	}
 
-	var r0 []Environment
+	var r0 []Category
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]Environment, int, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) ([]Category, int, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []Environment); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) []Category); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Environment)
+			r0 = ret.Get(0).([]Category)
 		}
 	}
 
@@ -97,25 +170,86 @@ func (_m *MockYumRepository) Environments(ctx context.Context) ([]Environment, i
 	return r0, r1, r2
 }
 
-// ModuleMDs provides a mock function with given fields: ctx
-func (_m *MockYumRepository) ModuleMDs(ctx context.Context) ([]ModuleMD, int, error) {
+// Checksums provides a mock function with given fields: metadataType
+func (_m *MockYumRepository) Checksums(metadataType string) (MetadataChecksums, bool) {
+	ret := _m.Called(metadataType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Checksums")
+	}
+
+	var r0 MetadataChecksums
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) (MetadataChecksums, bool)); ok {
+		return rf(metadataType)
+	}
+	if rf, ok := ret.Get(0).(func(string) MetadataChecksums); ok {
+		r0 = rf(metadataType)
+	} else {
+		r0 = ret.Get(0).(MetadataChecksums)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(metadataType)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// Clear provides a mock function with no fields
+func (_m *MockYumRepository) Clear() {
+	_m.Called()
+}
+
+// CloneTo provides a mock function with given fields: ctx, dir, opts
+func (_m *MockYumRepository) CloneTo(ctx context.Context, dir string, opts SyncOptions) (CloneReport, error) {
+	ret := _m.Called(ctx, dir, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloneTo")
+	}
+
+	var r0 CloneReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, SyncOptions) (CloneReport, error)); ok {
+		return rf(ctx, dir, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, SyncOptions) CloneReport); ok {
+		r0 = rf(ctx, dir, opts)
+	} else {
+		r0 = ret.Get(0).(CloneReport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, SyncOptions) error); ok {
+		r1 = rf(ctx, dir, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Comps provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Comps(ctx context.Context) (*Comps, int, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ModuleMDs")
+		panic("no return value specified for Comps")
 	}
 
-	var r0 []ModuleMD
+	var r0 *Comps
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]ModuleMD, int, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) (*Comps, int, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []ModuleMD); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) *Comps); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]ModuleMD)
+			r0 = ret.Get(0).(*Comps)
 		}
 	}
 
@@ -134,25 +268,30 @@ func (_m *MockYumRepository) ModuleMDs(ctx context.Context) ([]ModuleMD, int, er
 	return r0, r1, r2
 }
 
-// PackageGroups provides a mock function with given fields: ctx
-func (_m *MockYumRepository) PackageGroups(ctx context.Context) ([]PackageGroup, int, error) {
+// Configure provides a mock function with given fields: settings
+func (_m *MockYumRepository) Configure(settings YummySettings) {
+	_m.Called(settings)
+}
+
+// DeltaPackages provides a mock function with given fields: ctx
+func (_m *MockYumRepository) DeltaPackages(ctx context.Context) ([]DeltaPackage, int, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PackageGroups")
+		panic("no return value specified for DeltaPackages")
 	}
 
-	var r0 []PackageGroup
+	var r0 []DeltaPackage
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]PackageGroup, int, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) ([]DeltaPackage, int, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []PackageGroup); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) []DeltaPackage); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]PackageGroup)
+			r0 = ret.Get(0).([]DeltaPackage)
 		}
 	}
 
@@ -171,25 +310,95 @@ func (_m *MockYumRepository) PackageGroups(ctx context.Context) ([]PackageGroup,
 	return r0, r1, r2
 }
 
-// Packages provides a mock function with given fields: ctx
-func (_m *MockYumRepository) Packages(ctx context.Context) ([]Package, int, error) {
+// DownloadPackage provides a mock function with given fields: ctx, pkg, dest
+func (_m *MockYumRepository) DownloadPackage(ctx context.Context, pkg Package, dest io.Writer) (int64, int, error) {
+	ret := _m.Called(ctx, pkg, dest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DownloadPackage")
+	}
+
+	var r0 int64
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, Package, io.Writer) (int64, int, error)); ok {
+		return rf(ctx, pkg, dest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, Package, io.Writer) int64); ok {
+		r0 = rf(ctx, pkg, dest)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, Package, io.Writer) int); ok {
+		r1 = rf(ctx, pkg, dest)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, Package, io.Writer) error); ok {
+		r2 = rf(ctx, pkg, dest)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DownloadPackageToFile provides a mock function with given fields: ctx, pkg, path
+func (_m *MockYumRepository) DownloadPackageToFile(ctx context.Context, pkg Package, path string) (int64, int, error) {
+	ret := _m.Called(ctx, pkg, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DownloadPackageToFile")
+	}
+
+	var r0 int64
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, Package, string) (int64, int, error)); ok {
+		return rf(ctx, pkg, path)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, Package, string) int64); ok {
+		r0 = rf(ctx, pkg, path)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, Package, string) int); ok {
+		r1 = rf(ctx, pkg, path)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, Package, string) error); ok {
+		r2 = rf(ctx, pkg, path)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Environments provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Environments(ctx context.Context) ([]Environment, int, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Packages")
+		panic("no return value specified for Environments")
 	}
 
-	var r0 []Package
+	var r0 []Environment
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]Package, int, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) ([]Environment, int, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []Package); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) []Environment); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Package)
+			r0 = ret.Get(0).([]Environment)
 		}
 	}
 
@@ -208,25 +417,73 @@ func (_m *MockYumRepository) Packages(ctx context.Context) ([]Package, int, erro
 	return r0, r1, r2
 }
 
-// Repomd provides a mock function with given fields: ctx
-func (_m *MockYumRepository) Repomd(ctx context.Context) (*Repomd, int, error) {
+// Export provides a mock function with given fields: ctx, w
+func (_m *MockYumRepository) Export(ctx context.Context, w io.Writer) error {
+	ret := _m.Called(ctx, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer) error); ok {
+		r0 = rf(ctx, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: ctx
+func (_m *MockYumRepository) FetchAll(ctx context.Context) (map[string]FetchResult, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Repomd")
+		panic("no return value specified for FetchAll")
 	}
 
-	var r0 *Repomd
+	var r0 map[string]FetchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]FetchResult, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]FetchResult); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]FetchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Filelists provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Filelists(ctx context.Context) ([]PackageFiles, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Filelists")
+	}
+
+	var r0 []PackageFiles
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*Repomd, int, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) ([]PackageFiles, int, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) *Repomd); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) []PackageFiles); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*Repomd)
+			r0 = ret.Get(0).([]PackageFiles)
 		}
 	}
 
@@ -245,12 +502,12 @@ func (_m *MockYumRepository) Repomd(ctx context.Context) (*Repomd, int, error) {
 	return r0, r1, r2
 }
 
-// Signature provides a mock function with given fields: ctx
-func (_m *MockYumRepository) Signature(ctx context.Context) (*string, int, error) {
+// GPGKey provides a mock function with given fields: ctx
+func (_m *MockYumRepository) GPGKey(ctx context.Context) (*string, int, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Signature")
+		panic("no return value specified for GPGKey")
 	}
 
 	var r0 *string
@@ -282,6 +539,825 @@ func (_m *MockYumRepository) Signature(ctx context.Context) (*string, int, error
 	return r0, r1, r2
 }
 
+// IsStale provides a mock function with given fields: ctx, maxAge
+func (_m *MockYumRepository) IsStale(ctx context.Context, maxAge time.Duration) (bool, int, error) {
+	ret := _m.Called(ctx, maxAge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsStale")
+	}
+
+	var r0 bool
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (bool, int, error)); ok {
+		return rf(ctx, maxAge)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) bool); ok {
+		r0 = rf(ctx, maxAge)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) int); ok {
+		r1 = rf(ctx, maxAge)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Duration) error); ok {
+		r2 = rf(ctx, maxAge)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MirrorMetadata provides a mock function with given fields: ctx, dir
+func (_m *MockYumRepository) MirrorMetadata(ctx context.Context, dir string) (SyncResult, error) {
+	ret := _m.Called(ctx, dir)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MirrorMetadata")
+	}
+
+	var r0 SyncResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (SyncResult, error)); ok {
+		return rf(ctx, dir)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) SyncResult); ok {
+		r0 = rf(ctx, dir)
+	} else {
+		r0 = ret.Get(0).(SyncResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, dir)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ModuleMDs provides a mock function with given fields: ctx
+func (_m *MockYumRepository) ModuleMDs(ctx context.Context) ([]ModuleMD, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ModuleMDs")
+	}
+
+	var r0 []ModuleMD
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]ModuleMD, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []ModuleMD); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]ModuleMD)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ModuleStreams provides a mock function with given fields: ctx
+func (_m *MockYumRepository) ModuleStreams(ctx context.Context) ([]ModuleStream, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ModuleStreams")
+	}
+
+	var r0 []ModuleStream
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]ModuleStream, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []ModuleStream); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]ModuleStream)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ModuleTranslations provides a mock function with given fields: ctx
+func (_m *MockYumRepository) ModuleTranslations(ctx context.Context) (ModuleTranslations, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ModuleTranslations")
+	}
+
+	var r0 ModuleTranslations
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (ModuleTranslations, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) ModuleTranslations); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ModuleTranslations)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PackageGroups provides a mock function with given fields: ctx
+func (_m *MockYumRepository) PackageGroups(ctx context.Context) ([]PackageGroup, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PackageGroups")
+	}
+
+	var r0 []PackageGroup
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]PackageGroup, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []PackageGroup); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]PackageGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PackageSummary provides a mock function with given fields: ctx
+func (_m *MockYumRepository) PackageSummary(ctx context.Context) (*PackageSummary, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PackageSummary")
+	}
+
+	var r0 *PackageSummary
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*PackageSummary, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *PackageSummary); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*PackageSummary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Packages provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Packages(ctx context.Context) ([]Package, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Packages")
+	}
+
+	var r0 []Package
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]Package, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []Package); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PatchStatus provides a mock function with given fields: ctx, installed
+func (_m *MockYumRepository) PatchStatus(ctx context.Context, installed []NEVRA) (*PatchReport, int, error) {
+	ret := _m.Called(ctx, installed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchStatus")
+	}
+
+	var r0 *PatchReport
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []NEVRA) (*PatchReport, int, error)); ok {
+		return rf(ctx, installed)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []NEVRA) *PatchReport); ok {
+		r0 = rf(ctx, installed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*PatchReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []NEVRA) int); ok {
+		r1 = rf(ctx, installed)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []NEVRA) error); ok {
+		r2 = rf(ctx, installed)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Probe provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Probe(ctx context.Context) (bool, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Probe")
+	}
+
+	var r0 bool
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (bool, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) bool); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Products provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Products(ctx context.Context) ([]Product, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Products")
+	}
+
+	var r0 []Product
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]Product, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []Product); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Repomd provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Repomd(ctx context.Context) (*Repomd, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Repomd")
+	}
+
+	var r0 *Repomd
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*Repomd, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *Repomd); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Repomd)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ResolveEnvironment provides a mock function with given fields: ctx, envID
+func (_m *MockYumRepository) ResolveEnvironment(ctx context.Context, envID string) (*EnvironmentResolution, int, error) {
+	ret := _m.Called(ctx, envID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveEnvironment")
+	}
+
+	var r0 *EnvironmentResolution
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*EnvironmentResolution, int, error)); ok {
+		return rf(ctx, envID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *EnvironmentResolution); ok {
+		r0 = rf(ctx, envID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*EnvironmentResolution)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) int); ok {
+		r1 = rf(ctx, envID)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, envID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ResolveGroup provides a mock function with given fields: ctx, groupID, includeOptional
+func (_m *MockYumRepository) ResolveGroup(ctx context.Context, groupID string, includeOptional bool) ([]Package, int, error) {
+	ret := _m.Called(ctx, groupID, includeOptional)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveGroup")
+	}
+
+	var r0 []Package
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) ([]Package, int, error)); ok {
+		return rf(ctx, groupID, includeOptional)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) []Package); ok {
+		r0 = rf(ctx, groupID, includeOptional)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) int); ok {
+		r1 = rf(ctx, groupID, includeOptional)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, bool) error); ok {
+		r2 = rf(ctx, groupID, includeOptional)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ResolveModuleProfile provides a mock function with given fields: ctx, module, stream, profile
+func (_m *MockYumRepository) ResolveModuleProfile(ctx context.Context, module string, stream string, profile string) ([]Package, int, error) {
+	ret := _m.Called(ctx, module, stream, profile)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveModuleProfile")
+	}
+
+	var r0 []Package
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]Package, int, error)); ok {
+		return rf(ctx, module, stream, profile)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []Package); ok {
+		r0 = rf(ctx, module, stream, profile)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) int); ok {
+		r1 = rf(ctx, module, stream, profile)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string) error); ok {
+		r2 = rf(ctx, module, stream, profile)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ResponseHeaders provides a mock function with given fields: metadataType
+func (_m *MockYumRepository) ResponseHeaders(metadataType string) (ResponseHeaders, bool) {
+	ret := _m.Called(metadataType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResponseHeaders")
+	}
+
+	var r0 ResponseHeaders
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) (ResponseHeaders, bool)); ok {
+		return rf(metadataType)
+	}
+	if rf, ok := ret.Get(0).(func(string) ResponseHeaders); ok {
+		r0 = rf(metadataType)
+	} else {
+		r0 = ret.Get(0).(ResponseHeaders)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(metadataType)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// Signature provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Signature(ctx context.Context) (*string, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Signature")
+	}
+
+	var r0 *string
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*string, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SpillPackages provides a mock function with given fields: ctx, dir
+func (_m *MockYumRepository) SpillPackages(ctx context.Context, dir string) (*SpilledPackages, int, error) {
+	ret := _m.Called(ctx, dir)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SpillPackages")
+	}
+
+	var r0 *SpilledPackages
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*SpilledPackages, int, error)); ok {
+		return rf(ctx, dir)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *SpilledPackages); ok {
+		r0 = rf(ctx, dir)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*SpilledPackages)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) int); ok {
+		r1 = rf(ctx, dir)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, dir)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Stats provides a mock function with no fields
+func (_m *MockYumRepository) Stats() Stats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 Stats
+	if rf, ok := ret.Get(0).(func() Stats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(Stats)
+	}
+
+	return r0
+}
+
+// Sync provides a mock function with given fields: ctx, dir, opts
+func (_m *MockYumRepository) Sync(ctx context.Context, dir string, opts SyncOptions) (SyncResult, error) {
+	ret := _m.Called(ctx, dir, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Sync")
+	}
+
+	var r0 SyncResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, SyncOptions) (SyncResult, error)); ok {
+		return rf(ctx, dir, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, SyncOptions) SyncResult); ok {
+		r0 = rf(ctx, dir, opts)
+	} else {
+		r0 = ret.Get(0).(SyncResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, SyncOptions) error); ok {
+		r1 = rf(ctx, dir, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Treeinfo provides a mock function with given fields: ctx
+func (_m *MockYumRepository) Treeinfo(ctx context.Context) (*Treeinfo, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Treeinfo")
+	}
+
+	var r0 *Treeinfo
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*Treeinfo, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *Treeinfo); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Treeinfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Verify provides a mock function with given fields: ctx, gpgKey
+func (_m *MockYumRepository) Verify(ctx context.Context, gpgKey *string) VerifyReport {
+	ret := _m.Called(ctx, gpgKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 VerifyReport
+	if rf, ok := ret.Get(0).(func(context.Context, *string) VerifyReport); ok {
+		r0 = rf(ctx, gpgKey)
+	} else {
+		r0 = ret.Get(0).(VerifyReport)
+	}
+
+	return r0
+}
+
+// WeakChecksums provides a mock function with given fields: ctx
+func (_m *MockYumRepository) WeakChecksums(ctx context.Context) ([]WeakChecksum, int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WeakChecksums")
+	}
+
+	var r0 []WeakChecksum
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]WeakChecksum, int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []WeakChecksum); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]WeakChecksum)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) int); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// WhatProvides provides a mock function with given fields: ctx, capability
+func (_m *MockYumRepository) WhatProvides(ctx context.Context, capability string) ([]Package, int, error) {
+	ret := _m.Called(ctx, capability)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WhatProvides")
+	}
+
+	var r0 []Package
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]Package, int, error)); ok {
+		return rf(ctx, capability)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []Package); ok {
+		r0 = rf(ctx, capability)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) int); ok {
+		r1 = rf(ctx, capability)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, capability)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // NewMockYumRepository creates a new instance of MockYumRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockYumRepository(t interface {
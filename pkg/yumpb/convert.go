@@ -0,0 +1,280 @@
+// Package yumpb holds the protobuf messages of the core yum models, defined in proto/yummy/v1/yummy.proto,
+// and the functions converting between the messages and the models
+package yumpb
+
+import (
+	"github.com/content-services/yummy/pkg/yum"
+)
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=module=github.com/content-services/yummy/pkg/yumpb yummy/v1/yummy.proto
+
+// FromPackage converts a package to its message
+func FromPackage(pkg yum.Package) *Package {
+	return &Package{
+		Type:      pkg.Type,
+		Name:      pkg.Name,
+		Arch:      pkg.Arch,
+		Version:   &Version{Version: pkg.Version.Version, Release: pkg.Version.Release, Epoch: pkg.Version.Epoch},
+		Checksum:  fromChecksum(pkg.Checksum),
+		Summary:   pkg.Summary,
+		Size:      &PackageSize{Package: pkg.Size.Package, Installed: pkg.Size.Installed, Archive: pkg.Size.Archive},
+		Location:  pkg.Location.Href,
+		License:   pkg.License,
+		SourceRpm: pkg.SourceRPM,
+	}
+}
+
+// ToPackage converts a package message to a package
+func ToPackage(msg *Package) yum.Package {
+	return yum.Package{
+		Type:     msg.GetType(),
+		Name:     msg.GetName(),
+		Arch:     msg.GetArch(),
+		Version:  yum.Version{Version: msg.GetVersion().GetVersion(), Release: msg.GetVersion().GetRelease(), Epoch: msg.GetVersion().GetEpoch()},
+		Checksum: toChecksum(msg.GetChecksum()),
+		Summary:  msg.GetSummary(),
+		Size: yum.PackageSize{
+			Package:   msg.GetSize().GetPackage(),
+			Installed: msg.GetSize().GetInstalled(),
+			Archive:   msg.GetSize().GetArchive(),
+		},
+		Location:  yum.Location{Href: msg.GetLocation()},
+		License:   msg.GetLicense(),
+		SourceRPM: msg.GetSourceRpm(),
+	}
+}
+
+// FromAdvisory converts an advisory to its message
+func FromAdvisory(advisory yum.Advisory) *Advisory {
+	msg := &Advisory{
+		Id:          advisory.ID,
+		Type:        string(advisory.Type),
+		Status:      advisory.Status,
+		From:        advisory.From,
+		Version:     advisory.Version,
+		Title:       advisory.Title,
+		Severity:    Severity(advisory.Severity),
+		Release:     advisory.Release,
+		Issued:      advisory.Issued.Date,
+		Updated:     advisory.Updated.Date,
+		Rights:      advisory.Rights,
+		Summary:     advisory.Summary,
+		Description: advisory.Description,
+		Solution:    advisory.Solution,
+	}
+	for _, reference := range advisory.References {
+		msg.References = append(msg.References, &Reference{Type: string(reference.Type), Id: reference.ID, Url: reference.URL, Title: reference.Title})
+	}
+	for _, pkg := range advisory.Packages {
+		msgPkg := &AdvisoryPackage{
+			Name:            pkg.Name,
+			Epoch:           pkg.Epoch,
+			Version:         pkg.Version,
+			Release:         pkg.Release,
+			Arch:            pkg.Arch,
+			Src:             pkg.Src,
+			Filename:        pkg.Filename,
+			RebootSuggested: pkg.RebootSuggested,
+		}
+		for _, checksum := range pkg.Checksums {
+			msgPkg.Checksums = append(msgPkg.Checksums, fromChecksum(checksum))
+		}
+		msg.Packages = append(msg.Packages, msgPkg)
+	}
+	return msg
+}
+
+// ToAdvisory converts an advisory message to an advisory
+func ToAdvisory(msg *Advisory) yum.Advisory {
+	advisory := yum.Advisory{
+		ID:          msg.GetId(),
+		Type:        yum.AdvisoryType(msg.GetType()),
+		Status:      msg.GetStatus(),
+		From:        msg.GetFrom(),
+		Version:     msg.GetVersion(),
+		Title:       msg.GetTitle(),
+		Severity:    yum.Severity(msg.GetSeverity()),
+		Release:     msg.GetRelease(),
+		Issued:      yum.AdvisoryDate{Date: msg.GetIssued()},
+		Updated:     yum.AdvisoryDate{Date: msg.GetUpdated()},
+		Rights:      msg.GetRights(),
+		Summary:     msg.GetSummary(),
+		Description: msg.GetDescription(),
+		Solution:    msg.GetSolution(),
+	}
+	for _, reference := range msg.GetReferences() {
+		advisory.References = append(advisory.References, yum.Reference{
+			Type:  yum.ReferenceType(reference.GetType()),
+			ID:    reference.GetId(),
+			URL:   reference.GetUrl(),
+			Title: reference.GetTitle(),
+		})
+	}
+	for _, msgPkg := range msg.GetPackages() {
+		pkg := yum.AdvisoryPackage{
+			Name:            msgPkg.GetName(),
+			Epoch:           msgPkg.GetEpoch(),
+			Version:         msgPkg.GetVersion(),
+			Release:         msgPkg.GetRelease(),
+			Arch:            msgPkg.GetArch(),
+			Src:             msgPkg.GetSrc(),
+			Filename:        msgPkg.GetFilename(),
+			RebootSuggested: msgPkg.GetRebootSuggested(),
+		}
+		for _, checksum := range msgPkg.GetChecksums() {
+			pkg.Checksums = append(pkg.Checksums, toChecksum(checksum))
+		}
+		advisory.Packages = append(advisory.Packages, pkg)
+	}
+	return advisory
+}
+
+// FromModuleStream converts a module and its streams to its message. The components of the streams and
+// the rpm map of their artifacts are not converted.
+func FromModuleStream(moduleStream yum.ModuleStream) *ModuleStream {
+	msg := &ModuleStream{Name: moduleStream.Name}
+	for _, stream := range moduleStream.Streams {
+		msg.Streams = append(msg.Streams, FromStream(stream))
+	}
+	return msg
+}
+
+// ToModuleStream converts a module stream message to a module and its streams
+func ToModuleStream(msg *ModuleStream) yum.ModuleStream {
+	moduleStream := yum.ModuleStream{Name: msg.GetName()}
+	for _, stream := range msg.GetStreams() {
+		moduleStream.Streams = append(moduleStream.Streams, ToStream(stream))
+	}
+	return moduleStream
+}
+
+// FromStream converts a module stream to its message. Components and the rpm map of the artifacts are
+// not converted.
+func FromStream(stream yum.Stream) *Stream {
+	msg := &Stream{
+		Name:          stream.Name,
+		Stream:        stream.Stream,
+		Version:       stream.Version,
+		Context:       stream.Context,
+		Arch:          stream.Arch,
+		Summary:       stream.Summary,
+		Description:   stream.Description,
+		StaticContext: stream.StaticContext,
+		License:       &License{Module: stream.License.Module, Content: stream.License.Content},
+		ArtifactRpms:  stream.Artifacts.Rpms,
+	}
+	for _, dependencies := range stream.Dependencies {
+		msg.Dependencies = append(msg.Dependencies, &Dependencies{
+			BuildRequires: fromStreamNames(dependencies.BuildRequires),
+			Requires:      fromStreamNames(dependencies.Requires),
+		})
+	}
+	if stream.Profiles != nil {
+		msg.Profiles = map[string]*RpmProfile{}
+		for name, profile := range stream.Profiles {
+			msg.Profiles[name] = &RpmProfile{Rpms: profile.Rpms}
+		}
+	}
+	return msg
+}
+
+// ToStream converts a stream message to a module stream
+func ToStream(msg *Stream) yum.Stream {
+	stream := yum.Stream{
+		Name:          msg.GetName(),
+		Stream:        msg.GetStream(),
+		Version:       msg.GetVersion(),
+		Context:       msg.GetContext(),
+		Arch:          msg.GetArch(),
+		Summary:       msg.GetSummary(),
+		Description:   msg.GetDescription(),
+		StaticContext: msg.GetStaticContext(),
+		License:       yum.License{Module: msg.GetLicense().GetModule(), Content: msg.GetLicense().GetContent()},
+		Artifacts:     yum.Artifacts{Rpms: msg.GetArtifactRpms()},
+	}
+	for _, dependencies := range msg.GetDependencies() {
+		stream.Dependencies = append(stream.Dependencies, yum.Dependencies{
+			BuildRequires: toStreamNames(dependencies.GetBuildRequires()),
+			Requires:      toStreamNames(dependencies.GetRequires()),
+		})
+	}
+	if msg.GetProfiles() != nil {
+		stream.Profiles = map[string]yum.RpmProfiles{}
+		for name, profile := range msg.GetProfiles() {
+			stream.Profiles[name] = yum.RpmProfiles{Rpms: profile.GetRpms()}
+		}
+	}
+	return stream
+}
+
+// FromPackageGroup converts a package group to its message
+func FromPackageGroup(group yum.PackageGroup) *PackageGroup {
+	msg := &PackageGroup{
+		Id:           group.ID,
+		Name:         fromLocalizedString(group.Name),
+		Description:  fromLocalizedString(group.Description),
+		Default:      group.Default,
+		UserVisible:  group.UserVisible,
+		DisplayOrder: int32(group.DisplayOrder),
+		BiarchOnly:   group.BiarchOnly,
+	}
+	for _, req := range group.PackageList {
+		msg.PackageList = append(msg.PackageList, &PackageReq{Name: req.Name, Type: req.Type, Requires: req.Requires})
+	}
+	return msg
+}
+
+// ToPackageGroup converts a package group message to a package group
+func ToPackageGroup(msg *PackageGroup) yum.PackageGroup {
+	group := yum.PackageGroup{
+		ID:           msg.GetId(),
+		Name:         toLocalizedString(msg.GetName()),
+		Description:  toLocalizedString(msg.GetDescription()),
+		Default:      msg.GetDefault(),
+		UserVisible:  msg.GetUserVisible(),
+		DisplayOrder: int(msg.GetDisplayOrder()),
+		BiarchOnly:   msg.GetBiarchOnly(),
+	}
+	for _, req := range msg.GetPackageList() {
+		group.PackageList = append(group.PackageList, yum.PackageReq{Name: req.GetName(), Type: req.GetType(), Requires: req.GetRequires()})
+	}
+	return group
+}
+
+func fromChecksum(checksum yum.Checksum) *Checksum {
+	return &Checksum{Type: checksum.Type, Value: checksum.Value}
+}
+
+func toChecksum(msg *Checksum) yum.Checksum {
+	return yum.Checksum{Type: msg.GetType(), Value: msg.GetValue()}
+}
+
+func fromLocalizedString(s yum.LocalizedString) *LocalizedString {
+	return &LocalizedString{Value: s.Value, Translations: s.Translations}
+}
+
+func toLocalizedString(msg *LocalizedString) yum.LocalizedString {
+	return yum.LocalizedString{Value: msg.GetValue(), Translations: msg.GetTranslations()}
+}
+
+func fromStreamNames(streams map[string][]string) map[string]*StreamNames {
+	if streams == nil {
+		return nil
+	}
+	msg := make(map[string]*StreamNames, len(streams))
+	for module, names := range streams {
+		msg[module] = &StreamNames{Streams: names}
+	}
+	return msg
+}
+
+func toStreamNames(msg map[string]*StreamNames) map[string][]string {
+	if msg == nil {
+		return nil
+	}
+	streams := make(map[string][]string, len(msg))
+	for module, names := range msg {
+		streams[module] = names.GetStreams()
+	}
+	return streams
+}
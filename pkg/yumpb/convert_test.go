@@ -0,0 +1,97 @@
+package yumpb
+
+import (
+	"testing"
+
+	"github.com/content-services/yummy/pkg/yum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// roundTrip serializes a message and parses it back
+func roundTrip[T proto.Message](t *testing.T, msg T, parsed T) T {
+	wire, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	require.NoError(t, proto.Unmarshal(wire, parsed))
+	return parsed
+}
+
+func TestPackage(t *testing.T) {
+	pkg := yum.Package{
+		Type:      "rpm",
+		Name:      "nss-devel",
+		Arch:      "i686",
+		Version:   yum.Version{Version: "3.19.1", Release: "18.el7", Epoch: 1},
+		Checksum:  yum.Checksum{Type: "sha256", Value: "abcd"},
+		Summary:   "Development libraries for Network Security Services",
+		Size:      yum.PackageSize{Package: 215192, Installed: 757126, Archive: 764528},
+		Location:  yum.Location{Href: "Packages/n/nss-devel-3.19.1-18.el7.i686.rpm"},
+		License:   "MPLv2.0",
+		SourceRPM: "nss-3.19.1-18.el7.src.rpm",
+	}
+	assert.Equal(t, pkg, ToPackage(roundTrip(t, FromPackage(pkg), &Package{})))
+}
+
+func TestAdvisory(t *testing.T) {
+	advisory := yum.Advisory{
+		ID:         "RHSA-2024:0001",
+		Type:       yum.AdvisorySecurity,
+		Status:     "final",
+		Title:      "Important: nss security update",
+		Severity:   yum.SeverityImportant,
+		Issued:     yum.AdvisoryDate{Date: "2024-01-01 00:00:00"},
+		Updated:    yum.AdvisoryDate{Date: "2024-01-02 00:00:00"},
+		References: []yum.Reference{{Type: yum.ReferenceCVE, ID: "CVE-2024-0001", URL: "https://example.com/CVE-2024-0001"}},
+		Packages: []yum.AdvisoryPackage{{
+			Name:            "nss",
+			Version:         "3.19.1",
+			Release:         "19.el7",
+			Arch:            "x86_64",
+			Filename:        "nss-3.19.1-19.el7.x86_64.rpm",
+			Checksums:       []yum.Checksum{{Type: "sha256", Value: "abcd"}},
+			RebootSuggested: true,
+		}},
+	}
+	msg := roundTrip(t, FromAdvisory(advisory), &Advisory{})
+	assert.Equal(t, Severity_SEVERITY_IMPORTANT, msg.GetSeverity())
+	assert.Equal(t, advisory, ToAdvisory(msg))
+}
+
+func TestModuleStream(t *testing.T) {
+	moduleStream := yum.ModuleStream{
+		Name: "nodejs",
+		Streams: []yum.Stream{{
+			Name:    "nodejs",
+			Stream:  "18",
+			Version: "8070020230306170042",
+			Context: "ad008a3a",
+			Arch:    "x86_64",
+			Summary: "Javascript runtime",
+			License: yum.License{Module: []string{"MIT"}},
+			Dependencies: []yum.Dependencies{{
+				BuildRequires: map[string][]string{"platform": {"el8.7.0"}},
+				Requires:      map[string][]string{"platform": {"el8"}},
+			}},
+			Profiles:  map[string]yum.RpmProfiles{"common": {Rpms: []string{"nodejs", "npm"}}},
+			Artifacts: yum.Artifacts{Rpms: []string{"nodejs-1:18.14.2-2.module+el8.7.0+18113+f1e9e2f0.x86_64"}},
+		}},
+	}
+	assert.Equal(t, moduleStream, ToModuleStream(roundTrip(t, FromModuleStream(moduleStream), &ModuleStream{})))
+}
+
+func TestPackageGroup(t *testing.T) {
+	group := yum.PackageGroup{
+		ID:           "firefox",
+		Name:         yum.LocalizedString{Value: "Firefox Web Browser", Translations: map[string]string{"de": "Firefox-Webbrowser"}},
+		Description:  yum.LocalizedString{Value: "The Firefox web browser"},
+		Default:      true,
+		UserVisible:  true,
+		DisplayOrder: 10,
+		PackageList: []yum.PackageReq{
+			{Name: "firefox", Type: yum.PackageReqMandatory},
+			{Name: "firefox-langpack-de", Type: yum.PackageReqConditional, Requires: "langpacks-de"},
+		},
+	}
+	assert.Equal(t, group, ToPackageGroup(roundTrip(t, FromPackageGroup(group), &PackageGroup{})))
+}
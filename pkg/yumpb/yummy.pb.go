@@ -0,0 +1,1522 @@
+// Messages mirroring the core models of the yum package, so that gRPC services built on yummy share
+// a single schema. Convert to and from the Go models with the functions of the yumpb package.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: yummy/v1/yummy.proto
+
+package yumpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Severity int32
+
+const (
+	Severity_SEVERITY_NONE      Severity = 0
+	Severity_SEVERITY_LOW       Severity = 1
+	Severity_SEVERITY_MODERATE  Severity = 2
+	Severity_SEVERITY_IMPORTANT Severity = 3
+	Severity_SEVERITY_CRITICAL  Severity = 4
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_NONE",
+		1: "SEVERITY_LOW",
+		2: "SEVERITY_MODERATE",
+		3: "SEVERITY_IMPORTANT",
+		4: "SEVERITY_CRITICAL",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_NONE":      0,
+		"SEVERITY_LOW":       1,
+		"SEVERITY_MODERATE":  2,
+		"SEVERITY_IMPORTANT": 3,
+		"SEVERITY_CRITICAL":  4,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_yummy_v1_yummy_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_yummy_v1_yummy_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{0}
+}
+
+type Version struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Release       string                 `protobuf:"bytes,2,opt,name=release,proto3" json:"release,omitempty"`
+	Epoch         int32                  `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Version) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Version) GetRelease() string {
+	if x != nil {
+		return x.Release
+	}
+	return ""
+}
+
+func (x *Version) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+type Checksum struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Checksum) Reset() {
+	*x = Checksum{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Checksum) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Checksum) ProtoMessage() {}
+
+func (x *Checksum) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Checksum.ProtoReflect.Descriptor instead.
+func (*Checksum) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Checksum) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Checksum) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type PackageSize struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Package       int64                  `protobuf:"varint,1,opt,name=package,proto3" json:"package,omitempty"`
+	Installed     int64                  `protobuf:"varint,2,opt,name=installed,proto3" json:"installed,omitempty"`
+	Archive       int64                  `protobuf:"varint,3,opt,name=archive,proto3" json:"archive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackageSize) Reset() {
+	*x = PackageSize{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackageSize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageSize) ProtoMessage() {}
+
+func (x *PackageSize) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageSize.ProtoReflect.Descriptor instead.
+func (*PackageSize) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PackageSize) GetPackage() int64 {
+	if x != nil {
+		return x.Package
+	}
+	return 0
+}
+
+func (x *PackageSize) GetInstalled() int64 {
+	if x != nil {
+		return x.Installed
+	}
+	return 0
+}
+
+func (x *PackageSize) GetArchive() int64 {
+	if x != nil {
+		return x.Archive
+	}
+	return 0
+}
+
+// Package is a package from the primary metadata of a repository
+type Package struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Arch          string                 `protobuf:"bytes,3,opt,name=arch,proto3" json:"arch,omitempty"`
+	Version       *Version               `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Checksum      *Checksum              `protobuf:"bytes,5,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Summary       string                 `protobuf:"bytes,6,opt,name=summary,proto3" json:"summary,omitempty"`
+	Size          *PackageSize           `protobuf:"bytes,7,opt,name=size,proto3" json:"size,omitempty"`
+	Location      string                 `protobuf:"bytes,8,opt,name=location,proto3" json:"location,omitempty"`
+	License       string                 `protobuf:"bytes,9,opt,name=license,proto3" json:"license,omitempty"`
+	SourceRpm     string                 `protobuf:"bytes,10,opt,name=source_rpm,json=sourceRpm,proto3" json:"source_rpm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Package) Reset() {
+	*x = Package{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Package) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Package) ProtoMessage() {}
+
+func (x *Package) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Package.ProtoReflect.Descriptor instead.
+func (*Package) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Package) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Package) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Package) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *Package) GetVersion() *Version {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *Package) GetChecksum() *Checksum {
+	if x != nil {
+		return x.Checksum
+	}
+	return nil
+}
+
+func (x *Package) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Package) GetSize() *PackageSize {
+	if x != nil {
+		return x.Size
+	}
+	return nil
+}
+
+func (x *Package) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Package) GetLicense() string {
+	if x != nil {
+		return x.License
+	}
+	return ""
+}
+
+func (x *Package) GetSourceRpm() string {
+	if x != nil {
+		return x.SourceRpm
+	}
+	return ""
+}
+
+type Reference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reference) Reset() {
+	*x = Reference{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reference) ProtoMessage() {}
+
+func (x *Reference) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reference.ProtoReflect.Descriptor instead.
+func (*Reference) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Reference) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Reference) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Reference) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Reference) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type AdvisoryPackage struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Epoch           int32                  `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Version         string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Release         string                 `protobuf:"bytes,4,opt,name=release,proto3" json:"release,omitempty"`
+	Arch            string                 `protobuf:"bytes,5,opt,name=arch,proto3" json:"arch,omitempty"`
+	Src             string                 `protobuf:"bytes,6,opt,name=src,proto3" json:"src,omitempty"`
+	Filename        string                 `protobuf:"bytes,7,opt,name=filename,proto3" json:"filename,omitempty"`
+	Checksums       []*Checksum            `protobuf:"bytes,8,rep,name=checksums,proto3" json:"checksums,omitempty"`
+	RebootSuggested bool                   `protobuf:"varint,9,opt,name=reboot_suggested,json=rebootSuggested,proto3" json:"reboot_suggested,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AdvisoryPackage) Reset() {
+	*x = AdvisoryPackage{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdvisoryPackage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdvisoryPackage) ProtoMessage() {}
+
+func (x *AdvisoryPackage) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdvisoryPackage.ProtoReflect.Descriptor instead.
+func (*AdvisoryPackage) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AdvisoryPackage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AdvisoryPackage) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *AdvisoryPackage) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *AdvisoryPackage) GetRelease() string {
+	if x != nil {
+		return x.Release
+	}
+	return ""
+}
+
+func (x *AdvisoryPackage) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *AdvisoryPackage) GetSrc() string {
+	if x != nil {
+		return x.Src
+	}
+	return ""
+}
+
+func (x *AdvisoryPackage) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *AdvisoryPackage) GetChecksums() []*Checksum {
+	if x != nil {
+		return x.Checksums
+	}
+	return nil
+}
+
+func (x *AdvisoryPackage) GetRebootSuggested() bool {
+	if x != nil {
+		return x.RebootSuggested
+	}
+	return false
+}
+
+// Advisory is an erratum from the updateinfo metadata of a repository
+type Advisory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	From          string                 `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	Version       string                 `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	Title         string                 `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	Severity      Severity               `protobuf:"varint,7,opt,name=severity,proto3,enum=yummy.v1.Severity" json:"severity,omitempty"`
+	Release       string                 `protobuf:"bytes,8,opt,name=release,proto3" json:"release,omitempty"`
+	Issued        string                 `protobuf:"bytes,9,opt,name=issued,proto3" json:"issued,omitempty"`
+	Updated       string                 `protobuf:"bytes,10,opt,name=updated,proto3" json:"updated,omitempty"`
+	Rights        string                 `protobuf:"bytes,11,opt,name=rights,proto3" json:"rights,omitempty"`
+	Summary       string                 `protobuf:"bytes,12,opt,name=summary,proto3" json:"summary,omitempty"`
+	Description   string                 `protobuf:"bytes,13,opt,name=description,proto3" json:"description,omitempty"`
+	Solution      string                 `protobuf:"bytes,14,opt,name=solution,proto3" json:"solution,omitempty"`
+	References    []*Reference           `protobuf:"bytes,15,rep,name=references,proto3" json:"references,omitempty"`
+	Packages      []*AdvisoryPackage     `protobuf:"bytes,16,rep,name=packages,proto3" json:"packages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Advisory) Reset() {
+	*x = Advisory{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Advisory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Advisory) ProtoMessage() {}
+
+func (x *Advisory) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Advisory.ProtoReflect.Descriptor instead.
+func (*Advisory) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Advisory) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Advisory) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Advisory) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Advisory) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Advisory) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Advisory) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Advisory) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_NONE
+}
+
+func (x *Advisory) GetRelease() string {
+	if x != nil {
+		return x.Release
+	}
+	return ""
+}
+
+func (x *Advisory) GetIssued() string {
+	if x != nil {
+		return x.Issued
+	}
+	return ""
+}
+
+func (x *Advisory) GetUpdated() string {
+	if x != nil {
+		return x.Updated
+	}
+	return ""
+}
+
+func (x *Advisory) GetRights() string {
+	if x != nil {
+		return x.Rights
+	}
+	return ""
+}
+
+func (x *Advisory) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Advisory) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Advisory) GetSolution() string {
+	if x != nil {
+		return x.Solution
+	}
+	return ""
+}
+
+func (x *Advisory) GetReferences() []*Reference {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *Advisory) GetPackages() []*AdvisoryPackage {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+type StreamNames struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Streams       []string               `protobuf:"bytes,1,rep,name=streams,proto3" json:"streams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamNames) Reset() {
+	*x = StreamNames{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamNames) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamNames) ProtoMessage() {}
+
+func (x *StreamNames) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamNames.ProtoReflect.Descriptor instead.
+func (*StreamNames) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamNames) GetStreams() []string {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+// Dependencies lists the streams of other modules needed to build and run a stream, keyed by module name
+type Dependencies struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	BuildRequires map[string]*StreamNames `protobuf:"bytes,1,rep,name=build_requires,json=buildRequires,proto3" json:"build_requires,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Requires      map[string]*StreamNames `protobuf:"bytes,2,rep,name=requires,proto3" json:"requires,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Dependencies) Reset() {
+	*x = Dependencies{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dependencies) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dependencies) ProtoMessage() {}
+
+func (x *Dependencies) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dependencies.ProtoReflect.Descriptor instead.
+func (*Dependencies) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Dependencies) GetBuildRequires() map[string]*StreamNames {
+	if x != nil {
+		return x.BuildRequires
+	}
+	return nil
+}
+
+func (x *Dependencies) GetRequires() map[string]*StreamNames {
+	if x != nil {
+		return x.Requires
+	}
+	return nil
+}
+
+type License struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Module        []string               `protobuf:"bytes,1,rep,name=module,proto3" json:"module,omitempty"`
+	Content       []string               `protobuf:"bytes,2,rep,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *License) Reset() {
+	*x = License{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *License) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*License) ProtoMessage() {}
+
+func (x *License) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use License.ProtoReflect.Descriptor instead.
+func (*License) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *License) GetModule() []string {
+	if x != nil {
+		return x.Module
+	}
+	return nil
+}
+
+func (x *License) GetContent() []string {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type RpmProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rpms          []string               `protobuf:"bytes,1,rep,name=rpms,proto3" json:"rpms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RpmProfile) Reset() {
+	*x = RpmProfile{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RpmProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RpmProfile) ProtoMessage() {}
+
+func (x *RpmProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RpmProfile.ProtoReflect.Descriptor instead.
+func (*RpmProfile) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RpmProfile) GetRpms() []string {
+	if x != nil {
+		return x.Rpms
+	}
+	return nil
+}
+
+// Stream is a module stream. Components and the rpm map of the artifacts are not included.
+type Stream struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Stream        string                 `protobuf:"bytes,2,opt,name=stream,proto3" json:"stream,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Context       string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	Arch          string                 `protobuf:"bytes,5,opt,name=arch,proto3" json:"arch,omitempty"`
+	Summary       string                 `protobuf:"bytes,6,opt,name=summary,proto3" json:"summary,omitempty"`
+	Description   string                 `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	StaticContext bool                   `protobuf:"varint,8,opt,name=static_context,json=staticContext,proto3" json:"static_context,omitempty"`
+	License       *License               `protobuf:"bytes,9,opt,name=license,proto3" json:"license,omitempty"`
+	Dependencies  []*Dependencies        `protobuf:"bytes,10,rep,name=dependencies,proto3" json:"dependencies,omitempty"`
+	Profiles      map[string]*RpmProfile `protobuf:"bytes,11,rep,name=profiles,proto3" json:"profiles,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ArtifactRpms  []string               `protobuf:"bytes,12,rep,name=artifact_rpms,json=artifactRpms,proto3" json:"artifact_rpms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stream) Reset() {
+	*x = Stream{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stream) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stream) ProtoMessage() {}
+
+func (x *Stream) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stream.ProtoReflect.Descriptor instead.
+func (*Stream) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Stream) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Stream) GetStream() string {
+	if x != nil {
+		return x.Stream
+	}
+	return ""
+}
+
+func (x *Stream) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Stream) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *Stream) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *Stream) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Stream) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Stream) GetStaticContext() bool {
+	if x != nil {
+		return x.StaticContext
+	}
+	return false
+}
+
+func (x *Stream) GetLicense() *License {
+	if x != nil {
+		return x.License
+	}
+	return nil
+}
+
+func (x *Stream) GetDependencies() []*Dependencies {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+func (x *Stream) GetProfiles() map[string]*RpmProfile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+func (x *Stream) GetArtifactRpms() []string {
+	if x != nil {
+		return x.ArtifactRpms
+	}
+	return nil
+}
+
+// ModuleStream is a module with its streams
+type ModuleStream struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Streams       []*Stream              `protobuf:"bytes,2,rep,name=streams,proto3" json:"streams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModuleStream) Reset() {
+	*x = ModuleStream{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModuleStream) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleStream) ProtoMessage() {}
+
+func (x *ModuleStream) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleStream.ProtoReflect.Descriptor instead.
+func (*ModuleStream) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ModuleStream) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ModuleStream) GetStreams() []*Stream {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+// LocalizedString is a text with its translations keyed by locale
+type LocalizedString struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Translations  map[string]string      `protobuf:"bytes,2,rep,name=translations,proto3" json:"translations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocalizedString) Reset() {
+	*x = LocalizedString{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocalizedString) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocalizedString) ProtoMessage() {}
+
+func (x *LocalizedString) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocalizedString.ProtoReflect.Descriptor instead.
+func (*LocalizedString) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LocalizedString) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *LocalizedString) GetTranslations() map[string]string {
+	if x != nil {
+		return x.Translations
+	}
+	return nil
+}
+
+type PackageReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Requires      string                 `protobuf:"bytes,3,opt,name=requires,proto3" json:"requires,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackageReq) Reset() {
+	*x = PackageReq{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackageReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageReq) ProtoMessage() {}
+
+func (x *PackageReq) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageReq.ProtoReflect.Descriptor instead.
+func (*PackageReq) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PackageReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PackageReq) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PackageReq) GetRequires() string {
+	if x != nil {
+		return x.Requires
+	}
+	return ""
+}
+
+// PackageGroup is a package group from the comps metadata of a repository
+type PackageGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *LocalizedString       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   *LocalizedString       `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Default       bool                   `protobuf:"varint,4,opt,name=default,proto3" json:"default,omitempty"`
+	UserVisible   bool                   `protobuf:"varint,5,opt,name=user_visible,json=userVisible,proto3" json:"user_visible,omitempty"`
+	DisplayOrder  int32                  `protobuf:"varint,6,opt,name=display_order,json=displayOrder,proto3" json:"display_order,omitempty"`
+	BiarchOnly    bool                   `protobuf:"varint,7,opt,name=biarch_only,json=biarchOnly,proto3" json:"biarch_only,omitempty"`
+	PackageList   []*PackageReq          `protobuf:"bytes,8,rep,name=package_list,json=packageList,proto3" json:"package_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackageGroup) Reset() {
+	*x = PackageGroup{}
+	mi := &file_yummy_v1_yummy_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackageGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageGroup) ProtoMessage() {}
+
+func (x *PackageGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_yummy_v1_yummy_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageGroup.ProtoReflect.Descriptor instead.
+func (*PackageGroup) Descriptor() ([]byte, []int) {
+	return file_yummy_v1_yummy_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PackageGroup) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PackageGroup) GetName() *LocalizedString {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *PackageGroup) GetDescription() *LocalizedString {
+	if x != nil {
+		return x.Description
+	}
+	return nil
+}
+
+func (x *PackageGroup) GetDefault() bool {
+	if x != nil {
+		return x.Default
+	}
+	return false
+}
+
+func (x *PackageGroup) GetUserVisible() bool {
+	if x != nil {
+		return x.UserVisible
+	}
+	return false
+}
+
+func (x *PackageGroup) GetDisplayOrder() int32 {
+	if x != nil {
+		return x.DisplayOrder
+	}
+	return 0
+}
+
+func (x *PackageGroup) GetBiarchOnly() bool {
+	if x != nil {
+		return x.BiarchOnly
+	}
+	return false
+}
+
+func (x *PackageGroup) GetPackageList() []*PackageReq {
+	if x != nil {
+		return x.PackageList
+	}
+	return nil
+}
+
+var File_yummy_v1_yummy_proto protoreflect.FileDescriptor
+
+const file_yummy_v1_yummy_proto_rawDesc = "" +
+	"\n" +
+	"\x14yummy/v1/yummy.proto\x12\byummy.v1\"S\n" +
+	"\aVersion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x18\n" +
+	"\arelease\x18\x02 \x01(\tR\arelease\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x05R\x05epoch\"4\n" +
+	"\bChecksum\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"_\n" +
+	"\vPackageSize\x12\x18\n" +
+	"\apackage\x18\x01 \x01(\x03R\apackage\x12\x1c\n" +
+	"\tinstalled\x18\x02 \x01(\x03R\tinstalled\x12\x18\n" +
+	"\aarchive\x18\x03 \x01(\x03R\aarchive\"\xbc\x02\n" +
+	"\aPackage\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04arch\x18\x03 \x01(\tR\x04arch\x12+\n" +
+	"\aversion\x18\x04 \x01(\v2\x11.yummy.v1.VersionR\aversion\x12.\n" +
+	"\bchecksum\x18\x05 \x01(\v2\x12.yummy.v1.ChecksumR\bchecksum\x12\x18\n" +
+	"\asummary\x18\x06 \x01(\tR\asummary\x12)\n" +
+	"\x04size\x18\a \x01(\v2\x15.yummy.v1.PackageSizeR\x04size\x12\x1a\n" +
+	"\blocation\x18\b \x01(\tR\blocation\x12\x18\n" +
+	"\alicense\x18\t \x01(\tR\alicense\x12\x1d\n" +
+	"\n" +
+	"source_rpm\x18\n" +
+	" \x01(\tR\tsourceRpm\"W\n" +
+	"\tReference\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\"\x8e\x02\n" +
+	"\x0fAdvisoryPackage\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05epoch\x18\x02 \x01(\x05R\x05epoch\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x18\n" +
+	"\arelease\x18\x04 \x01(\tR\arelease\x12\x12\n" +
+	"\x04arch\x18\x05 \x01(\tR\x04arch\x12\x10\n" +
+	"\x03src\x18\x06 \x01(\tR\x03src\x12\x1a\n" +
+	"\bfilename\x18\a \x01(\tR\bfilename\x120\n" +
+	"\tchecksums\x18\b \x03(\v2\x12.yummy.v1.ChecksumR\tchecksums\x12)\n" +
+	"\x10reboot_suggested\x18\t \x01(\bR\x0frebootSuggested\"\xe2\x03\n" +
+	"\bAdvisory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\tR\x04from\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\tR\aversion\x12\x14\n" +
+	"\x05title\x18\x06 \x01(\tR\x05title\x12.\n" +
+	"\bseverity\x18\a \x01(\x0e2\x12.yummy.v1.SeverityR\bseverity\x12\x18\n" +
+	"\arelease\x18\b \x01(\tR\arelease\x12\x16\n" +
+	"\x06issued\x18\t \x01(\tR\x06issued\x12\x18\n" +
+	"\aupdated\x18\n" +
+	" \x01(\tR\aupdated\x12\x16\n" +
+	"\x06rights\x18\v \x01(\tR\x06rights\x12\x18\n" +
+	"\asummary\x18\f \x01(\tR\asummary\x12 \n" +
+	"\vdescription\x18\r \x01(\tR\vdescription\x12\x1a\n" +
+	"\bsolution\x18\x0e \x01(\tR\bsolution\x123\n" +
+	"\n" +
+	"references\x18\x0f \x03(\v2\x13.yummy.v1.ReferenceR\n" +
+	"references\x125\n" +
+	"\bpackages\x18\x10 \x03(\v2\x19.yummy.v1.AdvisoryPackageR\bpackages\"'\n" +
+	"\vStreamNames\x12\x18\n" +
+	"\astreams\x18\x01 \x03(\tR\astreams\"\xcf\x02\n" +
+	"\fDependencies\x12P\n" +
+	"\x0ebuild_requires\x18\x01 \x03(\v2).yummy.v1.Dependencies.BuildRequiresEntryR\rbuildRequires\x12@\n" +
+	"\brequires\x18\x02 \x03(\v2$.yummy.v1.Dependencies.RequiresEntryR\brequires\x1aW\n" +
+	"\x12BuildRequiresEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.yummy.v1.StreamNamesR\x05value:\x028\x01\x1aR\n" +
+	"\rRequiresEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.yummy.v1.StreamNamesR\x05value:\x028\x01\";\n" +
+	"\aLicense\x12\x16\n" +
+	"\x06module\x18\x01 \x03(\tR\x06module\x12\x18\n" +
+	"\acontent\x18\x02 \x03(\tR\acontent\" \n" +
+	"\n" +
+	"RpmProfile\x12\x12\n" +
+	"\x04rpms\x18\x01 \x03(\tR\x04rpms\"\xfc\x03\n" +
+	"\x06Stream\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06stream\x18\x02 \x01(\tR\x06stream\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x18\n" +
+	"\acontext\x18\x04 \x01(\tR\acontext\x12\x12\n" +
+	"\x04arch\x18\x05 \x01(\tR\x04arch\x12\x18\n" +
+	"\asummary\x18\x06 \x01(\tR\asummary\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x12%\n" +
+	"\x0estatic_context\x18\b \x01(\bR\rstaticContext\x12+\n" +
+	"\alicense\x18\t \x01(\v2\x11.yummy.v1.LicenseR\alicense\x12:\n" +
+	"\fdependencies\x18\n" +
+	" \x03(\v2\x16.yummy.v1.DependenciesR\fdependencies\x12:\n" +
+	"\bprofiles\x18\v \x03(\v2\x1e.yummy.v1.Stream.ProfilesEntryR\bprofiles\x12#\n" +
+	"\rartifact_rpms\x18\f \x03(\tR\fartifactRpms\x1aQ\n" +
+	"\rProfilesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.yummy.v1.RpmProfileR\x05value:\x028\x01\"N\n" +
+	"\fModuleStream\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12*\n" +
+	"\astreams\x18\x02 \x03(\v2\x10.yummy.v1.StreamR\astreams\"\xb9\x01\n" +
+	"\x0fLocalizedString\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12O\n" +
+	"\ftranslations\x18\x02 \x03(\v2+.yummy.v1.LocalizedString.TranslationsEntryR\ftranslations\x1a?\n" +
+	"\x11TranslationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"P\n" +
+	"\n" +
+	"PackageReq\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1a\n" +
+	"\brequires\x18\x03 \x01(\tR\brequires\"\xc6\x02\n" +
+	"\fPackageGroup\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12-\n" +
+	"\x04name\x18\x02 \x01(\v2\x19.yummy.v1.LocalizedStringR\x04name\x12;\n" +
+	"\vdescription\x18\x03 \x01(\v2\x19.yummy.v1.LocalizedStringR\vdescription\x12\x18\n" +
+	"\adefault\x18\x04 \x01(\bR\adefault\x12!\n" +
+	"\fuser_visible\x18\x05 \x01(\bR\vuserVisible\x12#\n" +
+	"\rdisplay_order\x18\x06 \x01(\x05R\fdisplayOrder\x12\x1f\n" +
+	"\vbiarch_only\x18\a \x01(\bR\n" +
+	"biarchOnly\x127\n" +
+	"\fpackage_list\x18\b \x03(\v2\x14.yummy.v1.PackageReqR\vpackageList*u\n" +
+	"\bSeverity\x12\x11\n" +
+	"\rSEVERITY_NONE\x10\x00\x12\x10\n" +
+	"\fSEVERITY_LOW\x10\x01\x12\x15\n" +
+	"\x11SEVERITY_MODERATE\x10\x02\x12\x16\n" +
+	"\x12SEVERITY_IMPORTANT\x10\x03\x12\x15\n" +
+	"\x11SEVERITY_CRITICAL\x10\x04B-Z+github.com/content-services/yummy/pkg/yumpbb\x06proto3"
+
+var (
+	file_yummy_v1_yummy_proto_rawDescOnce sync.Once
+	file_yummy_v1_yummy_proto_rawDescData []byte
+)
+
+func file_yummy_v1_yummy_proto_rawDescGZIP() []byte {
+	file_yummy_v1_yummy_proto_rawDescOnce.Do(func() {
+		file_yummy_v1_yummy_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_yummy_v1_yummy_proto_rawDesc), len(file_yummy_v1_yummy_proto_rawDesc)))
+	})
+	return file_yummy_v1_yummy_proto_rawDescData
+}
+
+var file_yummy_v1_yummy_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_yummy_v1_yummy_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_yummy_v1_yummy_proto_goTypes = []any{
+	(Severity)(0),           // 0: yummy.v1.Severity
+	(*Version)(nil),         // 1: yummy.v1.Version
+	(*Checksum)(nil),        // 2: yummy.v1.Checksum
+	(*PackageSize)(nil),     // 3: yummy.v1.PackageSize
+	(*Package)(nil),         // 4: yummy.v1.Package
+	(*Reference)(nil),       // 5: yummy.v1.Reference
+	(*AdvisoryPackage)(nil), // 6: yummy.v1.AdvisoryPackage
+	(*Advisory)(nil),        // 7: yummy.v1.Advisory
+	(*StreamNames)(nil),     // 8: yummy.v1.StreamNames
+	(*Dependencies)(nil),    // 9: yummy.v1.Dependencies
+	(*License)(nil),         // 10: yummy.v1.License
+	(*RpmProfile)(nil),      // 11: yummy.v1.RpmProfile
+	(*Stream)(nil),          // 12: yummy.v1.Stream
+	(*ModuleStream)(nil),    // 13: yummy.v1.ModuleStream
+	(*LocalizedString)(nil), // 14: yummy.v1.LocalizedString
+	(*PackageReq)(nil),      // 15: yummy.v1.PackageReq
+	(*PackageGroup)(nil),    // 16: yummy.v1.PackageGroup
+	nil,                     // 17: yummy.v1.Dependencies.BuildRequiresEntry
+	nil,                     // 18: yummy.v1.Dependencies.RequiresEntry
+	nil,                     // 19: yummy.v1.Stream.ProfilesEntry
+	nil,                     // 20: yummy.v1.LocalizedString.TranslationsEntry
+}
+var file_yummy_v1_yummy_proto_depIdxs = []int32{
+	1,  // 0: yummy.v1.Package.version:type_name -> yummy.v1.Version
+	2,  // 1: yummy.v1.Package.checksum:type_name -> yummy.v1.Checksum
+	3,  // 2: yummy.v1.Package.size:type_name -> yummy.v1.PackageSize
+	2,  // 3: yummy.v1.AdvisoryPackage.checksums:type_name -> yummy.v1.Checksum
+	0,  // 4: yummy.v1.Advisory.severity:type_name -> yummy.v1.Severity
+	5,  // 5: yummy.v1.Advisory.references:type_name -> yummy.v1.Reference
+	6,  // 6: yummy.v1.Advisory.packages:type_name -> yummy.v1.AdvisoryPackage
+	17, // 7: yummy.v1.Dependencies.build_requires:type_name -> yummy.v1.Dependencies.BuildRequiresEntry
+	18, // 8: yummy.v1.Dependencies.requires:type_name -> yummy.v1.Dependencies.RequiresEntry
+	10, // 9: yummy.v1.Stream.license:type_name -> yummy.v1.License
+	9,  // 10: yummy.v1.Stream.dependencies:type_name -> yummy.v1.Dependencies
+	19, // 11: yummy.v1.Stream.profiles:type_name -> yummy.v1.Stream.ProfilesEntry
+	12, // 12: yummy.v1.ModuleStream.streams:type_name -> yummy.v1.Stream
+	20, // 13: yummy.v1.LocalizedString.translations:type_name -> yummy.v1.LocalizedString.TranslationsEntry
+	14, // 14: yummy.v1.PackageGroup.name:type_name -> yummy.v1.LocalizedString
+	14, // 15: yummy.v1.PackageGroup.description:type_name -> yummy.v1.LocalizedString
+	15, // 16: yummy.v1.PackageGroup.package_list:type_name -> yummy.v1.PackageReq
+	8,  // 17: yummy.v1.Dependencies.BuildRequiresEntry.value:type_name -> yummy.v1.StreamNames
+	8,  // 18: yummy.v1.Dependencies.RequiresEntry.value:type_name -> yummy.v1.StreamNames
+	11, // 19: yummy.v1.Stream.ProfilesEntry.value:type_name -> yummy.v1.RpmProfile
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_yummy_v1_yummy_proto_init() }
+func file_yummy_v1_yummy_proto_init() {
+	if File_yummy_v1_yummy_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_yummy_v1_yummy_proto_rawDesc), len(file_yummy_v1_yummy_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_yummy_v1_yummy_proto_goTypes,
+		DependencyIndexes: file_yummy_v1_yummy_proto_depIdxs,
+		EnumInfos:         file_yummy_v1_yummy_proto_enumTypes,
+		MessageInfos:      file_yummy_v1_yummy_proto_msgTypes,
+	}.Build()
+	File_yummy_v1_yummy_proto = out.File
+	file_yummy_v1_yummy_proto_goTypes = nil
+	file_yummy_v1_yummy_proto_depIdxs = nil
+}